@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -13,9 +13,18 @@ import (
 	"os"
 
 	acciping "github.com/Lexer747/acci-ping/cmd/subcommands/acci-ping"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/analyse"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/compare"
 	"github.com/Lexer747/acci-ping/cmd/subcommands/drawframe"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/heatmap"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/histogram"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/importer"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/inspect"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/merge"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/outages"
 	"github.com/Lexer747/acci-ping/cmd/subcommands/ping"
 	"github.com/Lexer747/acci-ping/cmd/subcommands/rawdata"
+	"github.com/Lexer747/acci-ping/cmd/subcommands/slice"
 	"github.com/Lexer747/acci-ping/cmd/subcommands/version"
 	tabcompletion "github.com/Lexer747/acci-ping/cmd/tab_completion"
 	"github.com/Lexer747/acci-ping/terminal/ansi"
@@ -39,9 +48,18 @@ var (
 
 var programName = ansi.Green("acci-ping")
 
+const analyseString = "analyse"
+const compareString = "compare"
 const drawframeString = "drawframe"
+const heatmapString = "heatmap"
+const histogramString = "histogram"
+const importString = "import"
+const inspectString = "inspect"
+const mergeString = "merge"
+const outagesString = "outages"
 const rawdataString = "rawdata"
 const pingString = "ping"
+const sliceString = "slice"
 const versionString = "version"
 
 type subcommand struct {
@@ -50,11 +68,51 @@ type subcommand struct {
 }
 
 var commandsUsage = []subcommand{
+	{
+		subcommandName: ansi.Red(analyseString),
+		description: programName + " " + ansi.Red(analyseString) +
+			" -periodicity [file...]\n    will find the latency patterns which repeat (e.g. a backup job every 10 minutes) in .pings files.",
+	},
+	{
+		subcommandName: ansi.Red(compareString),
+		description: programName + " " + ansi.Red(compareString) +
+			" [before] [after]\n    will compare two .pings files side by side and test if the latency is significantly different.",
+	},
 	{
 		subcommandName: ansi.Red(drawframeString),
 		description: programName + " " + ansi.Red(drawframeString) +
 			" [file|folder]\n    will draw a single frame of the graph for a given .pings file, or folder of .pings files.",
 	},
+	{
+		subcommandName: ansi.Red(heatmapString),
+		description: programName + " " + ansi.Red(heatmapString) +
+			" [-loss] [file...]\n    will draw a calendar of .pings files, a row per day and a column per hour, coloured by p95 latency or loss.",
+	},
+	{
+		subcommandName: ansi.Red(histogramString),
+		description: programName + " " + ansi.Red(histogramString) +
+			" [-log] [file...]\n    will draw the latency distribution of .pings files as a histogram with percentile markers.",
+	},
+	{
+		subcommandName: ansi.Red(importString),
+		description: programName + " " + ansi.Red(importString) +
+			" -out [file] [file]\n    will convert a CSV (from rawdata -csv), linux ping or windows ping log into a .pings file.",
+	},
+	{
+		subcommandName: ansi.Red(inspectString),
+		description: programName + " " + ansi.Red(inspectString) +
+			" [-json] [file...]\n    will print the byte layout of .pings files and report any inconsistencies, for debugging the format.",
+	},
+	{
+		subcommandName: ansi.Red(mergeString),
+		description: programName + " " + ansi.Red(mergeString) +
+			" -out [file] [file...]\n    will merge many .pings files of the same url into a single .pings file.",
+	},
+	{
+		subcommandName: ansi.Red(outagesString),
+		description: programName + " " + ansi.Red(outagesString) +
+			" [-json] [file...]\n    will list every outage in .pings files with the uptime, MTBF and MTTR.",
+	},
 	{
 		subcommandName: ansi.Red(rawdataString),
 		description: programName + " " + ansi.Red(rawdataString) +
@@ -65,6 +123,11 @@ var commandsUsage = []subcommand{
 		description: programName + " " + ansi.Red(pingString) +
 			" will run like any other ping command line tool and print the plain text packet statistics to stdout.",
 	},
+	{
+		subcommandName: ansi.Red(sliceString),
+		description: programName + " " + ansi.Red(sliceString) +
+			" -out [file] [file]\n    will write the points of a .pings file within a time range (-from, -to) into a new .pings file.",
+	},
 	{
 		subcommandName: ansi.Red(versionString),
 		description: programName + " " + ansi.Red(versionString) +
@@ -78,17 +141,58 @@ var mainDescription = programName + " can run be with no arguments to start the
 func main() {
 	info := application.MakeBuildInfo(COMMIT, GO_VERSION, BRANCH, TIMESTAMP, TAG)
 	a := acciping.GetFlags(info)
+	an := analyse.GetFlags()
+	co := compare.GetFlags()
 	df := drawframe.GetFlags(info)
+	he := heatmap.GetFlags()
+	hi := histogram.GetFlags()
+	i := importer.GetFlags()
+	in := inspect.GetFlags()
+	m := merge.GetFlags()
+	o := outages.GetFlags()
 	rd := rawdata.GetFlags()
 	p := ping.GetFlags()
+	s := slice.GetFlags()
 	v := version.GetFlags(info)
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
+		case analyseString:
+			flagParseError(an.Parse(os.Args[2:]))
+			analyse.RunAnalyse(an)
+			exit.Success()
+		case compareString:
+			flagParseError(co.Parse(os.Args[2:]))
+			compare.RunCompare(co)
+			exit.Success()
 		case drawframeString:
 			flagParseError(df.Parse(os.Args[2:]))
 			PrintHelpDebugIfNeeded(df.HelpDebug(), df.FlagSet.FlagSet)
 			drawframe.RunDrawFrame(df)
 			exit.Success()
+		case heatmapString:
+			flagParseError(he.Parse(os.Args[2:]))
+			heatmap.RunHeatmap(he)
+			exit.Success()
+		case histogramString:
+			flagParseError(hi.Parse(os.Args[2:]))
+			histogram.RunHistogram(hi)
+			exit.Success()
+		case importString:
+			flagParseError(i.Parse(os.Args[2:]))
+			importer.RunImport(i)
+			exit.Success()
+		case inspectString:
+			flagParseError(in.Parse(os.Args[2:]))
+			inspect.RunInspect(in)
+			exit.Success()
+		case mergeString:
+			flagParseError(m.Parse(os.Args[2:]))
+			merge.RunMerge(m)
+			exit.Success()
+		case outagesString:
+			flagParseError(o.Parse(os.Args[2:]))
+			outages.RunOutages(o)
+			exit.Success()
 		case rawdataString:
 			flagParseError(rd.Parse(os.Args[2:]))
 			rawdata.RunPrintData(rd)
@@ -97,6 +201,10 @@ func main() {
 			flagParseError(p.Parse(os.Args[2:]))
 			ping.RunPing(p)
 			exit.Success()
+		case sliceString:
+			flagParseError(s.Parse(os.Args[2:]))
+			slice.RunSlice(s)
+			exit.Success()
 		case versionString:
 			flagParseError(v.Parse(os.Args[2:]))
 			version.RunVersion(v)
@@ -106,9 +214,18 @@ func main() {
 				os.Args,
 				tabcompletion.Command{Cmd: os.Args[0], Fs: a.FlagSet},
 				[]tabcompletion.Command{
+					{Cmd: analyseString, Fs: an.FlagSet},
+					{Cmd: compareString, Fs: co.FlagSet},
 					{Cmd: drawframeString, Fs: df.FlagSet},
+					{Cmd: heatmapString, Fs: he.FlagSet},
+					{Cmd: histogramString, Fs: hi.FlagSet},
+					{Cmd: importString, Fs: i.FlagSet},
+					{Cmd: inspectString, Fs: in.FlagSet},
+					{Cmd: mergeString, Fs: m.FlagSet},
+					{Cmd: outagesString, Fs: o.FlagSet},
 					{Cmd: rawdataString, Fs: rd.FlagSet},
 					{Cmd: pingString, Fs: p.FlagSet},
+					{Cmd: sliceString, Fs: s.FlagSet},
 					{Cmd: versionString, Fs: v.FlagSet},
 				},
 			)
@@ -32,8 +32,12 @@ type Config struct {
 	followingOnStart   *bool
 	hideHelpOnStart    *bool
 	logarithmicOnStart *bool
+	multiTarget        *bool
 	pingBufferingLimit *int
 	pingsPerMinute     *float64
+	retentionDays      *int
+	retentionMode      *string
+	rotateSizeMB       *int
 	testErrorListener  *bool
 	theme              *string
 	url                *string
@@ -48,9 +52,20 @@ func GetFlags(info *application.BuildInfo) *Config {
 		SharedFlags: sf,
 		FlagSet:     tf,
 
-		filePath: tf.String("file", "", "the file to write the pings into. (default data not saved)",
+		filePath: tf.String("file", "", "the file to write the pings into. (default data not saved)\n"+
+			"The file name can contain the date verbs %Y, %m, %d and %H e.g. \"captures/%Y-%m-%d.pings\",\n"+
+			"the capture moves on to a new file whenever the date in the file name changes.",
 			tabflags.AutoComplete{WantsFile: true, FileExt: ".pings"}),
-		hideHelpOnStart:    tf.Bool("hide-help", false, "if this flag is used the help box will be hidden by default"),
+		rotateSizeMB: tf.Int("rotate-size-mb", 0, "the capture moves on to a new -file once the current one reaches this many megabytes,\n"+
+			"0 means no limit."),
+		retentionDays: tf.Int("retention-days", 0, "files of the -file template which haven't been written to for this many days\n"+
+			"have the -retention applied, 0 keeps files forever."),
+		retentionMode: tf.String("retention", "downsample", "what to do with files older than -retention-days, either 'downsample'\n"+
+			"(only keep the fastest, slowest and dropped packets of each minute) or 'delete'.",
+			tabflags.AutoComplete{Choices: []string{"downsample", "delete"}}),
+		hideHelpOnStart: tf.Bool("hide-help", false, "if this flag is used the help box will be hidden by default"),
+		multiTarget: tf.Bool("multi-target", false, "allows the -file to hold the pings of many urls, if the -file doesn't\n"+
+			"already hold the -url then it's added to the file rather than failing."),
 		pingBufferingLimit: new(int),
 		pingsPerMinute: tf.Float64("pings-per-minute", 60.0,
 			"sets the speed at which the program will try to get new ping results, 0 represents no limit.\n"+
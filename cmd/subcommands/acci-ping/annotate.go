@@ -0,0 +1,163 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+// maxAnnotationLength is the longest note (in runes) a user can type, annotations are meant to be short and
+// are drawn as labels on the graph.
+const maxAnnotationLength = 64
+
+// annotationInput is the state of the text prompt which is opened by the user to type an annotation. It should
+// only be modified by the terminal listener thread, the prompt drawing happens on it's own thread via
+// [Application.showAnnotationPrompt].
+type annotationInput struct {
+	text   []rune
+	typing bool
+}
+
+// annotationPrompt is a snapshot of [annotationInput] sent to the drawing thread.
+type annotationPrompt struct {
+	text   string
+	typing bool
+}
+
+func (ai *annotationInput) prompt() annotationPrompt {
+	return annotationPrompt{text: string(ai.text), typing: ai.typing}
+}
+
+// showAnnotationPrompt which should only be called once the paint buffer is initialised.
+func (app *Application) showAnnotationPrompt(
+	ctx context.Context,
+	promptChannel <-chan annotationPrompt,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	buffer := app.drawBuffer.Get(draw.InputIndex)
+	p := annotationPrompt{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			app.GUIState.Paint(p.render(newSize, buffer))
+		case p = <-promptChannel:
+			app.GUIState.Paint(p.render(app.term.GetSize(), buffer))
+		}
+	}
+}
+
+// startAnnotation is the listener which opens the annotation prompt, while the prompt is open all other
+// listeners are disabled (see [Application.addListener]) and the input is instead handled by
+// [Application.typeAnnotation].
+func (app *Application) startAnnotation(promptChannel chan<- annotationPrompt) func(rune) error {
+	return func(rune) error {
+		app.annotation.typing = true
+		app.annotation.text = app.annotation.text[:0]
+		promptChannel <- app.annotation.prompt()
+		return nil
+	}
+}
+
+// typeAnnotation is the fallback listener which receives the users input while the annotation prompt is open.
+// Enter will save the annotation, escape will discard it.
+func (app *Application) typeAnnotation(
+	ctx context.Context,
+	promptChannel chan<- annotationPrompt,
+	fileAnnotations chan<- data.Annotation,
+) func(rune) error {
+	return func(r rune) error {
+		if !app.annotation.typing {
+			return nil
+		}
+		switch {
+		case r == '\r' || r == '\n':
+			app.annotation.typing = false
+			text := strings.TrimSpace(string(app.annotation.text))
+			if text != "" {
+				a := data.Annotation{Timestamp: time.Now(), Text: text}
+				go func() {
+					app.g.AddAnnotation(a)
+					if fileAnnotations == nil {
+						return
+					}
+					// The file writer stops receiving once the program is exiting.
+					select {
+					case <-ctx.Done():
+					case fileAnnotations <- a:
+					}
+				}()
+			}
+		case r == '\x1b':
+			app.annotation.typing = false
+		case r == '\x7f' || r == '\b':
+			if len(app.annotation.text) > 0 {
+				app.annotation.text = app.annotation.text[:len(app.annotation.text)-1]
+			}
+		case unicode.IsPrint(r) && len(app.annotation.text) < maxAnnotationLength:
+			app.annotation.text = append(app.annotation.text, r)
+		default:
+			return nil
+		}
+		promptChannel <- app.annotation.prompt()
+		return nil
+	}
+}
+
+// unlessTyping wraps a fallback listener so that it is not invoked while the annotation prompt is open.
+func (app *Application) unlessTyping(Action func(rune) error) func(rune) error {
+	return func(r rune) error {
+		if app.annotation.typing {
+			return nil
+		}
+		return Action(r)
+	}
+}
+
+func (p annotationPrompt) render(size terminal.Size, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	ret := gui.None
+	if buf.Len() != 0 {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	if !p.typing {
+		return ret
+	}
+	box := makeAnnotationBox(p.text)
+	box.Draw(size, buf)
+	return ret | gui.Paint
+}
+
+func makeAnnotationBox(text string) gui.Box {
+	return gui.Box{
+		BoxText: []gui.Typography{
+			{ToPrint: themes.Highlight("Annotation"), TextLen: 10, Alignment: gui.Centre},
+			{ToPrint: text + themes.Emphasis("_"), TextLen: len([]rune(text)) + 1, Alignment: gui.Left},
+			{
+				ToPrint: themes.Positive("enter") + themes.Primary(" to save, ") + themes.Negative("esc") + themes.Primary(" to cancel"),
+				TextLen: 5 + 10 + 3 + 10, Alignment: gui.Centre,
+			},
+		},
+		Position: gui.Position{
+			Vertical:   gui.Bottom,
+			Horizontal: gui.Centre,
+			Padding:    gui.Padding{Top: 1},
+		},
+		Style: gui.RoundedCorners,
+	}
+}
@@ -0,0 +1,125 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+// maxListedAnomalies is the most anomalies listed in the box, the most recent are kept.
+const maxListedAnomalies = 10
+
+// anomalies which should only be called once the paint buffer and graph are initialised. While shown the
+// box is redrawn as new anomalies are found.
+func (app *Application) anomalies(
+	ctx context.Context,
+	anomaliesChannel <-chan rune,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	anomaliesBuffer := app.drawBuffer.Get(draw.AnomalyListIndex)
+	a := anomaliesBox{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			app.GUIState.Paint(a.render(newSize, app.g.Anomalies(), anomaliesBuffer))
+		case <-ticker.C:
+			found := app.g.Anomalies()
+			if a.show && len(found) != a.drawnCount {
+				app.GUIState.Paint(a.render(app.term.GetSize(), found, anomaliesBuffer))
+			}
+		case toShow := <-anomaliesChannel:
+			switch toShow {
+			case 'a':
+				a.show = !a.show
+				app.GUIState.Paint(a.render(app.term.GetSize(), app.g.Anomalies(), anomaliesBuffer))
+			default:
+			}
+		}
+	}
+}
+
+type anomaliesBox struct {
+	show bool
+	// drawnCount is the number of anomalies when last drawn, there's no need to redraw until it changes.
+	drawnCount int
+}
+
+func (a *anomaliesBox) render(size terminal.Size, anomalies []data.Anomaly, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	ret := gui.None
+	shouldInvalidate := buf.Len() != 0
+	if shouldInvalidate {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	a.drawnCount = len(anomalies)
+	if !a.show {
+		return ret
+	}
+	// Leave room for the border, title, legend and summary.
+	box := gui.Box{
+		BoxText: anomalyLines(anomalies, max(min(maxListedAnomalies, size.Height-9), 1)),
+		Position: gui.Position{
+			Vertical:   gui.Middle,
+			Horizontal: gui.Left,
+			Padding:    gui.Padding{Left: 4},
+		},
+		Style: gui.SharpCorners,
+	}
+	box.Draw(size, buf)
+	return ret | gui.Paint
+}
+
+func anomalyLines(anomalies []data.Anomaly, listed int) []gui.Typography {
+	spikes, shifts := 0, 0
+	for _, a := range anomalies {
+		switch a.Kind {
+		case data.Spike:
+			spikes++
+		case data.LevelShift:
+			shifts++
+		}
+	}
+	ret := []gui.Typography{
+		{ToPrint: themes.Highlight("Anomalies"), TextLen: 9, Alignment: gui.Centre},
+		{ToPrint: graph.AnomalyKey(data.Spike) + themes.Primary(" spike  ") +
+			graph.AnomalyKey(data.LevelShift) + themes.Primary(" level shift"), TextLen: 1 + 8 + 1 + 12, Alignment: gui.Centre},
+		{ToPrint: "", TextLen: 0, Alignment: gui.Centre},
+	}
+	if len(anomalies) == 0 {
+		ret = append(ret, gui.Typography{ToPrint: themes.Secondary("None found yet"), TextLen: 14, Alignment: gui.Centre})
+	}
+	for _, a := range anomalies[max(len(anomalies)-listed, 0):] {
+		text := fmt.Sprintf(" %s %s (baseline %s, %+.1fσ)", a.Timestamp.Format(time.TimeOnly),
+			a.Duration.Round(time.Microsecond*10), a.Baseline.Round(time.Microsecond*10), a.Score)
+		ret = append(ret, gui.Typography{
+			ToPrint:   graph.AnomalyKey(a.Kind) + themes.Primary(text),
+			TextLen:   1 + utf8.RuneCountInString(text),
+			Alignment: gui.Left,
+		})
+	}
+	summary := fmt.Sprintf("%d spikes, %d level shifts", spikes, shifts)
+	ret = append(ret,
+		gui.Typography{ToPrint: "", TextLen: 0, Alignment: gui.Centre},
+		gui.Typography{ToPrint: themes.Secondary(summary), TextLen: len(summary), Alignment: gui.Centre},
+	)
+	return ret
+}
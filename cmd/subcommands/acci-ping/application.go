@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -10,10 +10,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
-	"os"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Lexer747/acci-ping/draw"
@@ -37,13 +38,22 @@ type Application struct {
 	g    *graph.Graph
 	term *terminal.Terminal
 
-	toUpdate   *os.File
+	toUpdate *files.RotatingFile
+	// template and retention describe the files of the capture when writing to file.
+	template  files.Template
+	retention files.Retention
+	// history is the points already recorded in [toUpdate], they are read after the application has started.
+	history    *data.Stream
 	config     Config
 	drawBuffer *draw.Buffer
 
 	errorChannel      chan error
 	graphControlPlane chan graph.Control
 	speedChange       chan<- ping.Speed
+
+	annotation annotationInput
+	// inspecting is true while the inspect cursor is shown, the arrow keys move it instead of panning.
+	inspecting bool
 }
 
 func (app *Application) Run(
@@ -54,14 +64,22 @@ func (app *Application) Run(
 ) error {
 	var fileData *data.Data
 	var graphChannel, fileChannel <-chan ping.PingResults
+	var fileAnnotations chan data.Annotation
+	var loaded <-chan error
 	if app.toUpdate != nil {
 		// The ping channel which is already running needs to be duplicated, providing one to the Graph and second
 		// to a file writer. This de-couples the processes, we don't want the GUI to affect storing data and vice
 		// versa.
 		graphChannel, fileChannel = channels.TeeBufferedChannel(ctx, channel, *app.config.pingBufferingLimit)
-		var err error
-		fileData, err = duplicateData(app.toUpdate)
-		exit.OnError(err)
+		fileData = data.NewData(app.history.URL)
+		for _, a := range app.history.Annotations {
+			fileData.AddAnnotation(a)
+		}
+		// The graph first receives the points already in the file, then the live points.
+		var history <-chan ping.PingResults
+		history, loaded = app.loadHistory(ctx, fileData)
+		graphChannel = channels.Concat(ctx, history, graphChannel)
+		fileAnnotations = make(chan data.Annotation)
 	} else {
 		// We don't need to duplicate the channel since we are not writing anything to a file
 		graphChannel = channel
@@ -70,9 +88,17 @@ func (app *Application) Run(
 	app.drawBuffer = draw.NewPaintBuffer()
 
 	helpCh := make(chan rune)
+	histogramCh := make(chan rune)
+	heatmapCh := make(chan rune)
+	anomaliesCh := make(chan rune)
+	windowsCh := make(chan rune)
+	mouseCh := make(chan terminal.Mouse, mouseBuffer)
+	inspectCh := make(chan terminal.Key, inspectBuffer)
 	guiControlChannel := make(chan graph.Control)
 	guiSpeedChange := make(chan ping.Speed)
-	app.addFallbackListener(helpAction(helpCh))
+	promptCh := make(chan annotationPrompt)
+	app.addFallbackListener(app.typeAnnotation(ctx, promptCh, fileAnnotations))
+	app.addFallbackListener(app.unlessTyping(helpAction(helpCh)))
 
 	control := graph.Presentation{
 		Following:  *app.config.followingOnStart,
@@ -99,14 +125,35 @@ func (app *Application) Run(
 	if *app.config.testErrorListener {
 		app.makeErrorGenerator()
 	}
-	app.addListeners(control, guiSpeedChange, guiControlChannel)
+	app.addListeners(ctx, control, guiSpeedChange, guiControlChannel, promptCh)
+	app.addListener('d', helpAction(histogramCh))
+	app.addListener('D', helpAction(histogramCh))
+	app.addListener('a', helpAction(anomaliesCh))
+	app.addListener('s', helpAction(windowsCh))
+	app.addListener('c', helpAction(heatmapCh))
+	app.addListener('C', helpAction(heatmapCh))
+	app.addListener('i', func(r rune) error {
+		app.inspecting = !app.inspecting
+		sendKey(ctx, inspectCh, terminal.Key{Rune: r})
+		return nil
+	})
+	app.addKeyListener(terminal.KeyLeft, app.inspectOrPan(ctx, inspectCh, graph.PanLeft))
+	app.addKeyListener(terminal.KeyRight, app.inspectOrPan(ctx, inspectCh, graph.PanRight))
+	app.addKeyListener(terminal.KeyMouse, sendMouse(ctx, mouseCh))
 	defer close(app.errorChannel)
 	defer close(app.graphControlPlane)
 	defer close(helpCh)
+	defer close(histogramCh)
+	defer close(heatmapCh)
+	defer close(anomaliesCh)
+	defer close(windowsCh)
+	defer close(mouseCh)
+	defer close(inspectCh)
 	defer close(guiControlChannel)
 	defer close(guiSpeedChange)
+	defer close(promptCh)
 	// Very high FPS is good for responsiveness in the UI (since it's locked) and re-drawing on a re-size.
-	graph, cleanup, terminalSizeUpdates, err := app.g.Run(ctx, cancelFunc, app.listeners(), app.fallbacks)
+	graph, cleanup, terminalSizeUpdates, err := app.g.Run(ctx, cancelFunc, app.listeners(), app.keyListeners(), app.fallbacks)
 	termRecover := func() {
 		_ = app.term.ClearScreen(terminal.UpdateSize)
 		cleanup()
@@ -114,7 +161,7 @@ func (app *Application) Run(
 			panic(err)
 		}
 	}
-	terminalUpdates := channels.FanInFanOut(ctx, terminalSizeUpdates, 0, 4)
+	terminalUpdates := channels.FanInFanOut(ctx, terminalSizeUpdates, 0, 11)
 
 	// https://go.dev/ref/spec#Handling_panics
 	// https://go.dev/blog/defer-panic-and-recover
@@ -123,7 +170,7 @@ func (app *Application) Run(
 	if fileData != nil {
 		go func() {
 			defer termRecover()
-			app.writeToFile(ctx, fileData, fileChannel)
+			app.writeToFile(ctx, fileData, loaded, fileChannel, fileAnnotations)
 		}()
 	}
 	go func() {
@@ -142,6 +189,34 @@ func (app *Application) Run(
 		defer termRecover()
 		app.showSpeedChanges(ctx, guiSpeedChange, terminalUpdates[3])
 	}()
+	go func() {
+		defer termRecover()
+		app.showAnnotationPrompt(ctx, promptCh, terminalUpdates[4])
+	}()
+	go func() {
+		defer termRecover()
+		app.histogram(ctx, histogramCh, terminalUpdates[5])
+	}()
+	go func() {
+		defer termRecover()
+		app.heatmap(ctx, heatmapCh, terminalUpdates[6])
+	}()
+	go func() {
+		defer termRecover()
+		app.anomalies(ctx, anomaliesCh, terminalUpdates[7])
+	}()
+	go func() {
+		defer termRecover()
+		app.windows(ctx, windowsCh, terminalUpdates[8])
+	}()
+	go func() {
+		defer termRecover()
+		app.mouse(ctx, mouseCh, terminalUpdates[9])
+	}()
+	go func() {
+		defer termRecover()
+		app.inspect(ctx, inspectCh, terminalUpdates[10])
+	}()
 	defer termRecover()
 	exit.OnError(err)
 	return graph()
@@ -150,7 +225,7 @@ func (app *Application) Run(
 func (app *Application) Init(ctx context.Context, c Config) (<-chan ping.PingResults, *data.Data) {
 	app.config = c
 	app.errorChannel = make(chan error)
-	app.graphControlPlane = make(chan graph.Control)
+	app.graphControlPlane = make(chan graph.Control, controlBuffer)
 	app.GUI = newGUIState()
 	p := ping.NewPing()
 	var err error
@@ -159,7 +234,14 @@ func (app *Application) Init(ctx context.Context, c Config) (<-chan ping.PingRes
 
 	var existingData *data.Data
 	if *c.filePath != "" {
-		existingData, app.toUpdate = loadFile(*c.filePath, *c.url)
+		// Only the meta data is read now, the points are streamed to the graph once it's running, see
+		// [Application.loadHistory].
+		app.template, app.retention = parseFileFlags(c)
+		app.history, app.toUpdate = loadFile(app.template, *c.filePath, *c.url, *c.multiTarget)
+		existingData = data.NewData(app.history.URL)
+		for _, a := range app.history.Annotations {
+			existingData.AddAnnotation(a)
+		}
 	} else {
 		existingData = data.NewData(*c.url)
 	}
@@ -191,6 +273,23 @@ func (app *Application) Finish() {
 		app.term.Print("\n\n# Summary\nData not saved, use `-file [FILE_NAME]` to save recordings in future.\n\t" +
 			app.g.Summarise() + "\n")
 	}
+	app.term.Print(summariseOutages(app.g.Outages(data.DefaultOutageOptions)))
+}
+
+// maxSummaryOutages is the most outages listed on exit, the rest can be found with the outages subcommand.
+const maxSummaryOutages = 10
+
+func summariseOutages(report *data.OutageReport) string {
+	var b strings.Builder
+	b.WriteString("\n# Outages\n\t" + strings.ReplaceAll(report.Summary(), "| ", "\n\t") + "\n")
+	listed := report.Outages[max(len(report.Outages)-maxSummaryOutages, 0):]
+	if hidden := len(report.Outages) - len(listed); hidden > 0 {
+		fmt.Fprintf(&b, "\t... %d earlier outages, use `acci-ping outages` to list them all\n", hidden)
+	}
+	for _, o := range listed {
+		b.WriteString("\t" + o.String() + "\n")
+	}
+	return b.String()
 }
 
 func appThemeStartUp() {
@@ -200,7 +299,13 @@ func appThemeStartUp() {
 
 // addListeners will add all the listeners to the application which will be forwarded to the terminal for
 // execution when the specified key is pressed.
-func (app *Application) addListeners(control graph.Presentation, guiSpeedChange chan ping.Speed, guiControlChannel chan graph.Control) {
+func (app *Application) addListeners(
+	ctx context.Context,
+	control graph.Presentation,
+	guiSpeedChange chan ping.Speed,
+	guiControlChannel chan graph.Control,
+	promptCh chan annotationPrompt,
+) {
 	app.addListener('f', func(rune) error {
 		control.Following = !control.Following
 		update := graph.Control{
@@ -234,6 +339,9 @@ func (app *Application) addListeners(control graph.Presentation, guiSpeedChange
 		}()
 		return nil
 	})
+	app.addListener('z', func(rune) error { app.sendControl(ctx, zoomControl(graph.ZoomIn)); return nil })
+	app.addListener('Z', func(rune) error { app.sendControl(ctx, zoomControl(graph.ZoomOut)); return nil })
+	app.addListener('r', func(rune) error { app.sendControl(ctx, zoomControl(graph.Live)); return nil })
 	app.addListener('+', func(rune) error {
 		go func() {
 			app.speedChange <- ping.Faster
@@ -248,11 +356,88 @@ func (app *Application) addListeners(control graph.Presentation, guiSpeedChange
 		}()
 		return nil
 	})
+	app.addListener('m', app.startAnnotation(promptCh))
+}
+
+// loadHistory reads the points already recorded in the file into [ourData] and the returned channel, this
+// allows the graph to start drawing a large file before it's been fully read. The returned error channel
+// receives once the file is fully read (or failed to read), before which [ourData] must not be used.
+func (app *Application) loadHistory(ctx context.Context, ourData *data.Data) (<-chan ping.PingResults, <-chan error) {
+	history := make(chan ping.PingResults)
+	loaded := make(chan error, 1)
+	go func() {
+		defer close(history)
+		for {
+			p, err := app.history.Next()
+			if errors.Is(err, io.EOF) {
+				loaded <- nil
+				return
+			}
+			if err != nil {
+				loaded <- errors.Wrapf(err, "failed to read %q", *app.config.filePath)
+				return
+			}
+			ourData.AddPoint(p)
+			select {
+			case <-ctx.Done():
+				loaded <- ctx.Err()
+				return
+			case history <- p:
+			}
+		}
+	}()
+	return history, loaded
 }
 
-func (app *Application) writeToFile(ctx context.Context, ourData *data.Data, input <-chan ping.PingResults) {
+func (app *Application) writeToFile(
+	ctx context.Context,
+	ourData *data.Data,
+	loaded <-chan error,
+	input <-chan ping.PingResults,
+	annotations <-chan data.Annotation,
+) {
 	defer app.toUpdate.Close()
+	// Nothing can be written until the existing points are read otherwise they would be lost.
+	var loadErr error
+	select {
+	case <-ctx.Done():
+		return
+	case loadErr = <-loaded:
+	}
+	if loadErr != nil {
+		app.errorChannel <- errors.Wrap(loadErr, "new pings will not be saved")
+	}
+	app.retain()
 	exp := backoff.NewExponentialBackoff(500 * time.Millisecond)
+	// dirty is set when the file is behind [ourData] because a write was skipped.
+	dirty := false
+	defer func() {
+		if !dirty {
+			return
+		}
+		// There is no next update, so wait for any readers rather than losing the last points.
+		if err := app.toUpdate.OverwriteWait(ourData); err != nil {
+			slog.Error("failed to write the last pings", "err", err)
+		}
+	}()
+	write := func() {
+		if loadErr != nil {
+			// The file is left untouched, keep consuming the input so that the graph isn't blocked.
+			return
+		}
+		err := app.toUpdate.Overwrite(ourData)
+		dirty = err != nil
+		if errors.Is(err, files.ErrBusy) {
+			// Someone is reading the file, the next update will write everything.
+			return
+		}
+		if err != nil {
+			app.errorChannel <- err
+			exp.Wait()
+			return
+		}
+		exp.Success()
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -261,24 +446,44 @@ func (app *Application) writeToFile(ctx context.Context, ourData *data.Data, inp
 			if !ok {
 				return
 			}
-			ourData.AddPoint(p)
-			_, err := app.toUpdate.Seek(0, 0)
-			if err != nil {
-				app.errorChannel <- err
-				exp.Wait()
-				continue
+			if loadErr == nil {
+				ourData, dirty = app.rotate(ourData, p, dirty)
 			}
-			err = ourData.AsCompact(app.toUpdate)
-			if err != nil {
-				app.errorChannel <- err
-				exp.Wait()
-				continue
+			ourData.AddPoint(p)
+			write()
+		case a, ok := <-annotations:
+			if !ok {
+				return
 			}
-			exp.Success()
+			ourData.AddAnnotation(a)
+			write()
 		}
 	}
 }
 
+// rotate moves on to the next file of the template if the point [p] belongs in a new file, returning the data
+// of the file which should now be written and if it's [dirty]. If moving on fails the current file continues
+// to be used.
+func (app *Application) rotate(ourData *data.Data, p ping.PingResults, dirty bool) (*data.Data, bool) {
+	newData, err := app.toUpdate.Rotate(p.Data.Timestamp, ourData)
+	if err != nil {
+		app.errorChannel <- errors.Wrap(err, "failed to move on to the next file")
+		return ourData, dirty
+	}
+	if newData == nil {
+		return ourData, dirty
+	}
+	app.retain()
+	return newData, false
+}
+
+// retain applies the retention to the older files of the template.
+func (app *Application) retain() {
+	if err := app.template.Retain(app.retention, time.Now()); err != nil {
+		app.errorChannel <- err
+	}
+}
+
 func (app *Application) makeErrorGenerator() {
 	app.addListener('e', func(r rune) error {
 		go func() { app.errorChannel <- errors.New("Test Error") }()
@@ -289,6 +494,23 @@ func (app *Application) makeErrorGenerator() {
 	)
 }
 
+// inspectOrPan is the listener of an arrow key, while inspecting it moves the inspect cursor otherwise it pans
+// the graph with the [action].
+func (app *Application) inspectOrPan(
+	ctx context.Context,
+	inspectChannel chan<- terminal.Key,
+	action graph.ZoomAction,
+) func(terminal.Key) error {
+	return func(k terminal.Key) error {
+		if app.inspecting {
+			sendKey(ctx, inspectChannel, k)
+		} else {
+			app.sendControl(ctx, zoomControl(action))
+		}
+		return nil
+	}
+}
+
 // addListener ensures that no key was double registered and creates the boiler plate required by the
 // terminal.
 func (app *Application) addListener(r rune, Action func(rune) error) {
@@ -301,7 +523,24 @@ func (app *Application) addListener(r rune, Action func(rune) error) {
 			Name:   "GUI Listener " + strconv.QuoteRune(r),
 		},
 		Applicable: func(in rune) bool {
-			return in == r
+			// While the user is typing an annotation no other listener should fire.
+			return in == r && !app.annotation.typing
+		},
+	}
+}
+
+// addKeyListener adds a listener for a special key without modifiers, e.g. [terminal.KeyLeft], see
+// [terminal.KeyListener].
+func (app *Application) addKeyListener(code terminal.KeyCode, Action func(terminal.Key) error) {
+	if _, found := app.listeningKeys[code]; found {
+		panic(fmt.Sprintf("Adding more than one listener for '%v'", terminal.Key{Code: code}))
+	}
+	app.listeningKeys[code] = terminal.KeyListener{
+		Action: Action,
+		Name:   "GUI Key Listener " + terminal.Key{Code: code}.String(),
+		Applicable: func(k terminal.Key) bool {
+			// While the user is typing an annotation no other listener should fire.
+			return k.Code == code && k.Modifiers == 0 && !app.annotation.typing
 		},
 	}
 }
@@ -319,19 +558,30 @@ func (app *Application) listeners() []terminal.ConditionalListener {
 	return slices.AppendSeq(ret, maps.Values(app.listeningChars))
 }
 
-func duplicateData(f *os.File) (*data.Data, error) {
-	d := &data.Data{}
-	file, fileErr := io.ReadAll(f)
-	_, readingErr := d.FromCompact(file)
-	return d, errors.Join(fileErr, readingErr)
+func (app *Application) keyListeners() []terminal.KeyListener {
+	ret := make([]terminal.KeyListener, 0, len(app.listeningKeys))
+	return slices.AppendSeq(ret, maps.Values(app.listeningKeys))
 }
 
-// TODO incremental read/writes, get the URL ASAP then start the channel, then incremental continuation.
-func loadFile(file, url string) (*data.Data, *os.File) {
-	// TODO this currently panics if the url's don't match we should do better
-	d, f, err := files.LoadOrCreateFile(file, url)
+// parseFileFlags exits if the flags describing the files of the capture are invalid.
+func parseFileFlags(c Config) (files.Template, files.Retention) {
+	if *c.rotateSizeMB < 0 || *c.retentionDays < 0 {
+		exit.OnError(errors.New("-rotate-size-mb and -retention-days cannot be negative"))
+	}
+	template, err := files.ParseTemplate(*c.filePath, int64(*c.rotateSizeMB)*1024*1024)
+	exit.OnErrorMsgf(err, "Invalid -file")
+	mode, err := files.ParseRetentionMode(*c.retentionMode)
+	exit.OnErrorMsgf(err, "Invalid -retention")
+	return template, files.Retention{MaxAge: time.Duration(*c.retentionDays) * 24 * time.Hour, Mode: mode}
+}
+
+func loadFile(template files.Template, file, url string, multiTarget bool) (*data.Stream, *files.RotatingFile) {
+	s, f, err := files.StreamOrCreateTemplate(template, url, multiTarget, time.Now())
+	if errors.Is(err, files.ErrWrongURL) {
+		exit.OnErrorMsgf(err, "Use -multi-target to also capture %q to %q, or use a different -file", url, file)
+	}
 	exit.OnError(err)
-	return d, f
+	return s, f
 }
 
 func makeTerminal(termSize *string) (*terminal.Terminal, error) {
@@ -13,6 +13,7 @@ import (
 
 type GUI struct {
 	listeningChars map[rune]terminal.ConditionalListener
+	listeningKeys  map[terminal.KeyCode]terminal.KeyListener
 	GUIState       *gui.GUIState
 	fallbacks      []terminal.Listener
 }
@@ -20,6 +21,7 @@ type GUI struct {
 func newGUIState() *GUI {
 	return &GUI{
 		listeningChars: map[rune]terminal.ConditionalListener{},
+		listeningKeys:  map[terminal.KeyCode]terminal.KeyListener{},
 		fallbacks:      []terminal.Listener{},
 		GUIState:       gui.NewGUIState(),
 	}
@@ -0,0 +1,106 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"time"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+// heatmap which should only be called once the paint buffer and graph are initialised. While shown the
+// heatmap is redrawn as new points arrive.
+func (app *Application) heatmap(
+	ctx context.Context,
+	heatmapChannel <-chan rune,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	heatmapBuffer := app.drawBuffer.Get(draw.HeatmapIndex)
+	h := heatmapPane{}
+	// Every point is read to build the heatmap so it's redrawn less often than the histogram.
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			app.GUIState.Paint(h.render(newSize, app.g, heatmapBuffer))
+		case <-ticker.C:
+			if h.show && graphPacketCount(app.g) != h.drawnCount {
+				app.GUIState.Paint(h.render(app.term.GetSize(), app.g, heatmapBuffer))
+			}
+		case toShow := <-heatmapChannel:
+			switch toShow {
+			case 'c':
+				h.show = !h.show
+			case 'C':
+				switch h.metric {
+				case graph.HeatmapLatency:
+					h.metric = graph.HeatmapLoss
+				case graph.HeatmapLoss:
+					h.metric = graph.HeatmapLatency
+				}
+			default:
+				continue
+			}
+			app.GUIState.Paint(h.render(app.term.GetSize(), app.g, heatmapBuffer))
+		}
+	}
+}
+
+type heatmapPane struct {
+	show   bool
+	metric graph.HeatmapMetric
+	// drawnCount is the number of packets when last drawn, there's no need to redraw until it changes.
+	drawnCount uint64
+}
+
+func (h *heatmapPane) render(size terminal.Size, g *graph.Graph, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	ret := gui.None
+	shouldInvalidate := buf.Len() != 0
+	if shouldInvalidate {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	if !h.show {
+		return ret
+	}
+	h.drawnCount = graphPacketCount(g)
+	// Leave room for the border, title, hours, legend and the days either side of the cells.
+	view := graph.HeatmapView{Metric: h.metric, MaxDays: max(size.Height-8, 1)}
+	options := data.HeatmapOptions{Bucket: time.Hour, Location: time.Local}
+	if size.Width >= 96+11+4 {
+		options.Bucket = 15 * time.Minute
+	}
+	text := graph.HeatmapLines(g.Heatmap(options), view)
+	if text == nil {
+		return ret
+	}
+	box := gui.Box{
+		BoxText: text,
+		Position: gui.Position{
+			Vertical:   gui.Middle,
+			Horizontal: gui.Centre,
+		},
+		Style: gui.SharpCorners,
+	}
+	box.Draw(size, buf)
+	return ret | gui.Paint
+}
+
+// graphPacketCount is the number of packets of the graph, dropped packets count as they change the loss.
+func graphPacketCount(g *graph.Graph) uint64 {
+	s := g.Stats()
+	return s.GoodCount + s.PacketsDropped
+}
@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2025 Lexer747
+// Copyright 2025-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -84,9 +84,21 @@ func (h help) makeHelpBox() gui.Box {
 func helpStartup() {
 	ctrlCText := themes.Positive("ctrl+c")
 	helpText := themes.Highlight("Help")
+	keyBindA := themes.Positive("a")
+	keyBindS := themes.Positive("s")
+	keyBindC := themes.Positive("c")
+	keyBindShiftC := themes.Positive("C")
+	keyBindD := themes.Positive("d")
+	keyBindShiftD := themes.Positive("D")
 	keyBindF := themes.Positive("f")
 	keyBindH := themes.Positive("h")
+	keyBindI := themes.Positive("i")
 	keyBindL := themes.Positive("l")
+	keyBindM := themes.Positive("m")
+	keyBindR := themes.Positive("r")
+	keyBindZ := themes.Positive("z")
+	keyBindShiftZ := themes.Positive("Z")
+	keyBindArrows := themes.Emphasis("←/→")
 	keyBindPlus := themes.Emphasis("+")
 	keyBindNegative := themes.Emphasis("-")
 
@@ -99,6 +111,28 @@ func helpStartup() {
 			TextLen: 6 + 1 + 41, Alignment: gui.Left},
 		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindL + themes.Primary(" to switch to between log and linear y-axis."),
 			TextLen: 6 + 1 + 44, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindZ + themes.Primary(" to zoom in, ") + keyBindShiftZ +
+			themes.Primary(" to zoom out, ") + keyBindR + themes.Primary(" to view everything live."),
+			TextLen: 6 + 1 + 13 + 1 + 14 + 1 + 25, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindArrows + themes.Primary(" to pan back/forward in time."),
+			TextLen: 6 + 3 + 29, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindI + themes.Primary(" to inspect the graph, ") + keyBindArrows +
+			themes.Primary(" move the cursor along it."),
+			TextLen: 6 + 1 + 23 + 3 + 26, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Hover over a point for its details, scroll to zoom and drag to pan."),
+			TextLen: 67, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindM + themes.Primary(" to annotate the graph with a note."),
+			TextLen: 6 + 1 + 35, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindA + themes.Primary(" to show/hide the anomalies found."),
+			TextLen: 6 + 1 + 34, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindS + themes.Primary(" to show/hide the stats of the last 1m, 5m and 15m."),
+			TextLen: 6 + 1 + 51, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindC + themes.Primary(" to show/hide the daily heatmap, ") +
+			keyBindShiftC + themes.Primary(" for loss/latency."),
+			TextLen: 6 + 1 + 33 + 1 + 18, Alignment: gui.Left},
+		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindD + themes.Primary(" to show/hide the latency histogram, ") +
+			keyBindShiftD + themes.Primary(" for log bins."),
+			TextLen: 6 + 1 + 37 + 1 + 14, Alignment: gui.Left},
 		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindPlus + themes.Primary(" to speed up the data capture."),
 			TextLen: 6 + 1 + 30, Alignment: gui.Left},
 		gui.Typography{ToPrint: themes.Primary("Press ") + keyBindNegative + themes.Primary(" to slow down the data capture."),
@@ -109,4 +143,4 @@ func helpStartup() {
 	)
 }
 
-var helpCopy = make([]gui.Typography, 0, 16)
+var helpCopy = make([]gui.Typography, 0, 17)
@@ -0,0 +1,99 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"time"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+// histogram which should only be called once the paint buffer and graph are initialised. While shown the
+// histogram is redrawn as new points arrive.
+func (app *Application) histogram(
+	ctx context.Context,
+	histogramChannel <-chan rune,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	histogramBuffer := app.drawBuffer.Get(draw.HistogramIndex)
+	h := histogramPane{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			app.GUIState.Paint(h.render(newSize, app.g.Stats(), histogramBuffer))
+		case <-ticker.C:
+			stats := app.g.Stats()
+			if h.show && stats.GoodCount != h.drawnCount {
+				app.GUIState.Paint(h.render(app.term.GetSize(), stats, histogramBuffer))
+			}
+		case toShow := <-histogramChannel:
+			switch toShow {
+			case 'd':
+				h.show = !h.show
+			case 'D':
+				h.log = !h.log
+			default:
+				continue
+			}
+			app.GUIState.Paint(h.render(app.term.GetSize(), app.g.Stats(), histogramBuffer))
+		}
+	}
+}
+
+type histogramPane struct {
+	show bool
+	log  bool
+	// drawnCount is the number of good points when last drawn, there's no need to redraw until it changes.
+	drawnCount uint64
+}
+
+func (h *histogramPane) render(size terminal.Size, stats *data.Stats, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	ret := gui.None
+	shouldInvalidate := buf.Len() != 0
+	if shouldInvalidate {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	h.drawnCount = stats.GoodCount
+	if !h.show {
+		return ret
+	}
+	text := graph.HistogramLines(stats, h.options(size))
+	if text == nil {
+		return ret
+	}
+	box := gui.Box{
+		BoxText: text,
+		Position: gui.Position{
+			Vertical:   gui.Middle,
+			Horizontal: gui.Centre,
+		},
+		Style: gui.SharpCorners,
+	}
+	box.Draw(size, buf)
+	return ret | gui.Paint
+}
+
+// options shrinks the histogram to fit small terminals, leaving room for the border, title, footer and the
+// labels either side of the bars.
+func (h *histogramPane) options(size terminal.Size) graph.HistogramOptions {
+	return graph.HistogramOptions{
+		Bins:     max(min(graph.DefaultHistogramOptions.Bins, size.Height-8), 1),
+		Log:      h.log,
+		BarWidth: max(min(graph.DefaultHistogramOptions.BarWidth, size.Width-48), 5),
+	}
+}
@@ -0,0 +1,187 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+// inspect which should only be called once the paint buffer and graph are initialised. While inspecting a
+// cursor is drawn down a column of the graph next to a box describing every point drawn in that column, the
+// arrow keys move the cursor between columns.
+func (app *Application) inspect(
+	ctx context.Context,
+	inspectChannel <-chan terminal.Key,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	inspectBuffer := app.drawBuffer.Get(draw.InspectIndex)
+	c := inspectCursor{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			app.GUIState.Paint(c.render(newSize, app.g, inspectBuffer))
+		case <-ticker.C:
+			// New points are drawn in the column and the graph moves under the cursor while it's live.
+			if c.show {
+				app.GUIState.Paint(c.render(app.term.GetSize(), app.g, inspectBuffer))
+			}
+		case k := <-inspectChannel:
+			switch {
+			case k.Code == terminal.KeyRune && k.Rune == 'i':
+				c.show = !c.show
+				c.column = 0
+			case k.Code == terminal.KeyLeft:
+				c.column--
+			case k.Code == terminal.KeyRight:
+				c.column++
+			}
+			app.GUIState.Paint(c.render(app.term.GetSize(), app.g, inspectBuffer))
+		}
+	}
+}
+
+// inspectBuffer is how many keys can be waiting for [Application.inspect], e.g. an arrow key held down.
+const inspectBuffer = 16
+
+// sendKey sends the [k]ey to the [keyChannel] in order, unless the program is exiting.
+func sendKey(ctx context.Context, keyChannel chan<- terminal.Key, k terminal.Key) {
+	select {
+	case <-ctx.Done():
+	case keyChannel <- k:
+	}
+}
+
+type inspectCursor struct {
+	show bool
+	// column is where the cursor is drawn, zero starts it at the latest column.
+	column int
+}
+
+func (c *inspectCursor) render(size terminal.Size, g *graph.Graph, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	drawn := bytes.NewSafeBuffer()
+	if c.show {
+		c.draw(size, g, drawn)
+	}
+	if drawn.String() == buf.String() {
+		// Nothing has changed since it was last drawn.
+		return gui.None
+	}
+	ret := gui.None
+	shouldInvalidate := buf.Len() != 0
+	if shouldInvalidate {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	if drawn.Len() == 0 {
+		return ret
+	}
+	buf.WriteString(drawn.String())
+	return ret | gui.Paint
+}
+
+func (c *inspectCursor) draw(size terminal.Size, g *graph.Graph, buf *bytes.SafeBuffer) {
+	first, last, ok := g.PlotColumns()
+	if !ok {
+		return
+	}
+	if c.column == 0 {
+		c.column = last
+	}
+	c.column = min(max(c.column, first), last)
+	// The same rows as the points of the graph.
+	for row := 2; row <= size.Height-2; row++ {
+		buf.WriteString(ansi.CursorPosition(row, c.column) + themes.Emphasis(typography.DashedVertical))
+	}
+	column, ok := g.InspectColumn(c.column)
+	lines := inspectLines(column, ok)
+	width := 0
+	for _, l := range lines {
+		width = max(width, l.TextLen)
+	}
+	// To the right of the cursor, unless that's off the screen in which case it's to the left so that it
+	// never covers the column being inspected.
+	boxColumn := c.column + 2
+	if boxColumn+width+2 > size.Width {
+		boxColumn = c.column - width - 3
+	}
+	box := gui.Box{BoxText: lines, Style: gui.RoundedCorners}
+	box.At(2, boxColumn, size).Draw(size, buf)
+}
+
+const (
+	inspectLabelWidth = 7
+	// inspectMaxIPs is how many IPs are listed before the rest are summarised.
+	inspectMaxIPs = 3
+)
+
+// inspectLines describes every point of the [column]: when they were, how many, their round trip times, how
+// many were dropped and which IPs were pinged. If not [ok] the column is between the spans of the graph.
+func inspectLines(column graph.Column, ok bool) []gui.Typography {
+	ret := []gui.Typography{
+		{ToPrint: themes.Highlight("Inspect"), TextLen: 7, Alignment: gui.Centre},
+	}
+	row := func(label, value string) {
+		ret = append(ret, gui.Typography{
+			ToPrint:   themes.Secondary(fmt.Sprintf("%-*s", inspectLabelWidth, label)) + themes.Primary(value),
+			TextLen:   inspectLabelWidth + len([]rune(value)),
+			Alignment: gui.Left,
+		})
+	}
+	if !ok {
+		row("", "between spans")
+		return ret
+	}
+	layout := "15:04:05"
+	if column.Begin.YearDay() != column.End.YearDay() {
+		layout = "Jan 2 15:04:05"
+	}
+	row("Time", column.Begin.Format(layout)+"–"+column.End.Format(layout))
+	s := column.Stats
+	count := s.GoodCount + s.PacketsDropped
+	row("Pings", fmt.Sprint(count))
+	if count == 0 {
+		return ret
+	}
+	if s.GoodCount > 0 {
+		row("Min", timeutils.HumanString(s.Min, 3))
+		row("Mean", timeutils.HumanString(time.Duration(s.Mean), 3))
+		row("Max", timeutils.HumanString(s.Max, 3))
+	}
+	row("Drops", fmt.Sprintf("%d (%.1f%%)", s.PacketsDropped, s.PacketLoss()*100))
+	for i, ip := range column.IPs {
+		label := ""
+		if i == 0 {
+			label = "IPs"
+		}
+		if i == inspectMaxIPs {
+			row(label, fmt.Sprintf("+%d more", len(column.IPs)-inspectMaxIPs))
+			break
+		}
+		text := "-"
+		if len(ip) != 0 {
+			text = ip.String()
+		}
+		row(label, text)
+	}
+	return ret
+}
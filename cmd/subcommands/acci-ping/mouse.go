@@ -0,0 +1,169 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+// mouse which should only be called once the paint buffer and graph are initialised. Hovering over the graph
+// shows a tooltip of the nearest point, the wheel zooms the time axis and dragging pans it.
+func (app *Application) mouse(
+	ctx context.Context,
+	mouseChannel <-chan terminal.Mouse,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	tooltipBuffer := app.drawBuffer.Get(draw.TooltipIndex)
+	tt := tooltip{}
+	// dragFrom is the column the left button was last at while held, zero if it isn't held.
+	dragFrom := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			// The tooltip would be in the wrong place, it's shown again when the mouse next moves.
+			tt.show = false
+			app.GUIState.Paint(tt.render(newSize, tooltipBuffer))
+		case m := <-mouseChannel:
+			// Anything but hovering changes the graph underneath the tooltip, so it's hidden.
+			tt.show = false
+			switch {
+			case m.Button == terminal.MouseWheelUp:
+				app.sendControl(ctx, zoomControl(graph.ZoomIn))
+			case m.Button == terminal.MouseWheelDown:
+				app.sendControl(ctx, zoomControl(graph.ZoomOut))
+			case m.Button == terminal.MouseLeft && m.Action == terminal.MousePress:
+				dragFrom = m.X
+			case m.Button == terminal.MouseLeft && m.Action == terminal.MouseMotion && dragFrom != 0:
+				// Dragging the graph to the right brings earlier times into view.
+				if columns := dragFrom - m.X; columns != 0 {
+					app.sendControl(ctx, graph.Control{Pan: graph.Change[int]{DidChange: true, Value: columns}})
+					dragFrom = m.X
+				}
+			case m.Action == terminal.MouseRelease:
+				dragFrom = 0
+			case m.Button == terminal.MouseNone && m.Action == terminal.MouseMotion:
+				tt.point, tt.show = app.g.Inspect(m.X)
+				tt.x, tt.y = m.X, m.Y
+			}
+			app.GUIState.Paint(tt.render(app.term.GetSize(), tooltipBuffer))
+		}
+	}
+}
+
+// mouseBuffer is how many mouse events can be waiting for [Application.mouse], a moving mouse reports an event
+// for every cell it crosses.
+const mouseBuffer = 16
+
+// sendMouse forwards mouse events from the terminal without holding up the terminal's input. While the buffer
+// is full motion is dropped since the next motion replaces it anyway, every other event is waited on unless
+// the program is exiting.
+func sendMouse(ctx context.Context, mouseChannel chan<- terminal.Mouse) func(terminal.Key) error {
+	return func(k terminal.Key) error {
+		if k.Mouse.Action == terminal.MouseMotion {
+			select {
+			case mouseChannel <- k.Mouse:
+			default:
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+		case mouseChannel <- k.Mouse:
+		}
+		return nil
+	}
+}
+
+// controlBuffer is how many controls can be waiting for the graph, e.g. a zoom key held down.
+const controlBuffer = 16
+
+// sendControl sends the [update] to the graph in order, unless the program is exiting.
+func (app *Application) sendControl(ctx context.Context, update graph.Control) {
+	select {
+	case <-ctx.Done():
+	case app.graphControlPlane <- update:
+	}
+}
+
+func zoomControl(action graph.ZoomAction) graph.Control {
+	return graph.Control{Zoom: graph.Change[graph.ZoomAction]{DidChange: true, Value: action}}
+}
+
+type tooltip struct {
+	show  bool
+	point ping.PingResults
+	// x and y are where the mouse is, the tooltip is drawn next to it.
+	x, y int
+}
+
+func (tt *tooltip) render(size terminal.Size, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	ret := gui.None
+	shouldInvalidate := buf.Len() != 0
+	if shouldInvalidate {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	if !tt.show {
+		return ret
+	}
+	lines := tooltipLines(tt.point)
+	width := 0
+	for _, l := range lines {
+		width = max(width, l.TextLen)
+	}
+	// Below and to the right of the mouse, unless that's off the screen in which case it's flipped to the
+	// other side so that it never covers the point being inspected.
+	row, column := tt.y+1, tt.x+2
+	if column+width+2 > size.Width {
+		column = tt.x - width - 3
+	}
+	if row+len(lines)+2 > size.Height {
+		row = tt.y - len(lines) - 2
+	}
+	box := gui.Box{BoxText: lines, Style: gui.RoundedCorners}
+	box.At(row, column, size).Draw(size, buf)
+	return ret | gui.Paint
+}
+
+const tooltipLabelWidth = 6
+
+// tooltipLines describes the [p]oint, when it was, how long it took or why it was dropped and the IP pinged.
+func tooltipLines(p ping.PingResults) []gui.Typography {
+	ret := []gui.Typography{}
+	row := func(label, value string) {
+		ret = append(ret, gui.Typography{
+			ToPrint:   themes.Secondary(fmt.Sprintf("%-*s", tooltipLabelWidth, label)) + themes.Primary(value),
+			TextLen:   tooltipLabelWidth + len([]rune(value)),
+			Alignment: gui.Left,
+		})
+	}
+	row("Time", p.Data.Timestamp.Format("Jan 2 15:04:05.000"))
+	if p.Data.Dropped() {
+		row("RTT", "-")
+		row("Drop", p.Data.DropReason.String())
+	} else {
+		row("RTT", timeutils.HumanString(p.Data.Duration, 3))
+	}
+	ip := "-"
+	if len(p.IP) != 0 {
+		ip = p.IP.String()
+	}
+	row("IP", ip)
+	return ret
+}
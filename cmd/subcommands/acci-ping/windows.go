@@ -0,0 +1,167 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package acciping
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+// windows which should only be called once the paint buffer and graph are initialised. While shown the stats
+// are redrawn as new points arrive.
+func (app *Application) windows(
+	ctx context.Context,
+	windowsChannel <-chan rune,
+	terminalSizeUpdates <-chan terminal.Size,
+) {
+	windowsBuffer := app.drawBuffer.Get(draw.WindowsIndex)
+	w := windowsPanel{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case newSize := <-terminalSizeUpdates:
+			app.GUIState.Paint(w.render(newSize, app.g.Windows(), windowsBuffer))
+		case <-ticker.C:
+			windows := app.g.Windows()
+			if w.show && packetCount(windows) != w.drawnCount {
+				app.GUIState.Paint(w.render(app.term.GetSize(), windows, windowsBuffer))
+			}
+		case toShow := <-windowsChannel:
+			switch toShow {
+			case 's':
+				w.show = !w.show
+				app.GUIState.Paint(w.render(app.term.GetSize(), app.g.Windows(), windowsBuffer))
+			default:
+			}
+		}
+	}
+}
+
+type windowsPanel struct {
+	show bool
+	// drawnCount is the number of packets when last drawn, there's no need to redraw until it changes.
+	drawnCount uint64
+}
+
+func (w *windowsPanel) render(size terminal.Size, windows []data.WindowStats, buf *bytes.SafeBuffer) gui.PaintUpdate {
+	ret := gui.None
+	shouldInvalidate := buf.Len() != 0
+	if shouldInvalidate {
+		ret = ret | gui.Invalidate
+	}
+	buf.Reset()
+	w.drawnCount = packetCount(windows)
+	if !w.show {
+		return ret
+	}
+	box := gui.Box{
+		BoxText: windowLines(windows),
+		Position: gui.Position{
+			Vertical:   gui.Bottom,
+			Horizontal: gui.Right,
+			// Keep clear of the x-axis
+			Padding: gui.Padding{Top: 2, Left: 1},
+		},
+		Style: gui.SharpCorners,
+	}
+	box.Draw(size, buf)
+	return ret | gui.Paint
+}
+
+// packetCount is the number of packets of the all time window, the last of [windows].
+func packetCount(windows []data.WindowStats) uint64 {
+	all := windows[len(windows)-1].Stats
+	if all == nil {
+		return 0
+	}
+	return all.GoodCount + all.PacketsDropped
+}
+
+const (
+	windowLabelWidth  = 13
+	windowColumnWidth = 9
+)
+
+// windowLines is a table of [windows], a column per window and a row per statistic.
+func windowLines(windows []data.WindowStats) []gui.Typography {
+	header := strings.Repeat(" ", windowLabelWidth)
+	for _, w := range windows {
+		header += fmt.Sprintf("%*s", windowColumnWidth, windowName(w.Window))
+	}
+	ret := []gui.Typography{
+		{ToPrint: themes.Highlight("Recent Stats"), TextLen: 12, Alignment: gui.Centre},
+		{ToPrint: themes.Emphasis(header), TextLen: len(header), Alignment: gui.Left},
+	}
+	row := func(label string, value func(w data.WindowStats) string) {
+		text := ""
+		for _, w := range windows {
+			cell := "-"
+			if w.Stats != nil {
+				cell = value(w)
+			}
+			text += fmt.Sprintf("%*s", windowColumnWidth, cell)
+		}
+		ret = append(ret, gui.Typography{
+			ToPrint:   themes.Secondary(fmt.Sprintf("%-*s", windowLabelWidth, label)) + themes.Primary(text),
+			TextLen:   windowLabelWidth + len([]rune(text)),
+			Alignment: gui.Left,
+		})
+	}
+	row("Mean", func(w data.WindowStats) string {
+		if w.Stats.GoodCount == 0 {
+			return "-"
+		}
+		return timeutils.HumanString(time.Duration(w.Stats.Mean), 3)
+	})
+	row("p95", func(w data.WindowStats) string {
+		if !w.Stats.HasQuantiles() {
+			return "-"
+		}
+		return timeutils.HumanString(w.Stats.Quantile(0.95), 3)
+	})
+	row("Loss", func(w data.WindowStats) string { return fmt.Sprintf("%.2f%%", w.Stats.PacketLoss()*100) })
+	row("Jitter", func(w data.WindowStats) string {
+		if w.Stats.GoodCount < 2 {
+			return "-"
+		}
+		return timeutils.HumanString(w.Stats.Jitter(), 3)
+	})
+	row("Longest drop", func(w data.WindowStats) string {
+		if w.LongestDrop == 0 {
+			return "-"
+		}
+		return timeutils.HumanString(w.LongestDrop, 3)
+	})
+	return ret
+}
+
+// windowName is the short name of the window, e.g. "5m", zero is all time.
+func windowName(window time.Duration) string {
+	switch {
+	case window == 0:
+		return "All"
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	case window%time.Minute == 0:
+		return fmt.Sprintf("%dm", window/time.Minute)
+	default:
+		return window.String()
+	}
+}
@@ -0,0 +1,186 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package analyse
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/exit"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	periodicity   *bool
+	json          *bool
+	step          *time.Duration
+	minPeriod     *time.Duration
+	maxPeriod     *time.Duration
+	top           *int
+	minConfidence *float64
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		periodicity: tf.Bool("periodicity", false, "finds the patterns in the latency which repeat, e.g. a backup job every 10 minutes,\n"+
+			"reporting each period with the time of its first peak, strength (autocorrelation) and confidence"),
+		json: tf.Bool("json", false, "writes the analysis as a json document per target instead of a table"),
+		step: tf.Duration("step", data.DefaultPeriodicityOptions.Step,
+			"the interval the latency is resampled to before looking for periods, if zero the median interval\n"+
+				"between the packets is used"),
+		minPeriod: tf.Duration("min-period", data.DefaultPeriodicityOptions.MinPeriod, "the shortest period looked for"),
+		maxPeriod: tf.Duration("max-period", data.DefaultPeriodicityOptions.MaxPeriod,
+			"the longest period looked for, if zero this is a third of the capture"),
+		top: tf.Int("top", data.DefaultPeriodicityOptions.Top, "the most periods reported"),
+		minConfidence: tf.Float64("min-confidence", data.DefaultPeriodicityOptions.MinConfidence,
+			"how sure it must be that a period isn't noise to be reported, between 0 and 1"),
+	}
+
+	f.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: analyses the latency of '.pings' files\n"+
+			"\t analyse -periodicity [-json][-step DURATION][-min-period DURATION][-max-period DURATION][-top N] FILES\n\n"+
+			"e.g. %s analyse -periodicity my_ping_capture.ping\n", os.Args[0], os.Args[0])
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunAnalyse(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	if !*c.periodicity {
+		fmt.Fprintf(os.Stderr, "No analysis chosen, use -periodicity. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	toRead := c.Args()
+	if len(toRead) == 0 {
+		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
+		exit.Success()
+	}
+	if *c.top < 1 {
+		fmt.Fprintf(os.Stderr, "-top must be at least 1, got %d.\n", *c.top)
+		exit.Silent()
+	}
+	if *c.minConfidence < 0 || *c.minConfidence > 1 {
+		fmt.Fprintf(os.Stderr, "-min-confidence must be between 0 and 1, got %g.\n", *c.minConfidence)
+		exit.Silent()
+	}
+	options := data.PeriodicityOptions{
+		Step:          *c.step,
+		MinPeriod:     *c.minPeriod,
+		MaxPeriod:     *c.maxPeriod,
+		Top:           *c.top,
+		MinConfidence: *c.minConfidence,
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for _, file := range toRead {
+		f, err := files.OpenReadOnly(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open %q, %s\n", file, err.Error())
+			continue
+		}
+		err = handle(w, *c.json, options, file, f)
+		f.Close()
+		if err != nil {
+			_ = w.Flush()
+			fmt.Fprintf(os.Stderr, "Failed to read %q, %s\n", file, err.Error())
+		}
+	}
+}
+
+// handle writes the periods found in each target of the '.pings' file [r].
+func handle(w io.Writer, asJSON bool, options data.PeriodicityOptions, name string, r io.Reader) error {
+	streams, err := data.NewTargetStreams(r)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		report, err := s.Periodicity(options)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			if err := json.NewEncoder(w).Encode(toJSON(name, s.URL, report)); err != nil {
+				return err
+			}
+			continue
+		}
+		printTable(w, name, s.URL, report)
+	}
+	return nil
+}
+
+func printTable(w io.Writer, name, url string, report *data.PeriodicityReport) {
+	fmt.Fprintf(w, "%s %s: Observed %s | Resampled every %s (%d samples)\n",
+		name, url, report.Observed.Round(time.Second), report.Step.Round(time.Millisecond), report.Samples)
+	if len(report.Periods) == 0 {
+		fmt.Fprint(w, "No periodicity found\n\n")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PERIOD\tFIRST PEAK\tSTRENGTH\tCONFIDENCE\tCYCLES")
+	for _, p := range report.Periods {
+		fmt.Fprintf(tw, "%s\t%s\t%.3f\t%.3f%%\t%d\n",
+			p.Period.Round(time.Second), p.Peak.Format(time.RFC3339Nano), p.Strength, p.Confidence*100, p.Cycles)
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+}
+
+// jsonReport is the schema of the -json output, durations are in nanoseconds.
+type jsonReport struct {
+	File       string       `json:"file"`
+	URL        string       `json:"url"`
+	Periods    []jsonPeriod `json:"periods"`
+	ObservedNS int64        `json:"observed_ns"`
+	StepNS     int64        `json:"step_ns"`
+	Samples    int          `json:"samples"`
+}
+
+type jsonPeriod struct {
+	Peak       string  `json:"peak"`
+	PeriodNS   int64   `json:"period_ns"`
+	Strength   float64 `json:"strength"`
+	Confidence float64 `json:"confidence"`
+	Cycles     int     `json:"cycles"`
+}
+
+func toJSON(name, url string, report *data.PeriodicityReport) jsonReport {
+	periods := make([]jsonPeriod, len(report.Periods))
+	for i, p := range report.Periods {
+		periods[i] = jsonPeriod{
+			Peak:       p.Peak.Format(time.RFC3339Nano),
+			PeriodNS:   p.Period.Nanoseconds(),
+			Strength:   p.Strength,
+			Confidence: p.Confidence,
+			Cycles:     p.Cycles,
+		}
+	}
+	return jsonReport{
+		File:       name,
+		URL:        url,
+		Periods:    periods,
+		ObservedNS: report.Observed.Nanoseconds(),
+		StepNS:     report.Step.Nanoseconds(),
+		Samples:    report.Samples,
+	}
+}
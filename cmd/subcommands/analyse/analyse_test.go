@@ -0,0 +1,76 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package analyse_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/analyse"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestText(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := analyse.Handle(&out, false, data.DefaultPeriodicityOptions, "test.pings", bytes.NewReader(makeTestFile(t, 2*time.Minute)))
+	assert.NilError(t, err)
+	expected := "test.pings www.google.com: Observed 59m59s | Resampled every 1s (3600 samples)\n" +
+		"PERIOD  FIRST PEAK            STRENGTH  CONFIDENCE  CYCLES\n" +
+		"2m0s    2024-08-02T21:05:57Z  0.947     100.000%    29\n\n"
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestTextNone(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := analyse.Handle(&out, false, data.DefaultPeriodicityOptions, "test.pings", bytes.NewReader(makeTestFile(t, 0)))
+	assert.NilError(t, err)
+	expected := "test.pings www.google.com: Observed 59m59s | Resampled every 1s (3600 samples)\n" +
+		"No periodicity found\n\n"
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := analyse.Handle(&out, true, data.DefaultPeriodicityOptions, "test.pings", bytes.NewReader(makeTestFile(t, 2*time.Minute)))
+	assert.NilError(t, err)
+
+	var doc map[string]any
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Check(t, is.Equal(doc["url"], "www.google.com"))
+	assert.Check(t, is.Equal(doc["step_ns"], float64(time.Second)))
+	assert.Check(t, is.Equal(doc["samples"], 3600.0))
+	list := doc["periods"].([]any)
+	assert.Assert(t, is.Len(list, 1))
+	period := list[0].(map[string]any)
+	assert.Check(t, is.Equal(period["peak"], "2024-08-02T21:05:57Z"))
+	assert.Check(t, is.Equal(period["cycles"], 29.0))
+	assert.Check(t, period["confidence"].(float64) > 0.99, period["confidence"])
+}
+
+// makeTestFile is an hour of a point a second with some noise, if [every] isn't zero there's a spike every
+// [every] from 90 seconds in.
+func makeTestFile(t *testing.T, every time.Duration) []byte {
+	t.Helper()
+	r := rand.New(rand.NewPCG(1, 2))
+	return th.WritePingsFile(t, th.Pings(th.PingsStart, 60*60, time.Second, func(i int, p *ping.PingResults) {
+		offset := time.Duration(i) * time.Second
+		p.Data.Duration = 8*time.Millisecond + time.Duration(r.IntN(5000))*time.Microsecond
+		if every != 0 && offset >= 90*time.Second && (offset-90*time.Second)%every == 0 {
+			p.Data.Duration = 120 * time.Millisecond
+		}
+	}))
+}
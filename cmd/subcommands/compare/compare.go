@@ -0,0 +1,352 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package compare
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/draw"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/application"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"github.com/Lexer747/acci-ping/utils/exit"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	json       *bool
+	alpha      *float64
+	minDropped *int
+	maxGap     *time.Duration
+	draw       *bool
+	termSize   *string
+	url        *string
+	logScale   *bool
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		json:    tf.Bool("json", false, "writes the comparison as a json document instead of a table"),
+		alpha:   tf.Float64("alpha", 0.05, "the significance level of the tests, a p-value below this is a significant difference"),
+		minDropped: tf.Int("min-dropped", data.DefaultOutageOptions.MinDropped,
+			"the fewest consecutive dropped packets which are counted as an outage"),
+		maxGap: tf.Duration("max-gap", data.DefaultOutageOptions.MaxGap,
+			"the longest time between two packets which still counts towards the uptime"),
+		draw: tf.Bool("draw", false, "also draws a frame of the graph with the second file overlaid on the first,\n"+
+			"as if both captures started at the same time"),
+		termSize: tf.String("term-size", "", "the size of the frame drawn by -draw, in the form \"<H>x<W>\" e.g. 20x80.\n"+
+			"(default the size of the terminal)",
+			tabflags.AutoComplete{Choices: []string{"15x80", "20x85", "HxW"}}),
+		url:      tf.String("url", "", "the url to read from files which hold the pings of many urls (see -multi-target).", tabflags.AutoComplete{}),
+		logScale: tf.Bool("log-scale", false, "switches the y-axis of -draw to be in logarithmic scaling instead of linear"),
+	}
+
+	f.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: compares two '.pings' files side by side, e.g. before and after changing a router\n"+
+			"\t compare [-json][-alpha P][-draw][-url URL] BEFORE AFTER\n\n"+
+			"e.g. %s compare before.pings after.pings\n\n"+
+			"The latency distributions are compared with the Mann-Whitney U and Kolmogorov-Smirnov tests.\n",
+			os.Args[0], os.Args[0])
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunCompare(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	if c.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Expected exactly two files to compare, got %d. Use -h/--help to print usage instructions.\n",
+			c.NArg())
+		exit.Silent()
+	}
+	if *c.minDropped < 1 {
+		fmt.Fprintf(os.Stderr, "-min-dropped must be at least 1, got %d.\n", *c.minDropped)
+		exit.Silent()
+	}
+	beforeName, afterName := c.Arg(0), c.Arg(1)
+	before, err := files.LoadTarget(beforeName, *c.url)
+	exit.OnErrorMsgf(err, "Couldn't open and read file %q, failed with", beforeName)
+	after, err := files.LoadTarget(afterName, *c.url)
+	exit.OnErrorMsgf(err, "Couldn't open and read file %q, failed with", afterName)
+
+	if *c.draw {
+		// The frame is drawn first as it clears the terminal
+		err = drawOverlaid(*c.termSize, *c.logScale, before, after)
+		exit.OnErrorMsg(err, "Failed to draw the comparison")
+		fmt.Printf("\n\n%s %s is overlaid as if it started at the same time as %s\n\n", graph.OverlayKey(), afterName, beforeName)
+	}
+	comparison := data.Compare(before, after, data.OutageOptions{MinDropped: *c.minDropped, MaxGap: *c.maxGap})
+	w := bufio.NewWriter(os.Stdout)
+	err = report(w, *c.json, *c.alpha, beforeName, afterName, comparison)
+	_ = w.Flush()
+	exit.OnErrorMsg(err, "Failed to write the comparison")
+}
+
+// report writes the comparison of the two files to [w].
+func report(w io.Writer, asJSON bool, alpha float64, beforeName, afterName string, c *data.Comparison) error {
+	if asJSON {
+		return json.NewEncoder(w).Encode(toJSON(alpha, beforeName, afterName, c))
+	}
+	fmt.Fprintf(w, "BEFORE %s %s: %s\n", beforeName, c.BeforeURL, c.BeforeTimeSpan.String())
+	fmt.Fprintf(w, "AFTER  %s %s: %s\n\n", afterName, c.AfterURL, c.AfterTimeSpan.String())
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tBEFORE\tAFTER\tDELTA\tCHANGE")
+	for _, m := range metrics(c) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.name, m.format(m.before), m.format(m.after), m.delta(), m.change())
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+
+	mw, ks := c.MannWhitney, c.KolmogorovSmirnov
+	fmt.Fprintf(w, "Mann-Whitney U: U %.1f | z %.3f | p %.4g | P(before slower) %.3f -> %s\n",
+		mw.U, mw.Z, mw.P, mw.Effect, mannWhitneyConclusion(mw, alpha))
+	fmt.Fprintf(w, "Kolmogorov-Smirnov: D %.4f | p %.4g -> %s\n", ks.D, ks.P, kolmogorovSmirnovConclusion(ks, alpha))
+	return nil
+}
+
+func mannWhitneyConclusion(mw data.MannWhitney, alpha float64) string {
+	switch {
+	case mw.P >= alpha:
+		return fmt.Sprintf("no significant difference in latency (alpha %g)", alpha)
+	case mw.Z > 0:
+		return fmt.Sprintf("after is significantly faster (alpha %g)", alpha)
+	default:
+		return fmt.Sprintf("after is significantly slower (alpha %g)", alpha)
+	}
+}
+
+func kolmogorovSmirnovConclusion(ks data.KolmogorovSmirnov, alpha float64) string {
+	if ks.P >= alpha {
+		return fmt.Sprintf("no significant difference in the latency distribution (alpha %g)", alpha)
+	}
+	return fmt.Sprintf("the latency distributions are significantly different (alpha %g)", alpha)
+}
+
+type kind int
+
+const (
+	durationKind kind = iota
+	countKind
+	percentKind
+)
+
+// metric is a single row of the comparison table.
+type metric struct {
+	name          string
+	jsonName      string
+	before, after float64
+	kind          kind
+}
+
+func metrics(c *data.Comparison) []metric {
+	d := func(name, jsonName string, before, after time.Duration) metric {
+		return metric{name: name, jsonName: jsonName, before: float64(before), after: float64(after), kind: durationKind}
+	}
+	return []metric{
+		{
+			name: "Packets", jsonName: "packets", kind: countKind,
+			before: float64(c.Before.GoodCount + c.Before.PacketsDropped),
+			after:  float64(c.After.GoodCount + c.After.PacketsDropped),
+		},
+		d("Mean", "mean_ns", time.Duration(c.Before.Mean), time.Duration(c.After.Mean)),
+		d("SD", "sd_ns", time.Duration(c.Before.StandardDeviation), time.Duration(c.After.StandardDeviation)),
+		d("Min", "min_ns", c.Before.Min, c.After.Min),
+		d("p50", "p50_ns", c.Before.Quantile(0.5), c.After.Quantile(0.5)),
+		d("p95", "p95_ns", c.Before.Quantile(0.95), c.After.Quantile(0.95)),
+		d("p99", "p99_ns", c.Before.Quantile(0.99), c.After.Quantile(0.99)),
+		d("Max", "max_ns", c.Before.Max, c.After.Max),
+		d("Jitter", "jitter_ns", c.Before.Jitter(), c.After.Jitter()),
+		d("Mean Jitter", "mean_jitter_ns", c.Before.MeanJitter(), c.After.MeanJitter()),
+		{
+			name: "Packet Loss", jsonName: "packet_loss", kind: percentKind,
+			before: lossOf(c.Before), after: lossOf(c.After),
+		},
+		{
+			name: "Outages", jsonName: "outages", kind: countKind,
+			before: float64(len(c.BeforeOutages.Outages)), after: float64(len(c.AfterOutages.Outages)),
+		},
+		d("Downtime", "downtime_ns", c.BeforeOutages.Downtime, c.AfterOutages.Downtime),
+		{
+			name: "Uptime", jsonName: "uptime", kind: percentKind,
+			before: c.BeforeOutages.Uptime(), after: c.AfterOutages.Uptime(),
+		},
+		d("MTBF", "mtbf_ns", c.BeforeOutages.MTBF(), c.AfterOutages.MTBF()),
+		d("MTTR", "mttr_ns", c.BeforeOutages.MTTR(), c.AfterOutages.MTTR()),
+	}
+}
+
+// lossOf is the packet loss of [s] which is 0 rather than NaN for an empty capture.
+func lossOf(s *data.Stats) float64 {
+	if s.GoodCount+s.PacketsDropped == 0 {
+		return 0
+	}
+	return s.PacketLoss()
+}
+
+func (m metric) format(v float64) string {
+	switch m.kind {
+	case durationKind:
+		return timeutils.HumanString(time.Duration(v), 4)
+	case countKind:
+		return fmt.Sprintf("%d", int64(v))
+	case percentKind:
+		return fmt.Sprintf("%.3f%%", v*100)
+	default:
+		panic("exhaustive:enforce")
+	}
+}
+
+func (m metric) delta() string {
+	difference := m.after - m.before
+	sign := "+"
+	if difference < 0 {
+		sign = "-"
+		difference = -difference
+	}
+	switch m.kind {
+	case durationKind:
+		return sign + timeutils.HumanString(time.Duration(difference), 4)
+	case countKind:
+		return fmt.Sprintf("%s%d", sign, int64(difference))
+	case percentKind:
+		// Percentage points, the relative change of a percentage is rarely what's wanted.
+		return fmt.Sprintf("%s%.3fpp", sign, difference*100)
+	default:
+		panic("exhaustive:enforce")
+	}
+}
+
+// change is the relative change, empty if it doesn't make sense.
+func (m metric) change() string {
+	if m.kind == percentKind || m.before == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.1f%%", (m.after-m.before)/m.before*100)
+}
+
+// jsonComparison is the schema of the -json output.
+type jsonComparison struct {
+	Before            jsonCapture           `json:"before"`
+	After             jsonCapture           `json:"after"`
+	Delta             map[string]float64    `json:"delta"`
+	MannWhitney       jsonMannWhitney       `json:"mann_whitney"`
+	KolmogorovSmirnov jsonKolmogorovSmirnov `json:"kolmogorov_smirnov"`
+	Alpha             float64               `json:"alpha"`
+}
+
+type jsonCapture struct {
+	File    string             `json:"file"`
+	URL     string             `json:"url"`
+	Begin   string             `json:"begin"`
+	End     string             `json:"end"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+type jsonMannWhitney struct {
+	U           float64 `json:"u"`
+	Z           float64 `json:"z"`
+	P           float64 `json:"p"`
+	Effect      float64 `json:"effect"`
+	Significant bool    `json:"significant"`
+}
+
+type jsonKolmogorovSmirnov struct {
+	D           float64 `json:"d"`
+	P           float64 `json:"p"`
+	Significant bool    `json:"significant"`
+}
+
+func toJSON(alpha float64, beforeName, afterName string, c *data.Comparison) jsonComparison {
+	ret := jsonComparison{
+		Before: jsonCapture{
+			File:    beforeName,
+			URL:     c.BeforeURL,
+			Begin:   c.BeforeTimeSpan.Begin.Format(time.RFC3339Nano),
+			End:     c.BeforeTimeSpan.End.Format(time.RFC3339Nano),
+			Metrics: map[string]float64{},
+		},
+		After: jsonCapture{
+			File:    afterName,
+			URL:     c.AfterURL,
+			Begin:   c.AfterTimeSpan.Begin.Format(time.RFC3339Nano),
+			End:     c.AfterTimeSpan.End.Format(time.RFC3339Nano),
+			Metrics: map[string]float64{},
+		},
+		Delta: map[string]float64{},
+		MannWhitney: jsonMannWhitney{
+			U:           c.MannWhitney.U,
+			Z:           c.MannWhitney.Z,
+			P:           c.MannWhitney.P,
+			Effect:      c.MannWhitney.Effect,
+			Significant: c.MannWhitney.P < alpha,
+		},
+		KolmogorovSmirnov: jsonKolmogorovSmirnov{
+			D:           c.KolmogorovSmirnov.D,
+			P:           c.KolmogorovSmirnov.P,
+			Significant: c.KolmogorovSmirnov.P < alpha,
+		},
+		Alpha: alpha,
+	}
+	for _, m := range metrics(c) {
+		ret.Before.Metrics[m.jsonName] = m.before
+		ret.After.Metrics[m.jsonName] = m.after
+		ret.Delta[m.jsonName] = m.after - m.before
+	}
+	return ret
+}
+
+// drawOverlaid draws a single frame of [before] with [after] overlaid to the stdout.
+func drawOverlaid(termSize string, logScale bool, before, after *data.Data) error {
+	var term *terminal.Terminal
+	var err error
+	if termSize != "" {
+		term, err = terminal.NewParsedFixedSizeTerminal(termSize)
+	} else {
+		term, err = terminal.NewTerminal()
+		if errors.Is(err, terminal.TermSizeError) {
+			term, err = terminal.NewDebuggingTerminal(terminal.Size{Height: 20, Width: 100})
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err = application.LoadTheme("", term); err != nil {
+		return err
+	}
+	graph.StartUp()
+	scale := graph.Linear
+	if logScale {
+		scale = graph.Logarithmic
+	}
+	g := graph.NewGraph(context.Background(), graph.GraphConfiguration{
+		Terminal:      term,
+		DrawingBuffer: draw.NewPaintBuffer(),
+		Presentation:  graph.Presentation{YAxisScale: scale},
+		Data:          before,
+		Overlay:       after,
+	})
+	return g.OneFrame()
+}
@@ -0,0 +1,70 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package compare_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/compare"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestText(t *testing.T) {
+	t.Parallel()
+	c := data.Compare(makeData(8*time.Millisecond, 0), makeData(12*time.Millisecond, 3), data.DefaultOutageOptions)
+	var out bytes.Buffer
+	assert.NilError(t, compare.Report(&out, false, 0.05, "before.pings", "after.pings", c))
+	actual := out.String()
+	assert.Check(t, is.Contains(actual, "BEFORE before.pings www.google.com: "))
+	assert.Check(t, is.Contains(actual, "AFTER  after.pings www.google.com: "))
+	assert.Check(t, is.Contains(actual, "Packets      40        40       +0        +0.0%"))
+	assert.Check(t, is.Contains(actual, "Outages      0         1        +1"))
+	assert.Check(t, is.Contains(actual, "Packet Loss  0.000%    7.500%   +7.500pp"))
+	assert.Check(t, is.Contains(actual, "after is significantly slower (alpha 0.05)"))
+	assert.Check(t, is.Contains(actual, "the latency distributions are significantly different (alpha 0.05)"))
+
+	c = data.Compare(makeData(8*time.Millisecond, 0), makeData(8*time.Millisecond, 0), data.DefaultOutageOptions)
+	out.Reset()
+	assert.NilError(t, compare.Report(&out, false, 0.05, "before.pings", "after.pings", c))
+	assert.Check(t, is.Contains(out.String(), "no significant difference in latency (alpha 0.05)"))
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	c := data.Compare(makeData(8*time.Millisecond, 0), makeData(12*time.Millisecond, 3), data.DefaultOutageOptions)
+	var out bytes.Buffer
+	assert.NilError(t, compare.Report(&out, true, 0.01, "before.pings", "after.pings", c))
+
+	var doc map[string]any
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Check(t, is.Equal(doc["alpha"], 0.01))
+	before := doc["before"].(map[string]any)
+	assert.Check(t, is.Equal(before["file"], "before.pings"))
+	assert.Check(t, is.Equal(before["metrics"].(map[string]any)["outages"], 0.0))
+	after := doc["after"].(map[string]any)
+	assert.Check(t, is.Equal(after["metrics"].(map[string]any)["outages"], 1.0))
+	assert.Check(t, is.Equal(doc["delta"].(map[string]any)["packet_loss"], 0.075))
+	assert.Check(t, is.Equal(doc["mann_whitney"].(map[string]any)["significant"], true))
+	assert.Check(t, is.Equal(doc["kolmogorov_smirnov"].(map[string]any)["significant"], true))
+}
+
+// makeData is 40 points a second apart of around [latency], where the last [dropped] points are dropped.
+func makeData(latency time.Duration, dropped int) *data.Data {
+	return th.MakeData(th.Pings(th.PingsStart, 40, time.Second, func(i int, p *ping.PingResults) {
+		p.Data.Duration = latency + time.Duration(i%5)*100*time.Microsecond
+		if i >= 40-dropped {
+			p.Data = ping.PingDataPoint{Timestamp: p.Data.Timestamp, DropReason: ping.Timeout}
+		}
+	}))
+}
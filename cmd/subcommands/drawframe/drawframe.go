@@ -36,6 +36,8 @@ type Config struct {
 	*tabflags.FlagSet
 
 	debugFollow *bool
+	periodicity *bool
+	stitch      *bool
 	termSize    *string
 	theme       *string
 	yAxisScale  *bool
@@ -51,6 +53,10 @@ func GetFlags(info *application.BuildInfo) *Config {
 		FlagSet:     tf,
 
 		debugFollow: tf.Bool("debug-follow", false, "switches drawing to followLastSpan."),
+		periodicity: tf.Bool("periodicity", false, "draws faint vertical guides at the peaks of the periods found in the latency, see\n"+
+			"'analyse -periodicity'."),
+		stitch: tf.Bool("stitch", false, "when given a folder, all the '.pings' files of the same url in it are drawn as one\n"+
+			"continuous graph (e.g. the files of a rotating -file capture) rather than one frame per file."),
 		termSize: tf.String("term-size", "", "controls the terminal size and fixes it to the input,"+
 			" input is in the form \"<H>x<W>\" e.g. 20x80. H and W must be integers - where H == height, and W == width of the terminal.",
 			tabflags.AutoComplete{Choices: []string{"15x80", "20x85", "HxW"}}),
@@ -102,43 +108,81 @@ func RunDrawFrame(c *Config) {
 	graph.StartUp()
 
 	for _, path := range toPrint {
-		run(term, path, c.Profiling(), *c.yAxisScale, *c.debugFollow, c.DebugStrict())
+		run(term, path, c.Profiling(), *c.yAxisScale, *c.debugFollow, *c.stitch, *c.periodicity, c.DebugStrict())
 	}
 	fmt.Println()
 	fmt.Println()
 	fmt.Println()
 }
 
-func run(term *terminal.Terminal, path string, profiling, logScale, debugFollow, debugStrict bool) {
+func run(term *terminal.Terminal, path string, profiling, logScale, debugFollow, stitch, periodicity, debugStrict bool) {
 	fs, err := os.Stat(path)
 	exit.OnErrorMsgf(err, "Couldn't stat path %q, failed with", path)
-	if fs.IsDir() {
+	switch {
+	case fs.IsDir() && stitch:
+		for _, d := range stitchFolder(path) {
+			drawData(d, term, profiling, logScale, debugFollow, periodicity, debugStrict)
+		}
+	case fs.IsDir():
 		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
 			if filepath.Ext(p) != ".pings" {
 				return nil
 			}
-			do(p, term, profiling, logScale, debugFollow, debugStrict)
+			do(p, term, profiling, logScale, debugFollow, periodicity, debugStrict)
 			return nil
 		})
 		exit.OnErrorMsgf(err, "Couldn't walk path %q, failed with", path)
-	} else {
-		do(path, term, profiling, logScale, debugFollow, debugStrict)
+	default:
+		do(path, term, profiling, logScale, debugFollow, periodicity, debugStrict)
+	}
+}
+
+// stitchFolder merges all the '.pings' files directly in the folder into one [data.Data] per url, in the order
+// each url is first seen. Every target of a file which holds many is stitched with the other files of it's url.
+func stitchFolder(path string) []*data.Data {
+	entries, err := os.ReadDir(path)
+	exit.OnErrorMsgf(err, "Couldn't read folder %q, failed with", path)
+	byURL := map[string][]*data.Data{}
+	urls := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pings" {
+			continue
+		}
+		t, err := files.LoadTargets(filepath.Join(path, entry.Name()))
+		exit.OnErrorMsgf(err, "Couldn't open and read file %q, failed with", entry.Name())
+		for _, d := range t.Targets {
+			if _, found := byURL[d.URL]; !found {
+				urls = append(urls, d.URL)
+			}
+			byURL[d.URL] = append(byURL[d.URL], d)
+		}
+	}
+	ret := make([]*data.Data, len(urls))
+	for i, url := range urls {
+		ret[i] = data.Merge(byURL[url]...)
 	}
+	return ret
 }
 
-func do(path string, term *terminal.Terminal, profiling, logScale, debugFollow, debugStrict bool) {
-	d, f, err := files.LoadFile(path)
+// do draws a frame of the file at [path], or a frame per url if the file holds the pings of many.
+func do(path string, term *terminal.Terminal, profiling, logScale, debugFollow, periodicity, debugStrict bool) {
+	t, err := files.LoadTargets(path)
 	exit.OnErrorMsg(err, "Couldn't open and read file, failed with")
-	f.Close()
-	if err != nil {
-		panic(err.Error())
+	for _, d := range t.Targets {
+		drawData(d, term, profiling, logScale, debugFollow, periodicity, debugStrict)
 	}
+}
 
+func drawData(d *data.Data, term *terminal.Terminal, profiling, logScale, debugFollow, periodicity, debugStrict bool) {
 	scale := graph.Linear
 	if logScale {
 		scale = graph.Logarithmic
 	}
-	g := makeGraph(term, debugFollow, scale, debugStrict, d)
+	var periods []data.Period
+	if periodicity {
+		periods = d.Periodicity(data.DefaultPeriodicityOptions).Periods
+	}
+	g := makeGraph(term, debugFollow, scale, debugStrict, d, periods)
 
 	// TODO don't profile like this when iterating over a folder of inputs.
 	if profiling {
@@ -186,7 +230,14 @@ func printGraph(g *graph.Graph) {
 	}
 }
 
-func makeGraph(term *terminal.Terminal, debugFollow bool, scale graph.YAxisScale, debugStrict bool, d *data.Data) *graph.Graph {
+func makeGraph(
+	term *terminal.Terminal,
+	debugFollow bool,
+	scale graph.YAxisScale,
+	debugStrict bool,
+	d *data.Data,
+	periods []data.Period,
+) *graph.Graph {
 	g := graph.NewGraph(
 		context.Background(),
 		graph.GraphConfiguration{
@@ -198,6 +249,7 @@ func makeGraph(term *terminal.Terminal, debugFollow bool, scale graph.YAxisScale
 			},
 			DebugStrict: debugStrict,
 			Data:        d,
+			Periods:     periods,
 		},
 	)
 	return g
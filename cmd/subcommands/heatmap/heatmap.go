@@ -0,0 +1,138 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package heatmap
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/application"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/exit"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	bucket   *time.Duration
+	days     *int
+	loss     *bool
+	theme    *string
+	timezone *string
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		bucket: tf.Duration("bucket", data.DefaultHeatmapOptions.Bucket,
+			"the time of day each column covers, e.g. 15m, it must divide a day evenly"),
+		days: tf.Int("days", 0, "the most days to draw, the most recent are kept. 0 draws every day"),
+		loss: tf.Bool("loss", false, "colours each cell by the packet loss instead of the p95 latency"),
+		theme: tf.String("theme", "", "the colour theme (either a path or builtin theme name) to draw with, if empty\n"+
+			"there's no colour. The builtin themes are:\n"+strings.Join(themes.DescribeBuiltins(), "\n"),
+			tabflags.AutoComplete{Choices: themes.GetBuiltInNames(), WantsFile: true, FileExt: ".json"}),
+		timezone: tf.String("tz", "Local", "the time zone which decides when each day starts, e.g. UTC or Europe/London",
+			tabflags.AutoComplete{Choices: []string{"Local", "UTC"}}),
+	}
+
+	f.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: draws a calendar heatmap of '.pings' files, a row per day and a column per time of day\n"+
+			"\t heatmap [-loss][-bucket DURATION][-days N][-tz ZONE][-theme THEME] FILES\n\n"+
+			"e.g. %s heatmap -bucket 15m my_ping_capture.ping\n", os.Args[0], os.Args[0])
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunHeatmap(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	toRead := c.Args()
+	if len(toRead) == 0 {
+		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
+		exit.Success()
+	}
+	if *c.bucket < time.Minute || (24*time.Hour)%*c.bucket != 0 {
+		fmt.Fprintf(os.Stderr, "-bucket must be at least a minute and divide a day evenly, got %s.\n", *c.bucket)
+		exit.Silent()
+	}
+	location, err := time.LoadLocation(*c.timezone)
+	exit.OnErrorMsgf(err, "Invalid -tz")
+	if *c.theme != "" {
+		term, err := terminal.NewTerminal()
+		exit.OnErrorMsg(err, "failed to open terminal for the theme")
+		exit.OnErrorMsg(application.LoadTheme(*c.theme, term), "failed to use theme")
+	}
+	options := data.HeatmapOptions{Bucket: *c.bucket, Location: location}
+	view := graph.HeatmapView{Metric: graph.HeatmapLatency, MaxDays: *c.days}
+	if *c.loss {
+		view.Metric = graph.HeatmapLoss
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for _, file := range toRead {
+		f, err := files.OpenReadOnly(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open %q, %s\n", file, err.Error())
+			continue
+		}
+		err = handle(w, options, view, file, f)
+		f.Close()
+		if err != nil {
+			_ = w.Flush()
+			fmt.Fprintf(os.Stderr, "Failed to read %q, %s\n", file, err.Error())
+		}
+	}
+}
+
+// handle writes the heatmap of each target of the '.pings' file [r].
+func handle(w io.Writer, options data.HeatmapOptions, view graph.HeatmapView, name string, r io.Reader) error {
+	streams, err := data.NewTargetStreams(r)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		h, err := s.Heatmap(options)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s %s:\n", name, s.URL)
+		lines := graph.HeatmapLines(h, view)
+		if lines == nil {
+			fmt.Fprint(w, "No packets.\n\n")
+			continue
+		}
+		width := 0
+		for _, l := range lines {
+			width = max(width, l.Len())
+		}
+		for _, l := range lines {
+			// Aligned as it would be inside a [gui.Box], only the titles are centred.
+			padding := 0
+			if l.Alignment == gui.Centre && l.Len() > 0 {
+				padding = (width - l.Len()) / 2
+			}
+			fmt.Fprintln(w, strings.Repeat(" ", padding)+l.ToPrint)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package heatmap_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/heatmap"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLatency(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	options := data.HeatmapOptions{Bucket: 2 * time.Hour, Location: time.UTC}
+	view := graph.HeatmapView{Metric: graph.HeatmapLatency}
+	err := heatmap.Handle(&out, options, view, "test.pings", bytes.NewReader(makeTestFile(t)))
+	assert.NilError(t, err)
+	expected := []string{
+		"test.pings www.google.com:",
+		"             p95 latency by time of day (UTC)",
+		"",
+		"           00  02  04  06  08  10  12  14  16  18  20  22",
+		"Fri 02 Aug                                     ████××××",
+		"Sat 03 Aug                                     ████░░░░",
+		"",
+		"░ ≤ 14.1ms  ▒ ≤ 20ms  ▓ ≤ 28.3ms  █ > 28.3ms  × all dropped",
+		"",
+		"",
+	}
+	assert.Check(t, is.Equal(trimLines(out.String()), strings.Join(expected, "\n")))
+}
+
+func TestLoss(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	options := data.HeatmapOptions{Bucket: 2 * time.Hour, Location: time.UTC}
+	view := graph.HeatmapView{Metric: graph.HeatmapLoss, MaxDays: 1}
+	err := heatmap.Handle(&out, options, view, "test.pings", bytes.NewReader(makeTestFile(t)))
+	assert.NilError(t, err)
+	expected := []string{
+		"test.pings www.google.com:",
+		"             packet loss by time of day (UTC)",
+		"",
+		"           00  02  04  06  08  10  12  14  16  18  20  22",
+		"Sat 03 Aug                                     ░░░░░░░░",
+		"",
+		"              ░ ≤ 0%  ▒ ≤ 1%  ▓ ≤ 5%  █ > 5%",
+		"",
+		"",
+	}
+	assert.Check(t, is.Equal(trimLines(out.String()), strings.Join(expected, "\n")))
+}
+
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// makeTestFile is two evenings, the first has congestion at 6pm and an outage at 8pm, the second has
+// congestion at 6pm.
+func makeTestFile(t *testing.T) []byte {
+	t.Helper()
+	start := time.Date(2024, time.August, 2, 18, 0, 0, 0, time.UTC)
+	return th.WritePingsFile(t, th.Pings(start, 8, time.Hour, func(i int, p *ping.PingResults) {
+		if i >= 4 {
+			// The same hours of the next evening
+			p.Data.Timestamp = p.Data.Timestamp.Add(20 * time.Hour)
+		}
+		p.Data.Duration = 10 * time.Millisecond
+		switch {
+		case p.Data.Timestamp.Hour() < 20:
+			p.Data.Duration = 40 * time.Millisecond
+		case p.Data.Timestamp.Day() == 2:
+			p.Data = ping.PingDataPoint{Timestamp: p.Data.Timestamp, DropReason: ping.Timeout}
+		}
+	}))
+}
@@ -0,0 +1,125 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package histogram
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/utils/application"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/exit"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	bins  *int
+	log   *bool
+	width *int
+	theme *string
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		bins:    tf.Int("bins", graph.DefaultHistogramOptions.Bins, "the most bars to draw"),
+		log: tf.Bool("log", false, "sizes the bins logarithmically, this spreads out the fast points and squashes the\n"+
+			"long tail"),
+		width: tf.Int("width", graph.DefaultHistogramOptions.BarWidth, "the width of the longest bar"),
+		theme: tf.String("theme", "", "the colour theme (either a path or builtin theme name) to draw with, if empty\n"+
+			"there's no colour. The builtin themes are:\n"+strings.Join(themes.DescribeBuiltins(), "\n"),
+			tabflags.AutoComplete{Choices: themes.GetBuiltInNames(), WantsFile: true, FileExt: ".json"}),
+	}
+
+	f.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: draws the round trip time distribution of '.pings' files as a histogram\n"+
+			"\t histogram [-log][-bins N][-width N][-theme THEME] FILES\n\n"+
+			"e.g. %s histogram my_ping_capture.ping\n", os.Args[0], os.Args[0])
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunHistogram(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	toRead := c.Args()
+	if len(toRead) == 0 {
+		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
+		exit.Success()
+	}
+	if *c.bins < 1 || *c.width < 1 {
+		fmt.Fprintf(os.Stderr, "-bins and -width must be at least 1, got %d and %d.\n", *c.bins, *c.width)
+		exit.Silent()
+	}
+	if *c.theme != "" {
+		term, err := terminal.NewTerminal()
+		exit.OnErrorMsg(err, "failed to open terminal for the theme")
+		exit.OnErrorMsg(application.LoadTheme(*c.theme, term), "failed to use theme")
+	}
+	options := graph.HistogramOptions{Bins: *c.bins, Log: *c.log, BarWidth: *c.width}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for _, file := range toRead {
+		f, err := files.OpenReadOnly(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open %q, %s\n", file, err.Error())
+			continue
+		}
+		err = handle(w, options, file, f)
+		f.Close()
+		if err != nil {
+			_ = w.Flush()
+			fmt.Fprintf(os.Stderr, "Failed to read %q, %s\n", file, err.Error())
+		}
+	}
+}
+
+// handle writes the histogram of each target of the '.pings' file [r], only the stats are needed so none of
+// the points are read.
+func handle(w io.Writer, options graph.HistogramOptions, name string, r io.Reader) error {
+	streams, err := data.NewTargetStreams(r)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		fmt.Fprintf(w, "%s %s:\n", name, s.URL)
+		lines := graph.HistogramLines(s.Header.Stats, options)
+		if lines == nil {
+			fmt.Fprint(w, "No good packets.\n\n")
+			continue
+		}
+		width := 0
+		for _, l := range lines {
+			width = max(width, l.Len())
+		}
+		for _, l := range lines {
+			// Aligned as it would be inside a [gui.Box], only the titles are centred.
+			padding := 0
+			if l.Alignment == gui.Centre && l.Len() > 0 {
+				padding = (width - l.Len()) / 2
+			}
+			fmt.Fprintln(w, strings.Repeat(" ", padding)+l.ToPrint)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
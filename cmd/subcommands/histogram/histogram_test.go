@@ -0,0 +1,62 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package histogram_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/histogram"
+	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestText(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	options := graph.HistogramOptions{Bins: 4, BarWidth: 10}
+	err := histogram.Handle(&out, options, "test.pings", bytes.NewReader(makeTestFile(t)))
+	assert.NilError(t, err)
+	expected := "test.pings www.google.com:\n" +
+		"RTT distribution (10 points, linear bins)\n" +
+		"\n" +
+		"10ms - 20ms │██████████ 6 ◀ p50\n" +
+		"20ms - 30ms │           0\n" +
+		"30ms - 40ms │           0\n" +
+		"40ms - 50ms │██████     4 ◀ p95 p99\n" +
+		"\n" +
+		"     p50 10ms | p95 50ms | p99 50ms\n" +
+		"\n"
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestNoGoodPackets(t *testing.T) {
+	t.Parallel()
+	b := th.WritePingsFile(t, th.Pings(th.PingsStart, 1, time.Second, func(_ int, p *ping.PingResults) {
+		p.Data = ping.PingDataPoint{Timestamp: p.Data.Timestamp, DropReason: ping.Timeout}
+	}))
+
+	var out bytes.Buffer
+	err := histogram.Handle(&out, graph.DefaultHistogramOptions, "test.pings", bytes.NewReader(b))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out.String(), "test.pings www.google.com:\nNo good packets.\n\n"))
+}
+
+// makeTestFile is a point a second, a fast mode at 10ms and a slow mode at 50ms.
+func makeTestFile(t *testing.T) []byte {
+	t.Helper()
+	return th.WritePingsFile(t, th.Pings(th.PingsStart, 10, time.Second, func(i int, p *ping.PingResults) {
+		p.Data.Duration = 10 * time.Millisecond
+		if i%5 >= 3 {
+			p.Data.Duration = 50 * time.Millisecond
+		}
+	}))
+}
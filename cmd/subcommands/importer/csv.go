@@ -0,0 +1,165 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package importer
+
+import (
+	"encoding/csv"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// csvHeaderPrefix is the start of the first line written by 'rawdata -csv', legacyCSVHeaderPrefix is the start
+// of the first line written by older versions.
+const (
+	csvHeaderPrefix       = "timestamp,"
+	legacyCSVHeaderPrefix = "timestamp("
+)
+
+// parseCSV reads the output of 'rawdata -csv', see docs/rawdata.md for the columns. The URL is read from the
+// url column, older exports don't have it so it must be provided with -url.
+func parseCSV(lines []string) (*parsed, error) {
+	if len(lines) > 0 && strings.HasPrefix(lines[0], legacyCSVHeaderPrefix) {
+		return parseLegacyCSV(lines)
+	}
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], csvHeaderPrefix) {
+		return nil, errors.Errorf("expected the CSV to start with a %q header row", csvHeaderPrefix+"...")
+	}
+	r := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, required := range []string{"timestamp", "latency_ns", "drop_reason_code", "ip", "annotation"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.Errorf("expected the CSV to have a %q column", required)
+		}
+	}
+	ret := &parsed{}
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return ret, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		lineNumber, _ := r.FieldPos(0)
+		if i, ok := columns["url"]; ok {
+			if err := ret.setURL(row[i]); err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, row[columns["timestamp"]])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid timestamp", lineNumber)
+		}
+		if text := row[columns["annotation"]]; text != "" {
+			ret.annotations = append(ret.annotations, data.Annotation{Timestamp: timestamp, Text: text})
+			continue
+		}
+		latency, err := strconv.ParseInt(row[columns["latency_ns"]], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid latency", lineNumber)
+		}
+		code, err := strconv.ParseUint(row[columns["drop_reason_code"]], 10, 8)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid drop reason code", lineNumber)
+		}
+		ret.addPoint(timestamp, time.Duration(latency), ping.Dropped(code), net.ParseIP(row[columns["ip"]]))
+	}
+}
+
+// parseLegacyCSV reads the output of 'rawdata -csv' from older versions. This isn't a standard CSV as every
+// field is written with a go %q, so quotes are escaped with a backslash rather than doubled.
+//
+// The columns are timestamp, latency, dropped, ip, header, annotation. The header row only has the header
+// column set (the [data.Data.String] of the exported file) which is where the URL comes from, annotation
+// rows only have the timestamp and annotation set. The oldest exports have no annotation column.
+func parseLegacyCSV(lines []string) (*parsed, error) {
+	ret := &parsed{}
+	for i, line := range lines[1:] {
+		lineNumber := i + 2
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields, err := splitQuoted(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		if len(fields) != 5 && len(fields) != 6 {
+			return nil, errors.Errorf("line %d: expected 5 or 6 columns but found %d", lineNumber, len(fields))
+		}
+		if fields[4] != "" {
+			if url, _, found := strings.Cut(fields[4], ": PingsMeta#"); found {
+				ret.url = url
+			}
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid timestamp", lineNumber)
+		}
+		if len(fields) == 6 && fields[5] != "" {
+			ret.annotations = append(ret.annotations, data.Annotation{Timestamp: timestamp, Text: fields[5]})
+			continue
+		}
+		duration, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid latency", lineNumber)
+		}
+		reason, ok := ping.ParseDropped(fields[2])
+		if !ok {
+			return nil, errors.Errorf("line %d: unknown drop reason %q", lineNumber, fields[2])
+		}
+		ret.addPoint(timestamp, duration, reason, net.ParseIP(fields[3]))
+	}
+	return ret, nil
+}
+
+// splitQuoted splits a comma separated line where each field is either empty or a go quoted string.
+func splitQuoted(line string) ([]string, error) {
+	ret := []string{}
+	for {
+		if !strings.HasPrefix(line, `"`) {
+			field, rest, found := strings.Cut(line, ",")
+			ret = append(ret, field)
+			if !found {
+				return ret, nil
+			}
+			line = rest
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid quoted field %q", line)
+		}
+		field, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid quoted field %q", quoted)
+		}
+		ret = append(ret, field)
+		line = line[len(quoted):]
+		if line == "" {
+			return ret, nil
+		}
+		if line[0] != ',' {
+			return nil, errors.Errorf("expected a comma after %s", quoted)
+		}
+		line = line[1:]
+	}
+}
@@ -0,0 +1,22 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package importer
+
+import (
+	"io"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+)
+
+func Parse(r io.Reader, format string, start time.Time, layout string) (*data.Data, error) {
+	p, err := parse(r, format, textOptions{start: start, loc: time.UTC, layout: layout, interval: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return p.toData(), nil
+}
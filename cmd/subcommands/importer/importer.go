@@ -0,0 +1,245 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package importer
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"github.com/Lexer747/acci-ping/utils/exit"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+const (
+	autoFormat    = "auto"
+	csvFormat     = "csv"
+	iputilsFormat = "iputils"
+	windowsFormat = "windows"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	format     *string
+	output     *string
+	url        *string
+	start      *string
+	timeLayout *string
+	interval   *time.Duration
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, "")
+	ret := &Config{
+		FlagSet: tf,
+		format: tf.String("format", autoFormat, "the format of the input, one of 'csv' (as written by 'rawdata -format csv'),\n"+
+			"'iputils' (the linux ping, ideally run with -D) or 'windows' (the windows ping). 'auto' will guess from the input.",
+			tabflags.AutoComplete{Choices: []string{autoFormat, csvFormat, iputilsFormat, windowsFormat}}),
+		output: tf.String("out", "", "the '.pings' file to write the imported result into, must not already exist.",
+			tabflags.AutoComplete{WantsFile: true, FileExt: ".pings"}),
+		url: tf.String("url", "", "the URL to record in the '.pings' file. (default the URL found in the input)",
+			tabflags.AutoComplete{}),
+		start: tf.String("start", "", "the time of the first ping, used for inputs which don't record timestamps (e.g. \"2024-08-02 21:04\").",
+			tabflags.AutoComplete{}),
+		timeLayout: tf.String("time-layout", "", "a go time layout used to parse the timestamp a line is prefixed with in 'windows' logs.\n"+
+			"(default \"2006-01-02 15:04:05\" and similar ISO 8601 formats)",
+			tabflags.AutoComplete{}),
+		interval: tf.Duration("interval", time.Second, "the time between pings, used with -start for inputs which don't record timestamps."),
+	}
+
+	f.Usage = func() {
+		var programName = "acci-ping " + ansi.Green("import")
+
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: reads the output of other ping tools and writes it as a '.pings' file\n"+
+			"\t import [-format FORMAT][-url URL][-start TIME][-interval DURATION][-time-layout LAYOUT] -out FILE FILE\n\n"+
+			"e.g. '%s -out history.pings ping_-D_output.txt'\n", programName, programName)
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunImport(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	if c.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Expected exactly one input file. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	if *c.output == "" {
+		fmt.Fprintf(os.Stderr, "No output file given, use -out FILE. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	opts, err := c.textOptions()
+	exit.OnError(err)
+	input := c.Arg(0)
+	in, err := os.OpenFile(input, os.O_RDONLY, 0)
+	exit.OnErrorMsgf(err, "Failed to open %q", input)
+	defer in.Close()
+	p, err := parse(in, *c.format, opts)
+	exit.OnErrorMsgf(err, "Failed to import %q", input)
+	if *c.url != "" {
+		p.url = *c.url
+	}
+	if p.url == "" {
+		exit.OnError(errors.Errorf("No URL found in %q, use -url to provide one.", input))
+	}
+	d := p.toData()
+
+	f, err := os.OpenFile(*c.output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o777)
+	exit.OnErrorMsgf(err, "Failed to create %q", *c.output)
+	defer f.Close()
+	exit.OnErrorMsgf(d.AsCompact(f), "Failed to write %q", *c.output)
+	fmt.Fprintf(os.Stdout, "Imported %d points into %q\n\t%s\n", d.TotalCount, *c.output, d.String())
+}
+
+// textOptions controls how the plain text formats are parsed, these formats often lack timestamps or print
+// them in a locale specific way.
+type textOptions struct {
+	// start is the time of the first ping if the input doesn't have timestamps, the zero value means the input
+	// must have timestamps.
+	start    time.Time
+	loc      *time.Location
+	layout   string
+	interval time.Duration
+}
+
+func (c *Config) textOptions() (textOptions, error) {
+	ret := textOptions{
+		loc:      time.Local,
+		layout:   *c.timeLayout,
+		interval: *c.interval,
+	}
+	if ret.interval <= 0 {
+		return ret, errors.Errorf("invalid -interval %s, must be positive", ret.interval)
+	}
+	if *c.start != "" {
+		now := time.Now()
+		start, err := timeutils.ParseTimeOrOffset(*c.start, now, now, ret.loc)
+		if err != nil {
+			return ret, errors.Wrap(err, "invalid -start")
+		}
+		ret.start = start
+	}
+	return ret, nil
+}
+
+// timestampOf returns the time of the n'th ping (zero indexed) when the input has no timestamps.
+func (o textOptions) timestampOf(n int64) (time.Time, error) {
+	if o.start.IsZero() {
+		return time.Time{}, errors.New("input has no timestamps, use -start to set the time of the first ping")
+	}
+	return o.start.Add(time.Duration(n) * o.interval), nil
+}
+
+// parsed is the format agnostic result of every importer, it is converted into [data.Data] once the whole
+// input has been read.
+type parsed struct {
+	url         string
+	points      []ping.PingResults
+	annotations []data.Annotation
+}
+
+// setURL records the [url] of the input, every non-empty url must be the same.
+func (p *parsed) setURL(url string) error {
+	switch {
+	case url == "" || url == p.url:
+		return nil
+	case p.url == "":
+		p.url = url
+		return nil
+	default:
+		return errors.Errorf("found the url %q after %q, only one url can be imported at a time", url, p.url)
+	}
+}
+
+func (p *parsed) addPoint(t time.Time, duration time.Duration, reason ping.Dropped, ip net.IP) {
+	p.points = append(p.points, ping.PingResults{
+		Data: ping.PingDataPoint{Timestamp: t, Duration: duration, DropReason: reason},
+		IP:   ip,
+	})
+}
+
+// toData adds the points in timestamp order so the runs and statistics of the result are correct even if the
+// input was not in order.
+func (p *parsed) toData() *data.Data {
+	slices.SortStableFunc(p.points, func(a, b ping.PingResults) int { return a.Data.Timestamp.Compare(b.Data.Timestamp) })
+	ret := data.NewData(p.url)
+	for _, point := range p.points {
+		ret.AddPoint(point)
+	}
+	for _, a := range p.annotations {
+		ret.AddAnnotation(a)
+	}
+	return ret
+}
+
+func parse(r io.Reader, format string, opts textOptions) (*parsed, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if format == autoFormat {
+		format = detectFormat(lines)
+	}
+	switch format {
+	case csvFormat:
+		return parseCSV(lines)
+	case iputilsFormat:
+		return parseIPUtils(lines, opts)
+	case windowsFormat:
+		return parseWindows(lines, opts)
+	case autoFormat:
+		return nil, errors.New("cannot detect the format of the input, use -format to set it")
+	default:
+		return nil, errors.Errorf("unknown -format %q", format)
+	}
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	ret := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		ret = append(ret, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	return ret, scanner.Err()
+}
+
+// detectFormat guesses the format from the first line which isn't empty, returning [autoFormat] if no format
+// matched.
+func detectFormat(lines []string) string {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, csvHeaderPrefix), strings.HasPrefix(line, legacyCSVHeaderPrefix):
+			return csvFormat
+		case iputilsHeader.MatchString(line) || iputilsReply.MatchString(line):
+			return iputilsFormat
+		case windowsHeader.MatchString(line) || strings.Contains(line, "Reply from "):
+			return windowsFormat
+		default:
+			return autoFormat
+		}
+	}
+	return autoFormat
+}
@@ -0,0 +1,199 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package importer_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/importer"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type expectedPoint struct {
+	timestamp time.Time
+	ip        net.IP
+	duration  time.Duration
+	reason    ping.Dropped
+}
+
+func TestImportCSV(t *testing.T) {
+	t.Parallel()
+	input := `timestamp,latency_ns,latency_ms,drop_reason_code,drop_reason,ip,annotation,url
+2024-08-02T21:04:27.5Z,8154265,8.154265,0,,142.250.179.228,,www.google.com
+2024-08-02T21:04:28.5Z,0,0,1,Timeout,142.250.179.228,,www.google.com
+2024-08-02T21:04:29.5Z,7900000,7.9,0,,172.217.16.228,,www.google.com
+2024-08-02T21:04:28Z,,,,,,"router ""rebooted"", again",www.google.com
+`
+	d, err := importer.Parse(strings.NewReader(input), "auto", time.Time{}, "")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "www.google.com")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 4, 27, 500), ip: net.ParseIP("142.250.179.228"), duration: 8154265},
+		{timestamp: utc(21, 4, 28, 500), ip: net.ParseIP("142.250.179.228"), reason: ping.Timeout},
+		{timestamp: utc(21, 4, 29, 500), ip: net.ParseIP("172.217.16.228"), duration: 7900 * time.Microsecond},
+	})
+	assert.Check(t, is.DeepEqual(d.Annotations, []data.Annotation{{Timestamp: utc(21, 4, 28, 0), Text: `router "rebooted", again`}}))
+
+	_, err = importer.Parse(strings.NewReader(input+"2024-08-02T21:04:30Z,1,1\n"), "csv", time.Time{}, "")
+	assert.ErrorContains(t, err, "wrong number of fields")
+
+	_, err = importer.Parse(strings.NewReader(input+"2024-08-02T21:04:30Z,1,0.000001,0,,142.250.179.228,,www.bing.com\n"),
+		"csv", time.Time{}, "")
+	assert.ErrorContains(t, err, `found the url "www.bing.com" after "www.google.com"`)
+}
+
+func TestImportCSVWithoutURL(t *testing.T) {
+	t.Parallel()
+	input := `timestamp,latency_ns,latency_ms,drop_reason_code,drop_reason,ip,annotation
+2024-08-02T21:04:27.5Z,8154265,8.154265,0,,142.250.179.228,
+`
+	d, err := importer.Parse(strings.NewReader(input), "auto", time.Time{}, "")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 4, 27, 500), ip: net.ParseIP("142.250.179.228"), duration: 8154265},
+	})
+}
+
+func TestImportLegacyCSV(t *testing.T) {
+	t.Parallel()
+	input := `timestamp(RFC3339Nano),latency,dropped,ip,header,annotation
+,,,,"www.google.com: PingsMeta#5 [142.250.179.228] | stats | runs",
+"2024-08-02T21:04:27.5Z","8.154265ms","","142.250.179.228",,
+"2024-08-02T21:04:28.5Z","0s","Timeout","142.250.179.228",,
+"2024-08-02T21:04:29.5Z","7.9ms","","172.217.16.228",,
+"2024-08-02T21:04:28Z",,,,,"router \"rebooted\", again"
+`
+	d, err := importer.Parse(strings.NewReader(input), "auto", time.Time{}, "")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "www.google.com")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 4, 27, 500), ip: net.ParseIP("142.250.179.228"), duration: 8154265},
+		{timestamp: utc(21, 4, 28, 500), ip: net.ParseIP("142.250.179.228"), reason: ping.Timeout},
+		{timestamp: utc(21, 4, 29, 500), ip: net.ParseIP("172.217.16.228"), duration: 7900 * time.Microsecond},
+	})
+	assert.Check(t, is.DeepEqual(d.Annotations, []data.Annotation{{Timestamp: utc(21, 4, 28, 0), Text: `router "rebooted", again`}}))
+}
+
+func TestImportIPUtils(t *testing.T) {
+	t.Parallel()
+	input := `PING www.google.com (142.250.179.228) 56(84) bytes of data.
+[1722632667.500000] 64 bytes from lhr25s34-in-f4.1e100.net (142.250.179.228): icmp_seq=1 ttl=117 time=8.15 ms
+[1722632670.500000] 64 bytes from lhr25s34-in-f4.1e100.net (142.250.179.228): icmp_seq=4 ttl=117 time=8.50 ms
+[1722632670.600000] 64 bytes from lhr25s34-in-f4.1e100.net (142.250.179.228): icmp_seq=4 ttl=117 time=108 ms (DUP!)
+[1722632671.500000] no answer yet for icmp_seq=5
+[1722632672.5] From 192.168.1.1 icmp_seq=6 Destination Host Unreachable
+
+--- www.google.com ping statistics ---
+6 packets transmitted, 2 received, +1 duplicates, +1 errors, 66.6667% packet loss, time 5006ms
+rtt min/avg/max/mdev = 8.150/8.325/8.500/0.175 ms
+`
+	d, err := importer.Parse(strings.NewReader(input), "auto", time.Time{}, "")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "www.google.com")
+	google := net.ParseIP("142.250.179.228")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 4, 27, 500), ip: google, duration: 8150 * time.Microsecond},
+		{timestamp: utc(21, 4, 28, 500), ip: google, reason: ping.Timeout},
+		{timestamp: utc(21, 4, 29, 500), ip: google, reason: ping.Timeout},
+		{timestamp: utc(21, 4, 30, 500), ip: google, duration: 8500 * time.Microsecond},
+		{timestamp: utc(21, 4, 31, 500), ip: google, reason: ping.Timeout},
+		{timestamp: utc(21, 4, 32, 500), ip: net.ParseIP("192.168.1.1"), reason: ping.BadResponse},
+	})
+}
+
+func TestImportIPUtilsWithoutTimestamps(t *testing.T) {
+	t.Parallel()
+	input := `PING google.com(lhr25s33-in-x0e.1e100.net (2a00:1450:4009:822::200e)) 56 data bytes
+64 bytes from lhr25s33-in-x0e.1e100.net (2a00:1450:4009:822::200e): icmp_seq=1 ttl=117 time=9.01 ms
+64 bytes from lhr25s33-in-x0e.1e100.net (2a00:1450:4009:822::200e): icmp_seq=3 ttl=117 time=9.02 ms
+`
+	_, err := importer.Parse(strings.NewReader(input), "iputils", time.Time{}, "")
+	assert.ErrorContains(t, err, "use -start")
+
+	d, err := importer.Parse(strings.NewReader(input), "iputils", utc(21, 0, 0, 0), "")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "google.com")
+	google := net.ParseIP("2a00:1450:4009:822::200e")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 0, 0, 0), ip: google, duration: 9010 * time.Microsecond},
+		{timestamp: utc(21, 0, 1, 0), ip: google, reason: ping.Timeout},
+		{timestamp: utc(21, 0, 2, 0), ip: google, duration: 9020 * time.Microsecond},
+	})
+}
+
+func TestImportWindows(t *testing.T) {
+	t.Parallel()
+	input := "\r\nPinging www.google.com [142.250.179.228] with 32 bytes of data:\r\n" +
+		"02/08/2024 21:04:27 - Reply from 142.250.179.228: bytes=32 time=8ms TTL=117\r\n" +
+		"02/08/2024 21:04:32 - Request timed out.\r\n" +
+		"02/08/2024 21:04:33 - Reply from 192.168.1.1: Destination host unreachable.\r\n" +
+		"02/08/2024 21:04:34 - Reply from 142.250.179.228: bytes=32 time<1ms TTL=117\r\n" +
+		"02/08/2024 21:04:35 - General failure.\r\n" +
+		"\r\nPing statistics for 142.250.179.228:\r\n" +
+		"    Packets: Sent = 5, Received = 3, Lost = 2 (40% loss),\r\n"
+	d, err := importer.Parse(strings.NewReader(input), "auto", time.Time{}, "02/01/2006 15:04:05")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "www.google.com")
+	google := net.ParseIP("142.250.179.228")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 4, 27, 0), ip: google, duration: 8 * time.Millisecond},
+		{timestamp: utc(21, 4, 32, 0), ip: google, reason: ping.Timeout},
+		{timestamp: utc(21, 4, 33, 0), ip: net.ParseIP("192.168.1.1"), reason: ping.BadResponse},
+		{timestamp: utc(21, 4, 34, 0), ip: google, duration: time.Millisecond},
+		{timestamp: utc(21, 4, 35, 0), ip: google, reason: ping.BadResponse},
+	})
+}
+
+func TestImportWindowsWithoutTimestamps(t *testing.T) {
+	t.Parallel()
+	input := "Pinging 8.8.8.8 with 32 bytes of data:\n" +
+		"Reply from 8.8.8.8: bytes=32 time=12ms TTL=117\n" +
+		"Reply from 8.8.8.8: bytes=32 time=13ms TTL=117\n"
+	d, err := importer.Parse(strings.NewReader(input), "windows", utc(21, 0, 0, 0), "")
+	assert.NilError(t, err)
+	assert.Equal(t, d.URL, "8.8.8.8")
+	checkPoints(t, d, []expectedPoint{
+		{timestamp: utc(21, 0, 0, 0), ip: net.ParseIP("8.8.8.8"), duration: 12 * time.Millisecond},
+		{timestamp: utc(21, 0, 1, 0), ip: net.ParseIP("8.8.8.8"), duration: 13 * time.Millisecond},
+	})
+}
+
+func TestImportUnknownFormat(t *testing.T) {
+	t.Parallel()
+	_, err := importer.Parse(strings.NewReader("hello world\n"), "auto", time.Time{}, "")
+	assert.ErrorContains(t, err, "cannot detect the format")
+}
+
+func TestFlagsCompletion(t *testing.T) {
+	t.Parallel()
+	c := importer.GetFlags()
+	assert.Check(t, is.DeepEqual(c.GetNames(false, nil),
+		[]string{"-format", "-interval", "-out", "-start", "-time-layout", "-url"}))
+}
+
+func checkPoints(t *testing.T, d *data.Data, expected []expectedPoint) {
+	t.Helper()
+	assert.Equal(t, d.TotalCount, int64(len(expected)))
+	for i, e := range expected {
+		actual := d.GetFull(int64(i))
+		assert.Check(t, actual.Data.Timestamp.Equal(e.timestamp), "%d: %s != %s", i, actual.Data.Timestamp, e.timestamp)
+		assert.Check(t, actual.IP.Equal(e.ip), "%d: %s != %s", i, actual.IP, e.ip)
+		assert.Check(t, is.Equal(actual.Data.Duration, e.duration), i)
+		assert.Check(t, is.Equal(actual.Data.DropReason, e.reason), i)
+	}
+}
+
+func utc(hour, minute, second, milli int) time.Time {
+	return time.Date(2024, time.August, 2, hour, minute, second, milli*int(time.Millisecond), time.UTC)
+}
@@ -0,0 +1,132 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package importer
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+var (
+	// e.g. "PING www.google.com (142.250.179.228) 56(84) bytes of data." or for IPv6
+	// "PING google.com(lhr25s33-in-x0e.1e100.net (2a00:1450:4009:822::200e)) 56 data bytes"
+	iputilsHeader = regexp.MustCompile(`^PING ([^\s(]+) ?\((?:[^\s(]+ \()?([0-9a-fA-F.:%]+)\)`)
+	// e.g. "[1722635000.123456] " which is printed by "ping -D"
+	iputilsTimestamp = regexp.MustCompile(`^\[(\d+)(?:\.(\d+))?\]\s*`)
+	// e.g. "64 bytes from lhr25s34-in-f4.1e100.net (142.250.179.228): icmp_seq=1 ttl=117 time=8.15 ms"
+	iputilsReply = regexp.MustCompile(`bytes from (?:[^\s(]+ \()?([0-9a-fA-F.:%]+)\)?: icmp_seq=(\d+) .*time=([\d.]+) ms`)
+	// e.g. "no answer yet for icmp_seq=3" which is printed by "ping -O"
+	iputilsNoAnswer = regexp.MustCompile(`no answer yet for icmp_seq=(\d+)`)
+	// e.g. "From 192.168.1.1 (192.168.1.1) icmp_seq=4 Destination Host Unreachable"
+	iputilsError = regexp.MustCompile(`^From ([^\s(]+)(?: \(([^)]+)\))? icmp_seq=(\d+) `)
+)
+
+// parseIPUtils reads the output of the linux (iputils) ping. Timestamps are read from the "ping -D" prefix, if
+// a line has no timestamp then it's derived from the sequence number and [textOptions.timestampOf].
+//
+// iputils doesn't print anything for lost packets (unless run with -O) so any gap in the sequence numbers is
+// imported as a timed out packet, the timestamp of which is interpolated between its neighbours. Sequence
+// numbers wrapping around is not handled.
+func parseIPUtils(lines []string, opts textOptions) (*parsed, error) {
+	ret := &parsed{}
+	var headerIP net.IP
+	seen := map[int64]bool{}
+	var lastSeq int64
+	var lastTime time.Time
+	for i, line := range lines {
+		lineNumber := i + 1
+		if m := iputilsHeader.FindStringSubmatch(line); m != nil {
+			ret.url = m[1]
+			headerIP = net.ParseIP(m[2])
+			continue
+		}
+		timestamp, hasTimestamp, err := parseIPUtilsTimestamp(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		line = iputilsTimestamp.ReplaceAllString(line, "")
+		if strings.Contains(line, "(DUP!)") {
+			continue
+		}
+
+		var seq string
+		var duration time.Duration
+		reason := ping.NotDropped
+		ip := headerIP
+		if m := iputilsReply.FindStringSubmatch(line); m != nil {
+			ms, err := strconv.ParseFloat(m[3], 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d: invalid time", lineNumber)
+			}
+			ip, seq, duration = net.ParseIP(m[1]), m[2], time.Duration(ms*float64(time.Millisecond))
+		} else if m := iputilsNoAnswer.FindStringSubmatch(line); m != nil {
+			seq, reason = m[1], ping.Timeout
+		} else if m := iputilsError.FindStringSubmatch(line); m != nil {
+			from := m[1]
+			if m[2] != "" {
+				from = m[2]
+			}
+			ip, seq, reason = net.ParseIP(from), m[3], ping.BadResponse
+		} else {
+			continue
+		}
+
+		sequence, err := strconv.ParseInt(seq, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid icmp_seq", lineNumber)
+		}
+		if seen[sequence] {
+			continue
+		}
+		seen[sequence] = true
+		if !hasTimestamp {
+			timestamp, err = opts.timestampOf(sequence - 1)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNumber)
+			}
+		}
+		if !lastTime.IsZero() && sequence > lastSeq+1 {
+			step := timestamp.Sub(lastTime) / time.Duration(sequence-lastSeq)
+			for missing := lastSeq + 1; missing < sequence; missing++ {
+				seen[missing] = true
+				ret.addPoint(lastTime.Add(time.Duration(missing-lastSeq)*step), 0, ping.Timeout, headerIP)
+			}
+		}
+		if sequence > lastSeq {
+			lastSeq, lastTime = sequence, timestamp
+		}
+		ret.addPoint(timestamp, duration, reason, ip)
+	}
+	return ret, nil
+}
+
+func parseIPUtilsTimestamp(line string) (time.Time, bool, error) {
+	m := iputilsTimestamp.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, false, nil
+	}
+	seconds, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, "invalid timestamp")
+	}
+	var nanos int64
+	if m[2] != "" {
+		// The fraction is padded or truncated to 9 digits to become nanoseconds
+		fraction := (m[2] + "000000000")[:9]
+		nanos, err = strconv.ParseInt(fraction, 10, 64)
+		if err != nil {
+			return time.Time{}, false, errors.Wrap(err, "invalid timestamp")
+		}
+	}
+	return time.Unix(seconds, nanos), true, nil
+}
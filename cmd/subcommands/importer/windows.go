@@ -0,0 +1,102 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package importer
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+var (
+	// e.g. "Pinging www.google.com [142.250.179.228] with 32 bytes of data:" or
+	// "Pinging 8.8.8.8 with 32 bytes of data:"
+	windowsHeader = regexp.MustCompile(`^Pinging (\S+) (?:\[([0-9a-fA-F.:%]+)\] )?with`)
+	// windowsResult finds the start of every line which is the result of a single ping, anything before
+	// the match is a timestamp added by whatever was logging the output.
+	windowsResult = regexp.MustCompile(`Reply from |Request timed out|General failure|PING: transmit failed|Destination host unreachable`)
+	// e.g. "Reply from 142.250.179.228: bytes=32 time=8ms TTL=117" or "Reply from ::1: time<1ms"
+	windowsReply = regexp.MustCompile(`^Reply from ([0-9a-fA-F.:%]+): (?:bytes=\d+ )?time[=<](\d+)ms`)
+	// e.g. "Reply from 192.168.1.1: Destination host unreachable."
+	windowsBadReply = regexp.MustCompile(`^Reply from ([0-9a-fA-F.:%]+): `)
+)
+
+// parseWindows reads the output of the windows ping (usually "ping -t"). The windows ping doesn't print
+// timestamps so logs are typically piped through something which prefixes each line with the time, e.g.
+// "2024-08-02 21:04:27 - Reply from ...", this prefix is parsed with [textOptions.layout]. Otherwise the
+// timestamp is derived from [textOptions.timestampOf].
+//
+// Latencies under a millisecond are printed as "time<1ms" and are imported as 1ms.
+func parseWindows(lines []string, opts textOptions) (*parsed, error) {
+	ret := &parsed{}
+	var headerIP net.IP
+	var count int64
+	for i, line := range lines {
+		lineNumber := i + 1
+		if m := windowsHeader.FindStringSubmatch(line); m != nil {
+			ret.url = m[1]
+			headerIP = net.ParseIP(m[2])
+			if headerIP == nil {
+				headerIP = net.ParseIP(m[1])
+			}
+			continue
+		}
+		loc := windowsResult.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		result := line[loc[0]:]
+
+		var duration time.Duration
+		reason := ping.NotDropped
+		ip := headerIP
+		if m := windowsReply.FindStringSubmatch(result); m != nil {
+			ms, err := strconv.ParseInt(m[2], 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d: invalid time", lineNumber)
+			}
+			ip, duration = net.ParseIP(m[1]), time.Duration(ms)*time.Millisecond
+			if ms == 0 {
+				duration = time.Millisecond
+			}
+		} else if m := windowsBadReply.FindStringSubmatch(result); m != nil {
+			ip, reason = net.ParseIP(m[1]), ping.BadResponse
+		} else if strings.HasPrefix(result, "Request timed out") {
+			reason = ping.Timeout
+		} else {
+			reason = ping.BadResponse
+		}
+
+		timestamp, err := opts.parsePrefix(line[:loc[0]], count)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		count++
+		ret.addPoint(timestamp, duration, reason, ip)
+	}
+	return ret, nil
+}
+
+// parsePrefix parses the timestamp a logger has added before the ping output, if there is no prefix then the
+// timestamp is derived from the count of pings so far.
+func (o textOptions) parsePrefix(prefix string, count int64) (time.Time, error) {
+	prefix = strings.Trim(prefix, " \t-:|[]")
+	if prefix == "" {
+		return o.timestampOf(count)
+	}
+	if o.layout != "" {
+		t, err := time.ParseInLocation(o.layout, prefix, o.loc)
+		return t, errors.Wrapf(err, "invalid timestamp for -time-layout %q", o.layout)
+	}
+	return timeutils.ParseTimeOrOffset(prefix, time.Time{}, time.Time{}, o.loc)
+}
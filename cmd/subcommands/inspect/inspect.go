@@ -0,0 +1,168 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package inspect
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/exit"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	json *bool
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		json:    tf.Bool("json", false, "writes the layout as a json document per file instead of plain text"),
+	}
+
+	f.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: prints the byte layout of '.pings' files and any inconsistencies found\n"+
+			"\t inspect [-json] FILES\n\n"+
+			"e.g. %s inspect my_ping_capture.ping\n\n"+
+			"Exits with a non-zero status if any file has problems.\n", os.Args[0], os.Args[0])
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunInspect(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	toInspect := c.Args()
+	if len(toInspect) == 0 {
+		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
+		exit.Success()
+	}
+	w := bufio.NewWriter(os.Stdout)
+	failed := false
+	for _, file := range toInspect {
+		f, err := files.OpenReadOnly(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open %q, %s\n", file, err.Error())
+			failed = true
+			continue
+		}
+		problems, err := handle(w, *c.json, file, f)
+		f.Close()
+		if err != nil {
+			_ = w.Flush()
+			fmt.Fprintf(os.Stderr, "Failed to inspect %q, %s\n", file, err.Error())
+		}
+		failed = failed || problems || err != nil
+	}
+	_ = w.Flush()
+	if failed {
+		exit.Silent()
+	}
+}
+
+// handle writes the layout of the '.pings' file [r], returning true if there were any problems.
+func handle(w io.Writer, asJSON bool, name string, r io.Reader) (bool, error) {
+	layout, err := data.Inspect(r)
+	if err != nil {
+		return false, err
+	}
+	if asJSON {
+		return len(layout.Problems) > 0, json.NewEncoder(w).Encode(toJSON(name, layout))
+	}
+	fmt.Fprintf(w, "%s: %d bytes\n", name, layout.Size)
+	fmt.Fprintf(w, "%-10s %-10s %s\n", "OFFSET", "LENGTH", "SECTION")
+	printSections(w, layout.Sections, 0)
+	if len(layout.Problems) == 0 {
+		fmt.Fprintln(w, "No problems found")
+	} else {
+		fmt.Fprintf(w, "%d problems found:\n", len(layout.Problems))
+		for _, p := range layout.Problems {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+	}
+	fmt.Fprintln(w)
+	return len(layout.Problems) > 0, nil
+}
+
+func printSections(w io.Writer, sections []*data.Section, depth int) {
+	for _, s := range sections {
+		name := strings.Repeat("  ", depth) + s.Name
+		if s.Identifier != 0 {
+			name += " <" + s.Identifier.String() + ">"
+		}
+		if s.Detail != "" {
+			name += ": " + s.Detail
+		}
+		fmt.Fprintf(w, "0x%08x %-10d %s [% x]\n", s.Offset, s.Length, name, s.Preview)
+		printSections(w, s.Children, depth+1)
+	}
+}
+
+// jsonLayout is the schema of the -json output.
+type jsonLayout struct {
+	File     string         `json:"file"`
+	Sections []*jsonSection `json:"sections"`
+	Problems []string       `json:"problems"`
+	Size     int64          `json:"size"`
+}
+
+type jsonSection struct {
+	Name       string         `json:"name"`
+	Detail     string         `json:"detail,omitempty"`
+	Identifier string         `json:"identifier,omitempty"`
+	Preview    string         `json:"preview"`
+	Children   []*jsonSection `json:"children,omitempty"`
+	Offset     int64          `json:"offset"`
+	Length     int64          `json:"length"`
+	Version    int            `json:"version,omitempty"`
+}
+
+func toJSON(name string, layout *data.Layout) jsonLayout {
+	problems := layout.Problems
+	if problems == nil {
+		problems = []string{}
+	}
+	return jsonLayout{
+		File:     name,
+		Sections: toJSONSections(layout.Sections),
+		Problems: problems,
+		Size:     layout.Size,
+	}
+}
+
+func toJSONSections(sections []*data.Section) []*jsonSection {
+	ret := make([]*jsonSection, len(sections))
+	for i, s := range sections {
+		ret[i] = &jsonSection{
+			Name:     s.Name,
+			Detail:   s.Detail,
+			Preview:  hex.EncodeToString(s.Preview),
+			Children: toJSONSections(s.Children),
+			Offset:   s.Offset,
+			Length:   s.Length,
+			Version:  int(s.Version),
+		}
+		if s.Identifier != 0 {
+			ret[i].Identifier = s.Identifier.String()
+		}
+	}
+	return ret
+}
@@ -0,0 +1,62 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package inspect_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/inspect"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestText(t *testing.T) {
+	t.Parallel()
+	b := makeTestFile(t)
+	var out bytes.Buffer
+	problems, err := inspect.Handle(&out, false, "test.pings", bytes.NewReader(b))
+	assert.NilError(t, err)
+	assert.Check(t, !problems)
+	assert.Check(t, is.Contains(out.String(), "test.pings: "))
+	assert.Check(t, is.Contains(out.String(), "<Data>: version 9"))
+	assert.Check(t, is.Contains(out.String(), "No problems found"))
+
+	out.Reset()
+	problems, err = inspect.Handle(&out, false, "test.pings", bytes.NewReader(b[:len(b)-1]))
+	assert.NilError(t, err)
+	assert.Check(t, problems)
+	assert.Check(t, is.Contains(out.String(), "1 problems found:"))
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	problems, err := inspect.Handle(&out, true, "test.pings", bytes.NewReader(makeTestFile(t)))
+	assert.NilError(t, err)
+	assert.Check(t, !problems)
+
+	var doc map[string]any
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Check(t, is.Equal(doc["file"], "test.pings"))
+	assert.Check(t, is.DeepEqual(doc["problems"], []any{}))
+	sections := doc["sections"].([]any)
+	assert.Assert(t, is.Len(sections, 1))
+	root := sections[0].(map[string]any)
+	assert.Check(t, is.Equal(root["identifier"], "Data"))
+	assert.Check(t, is.Equal(root["version"], 9.0))
+	assert.Check(t, is.Equal(root["offset"], 0.0))
+	assert.Check(t, is.Equal(root["length"], doc["size"]))
+}
+
+func makeTestFile(t *testing.T) []byte {
+	t.Helper()
+	return th.WritePingsFile(t, th.Pings(th.PingsStart, 10, time.Second, nil))
+}
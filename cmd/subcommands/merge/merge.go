@@ -0,0 +1,96 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package merge
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"github.com/Lexer747/acci-ping/utils/exit"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	output *string
+	url    *string
+	force  *bool
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		output: tf.String("out", "", "the '.pings' file to write the merged result into, must not already exist.",
+			tabflags.AutoComplete{WantsFile: true, FileExt: ".pings"}),
+		url:   tf.String("url", "", "the url to read from files which hold the pings of many urls (see -multi-target).", tabflags.AutoComplete{}),
+		force: tf.Bool("force", false, "merge the files even if they target different URLs, the URL of the first file is kept."),
+	}
+
+	f.Usage = func() {
+		var programName = "acci-ping " + ansi.Green("merge")
+
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: merges many '.pings' files of the same URL into a single '.pings' file\n"+
+			"\t merge [-force][-url URL] -out FILE FILES\n\n"+
+			"e.g. '%s -out week.pings monday.pings tuesday.pings'\n", programName, programName)
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunMerge(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	toMerge := c.Args()
+	if len(toMerge) == 0 {
+		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	if *c.output == "" {
+		fmt.Fprintf(os.Stderr, "No output file given, use -out FILE. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	inputs := make([]*data.Data, 0, len(toMerge))
+	for _, file := range toMerge {
+		d, err := files.LoadTarget(file, *c.url)
+		exit.OnErrorMsgf(err, "Failed to read %q", file)
+		inputs = append(inputs, d)
+	}
+	exit.OnError(checkURLs(toMerge, inputs, *c.force))
+
+	merged := data.Merge(inputs...)
+	f, err := os.OpenFile(*c.output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o777)
+	exit.OnErrorMsgf(err, "Failed to create %q", *c.output)
+	defer f.Close()
+	exit.OnErrorMsgf(merged.AsCompact(f), "Failed to write %q", *c.output)
+	fmt.Fprintf(os.Stdout, "Merged %d files into %q\n\t%s\n", len(inputs), *c.output, merged.String())
+}
+
+// checkURLs ensures all the inputs target the same URL, unless forced.
+func checkURLs(files []string, inputs []*data.Data, force bool) error {
+	if force {
+		return nil
+	}
+	expected := inputs[0].URL
+	for i, d := range inputs {
+		if d.URL != expected {
+			return errors.Errorf(
+				"Cannot merge %q (URL %q) with %q (URL %q), the URLs differ. Use -force to merge anyway.",
+				files[i], d.URL, files[0], expected,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package merge_test
+
+import (
+	"testing"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/merge"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+	c := merge.GetFlags()
+	assert.NilError(t, c.Parse([]string{"-force", "-out", "week.pings", "monday.pings", "tuesday.pings"}))
+	assert.Check(t, is.Equal(c.Output(), "week.pings"))
+	assert.Check(t, c.Force())
+	assert.Check(t, is.DeepEqual(c.Args(), []string{"monday.pings", "tuesday.pings"}))
+}
+
+func TestCheckURLs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		urls          []string
+		force         bool
+		expectedError string
+	}{
+		{name: "one file", urls: []string{"www.google.com"}},
+		{name: "same URL", urls: []string{"www.google.com", "www.google.com", "www.google.com"}},
+		{
+			name:          "different URL",
+			urls:          []string{"www.google.com", "www.google.com", "1.1.1.1"},
+			expectedError: `Cannot merge "2.pings" (URL "1.1.1.1") with "0.pings" (URL "www.google.com"), the URLs differ. Use -force to merge anyway.`,
+		},
+		{name: "different URL forced", urls: []string{"www.google.com", "1.1.1.1"}, force: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			files := []string{}
+			inputs := []*data.Data{}
+			for i, url := range test.urls {
+				files = append(files, string(rune('0'+i))+".pings")
+				inputs = append(inputs, data.NewData(url))
+			}
+			err := merge.CheckURLs(files, inputs, test.force)
+			if test.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, test.expectedError)
+			}
+		})
+	}
+}
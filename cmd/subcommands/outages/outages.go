@@ -0,0 +1,168 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package outages
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/exit"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	json       *bool
+	minDropped *int
+	maxGap     *time.Duration
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		json:    tf.Bool("json", false, "writes the outages as a json document per target instead of a table"),
+		minDropped: tf.Int("min-dropped", data.DefaultOutageOptions.MinDropped,
+			"the fewest consecutive dropped packets which are counted as an outage"),
+		maxGap: tf.Duration("max-gap", data.DefaultOutageOptions.MaxGap,
+			"the longest time between two packets which still counts towards the uptime, longer gaps are when\n"+
+				"nothing was recording"),
+	}
+
+	f.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: lists every outage (consecutive dropped packets) found in '.pings' files\n"+
+			"\t outages [-json][-min-dropped N][-max-gap DURATION] FILES\n\n"+
+			"e.g. %s outages my_ping_capture.ping\n", os.Args[0], os.Args[0])
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunOutages(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	toRead := c.Args()
+	if len(toRead) == 0 {
+		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
+		exit.Success()
+	}
+	if *c.minDropped < 1 {
+		fmt.Fprintf(os.Stderr, "-min-dropped must be at least 1, got %d.\n", *c.minDropped)
+		exit.Silent()
+	}
+	options := data.OutageOptions{MinDropped: *c.minDropped, MaxGap: *c.maxGap}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for _, file := range toRead {
+		f, err := files.OpenReadOnly(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open %q, %s\n", file, err.Error())
+			continue
+		}
+		err = handle(w, *c.json, options, file, f)
+		f.Close()
+		if err != nil {
+			_ = w.Flush()
+			fmt.Fprintf(os.Stderr, "Failed to read %q, %s\n", file, err.Error())
+		}
+	}
+}
+
+// handle writes the outages of each target of the '.pings' file [r].
+func handle(w io.Writer, asJSON bool, options data.OutageOptions, name string, r io.Reader) error {
+	streams, err := data.NewTargetStreams(r)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		report, err := s.Outages(options)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			if err := json.NewEncoder(w).Encode(toJSON(name, s.URL, report)); err != nil {
+				return err
+			}
+			continue
+		}
+		printTable(w, name, s.URL, report)
+	}
+	return nil
+}
+
+func printTable(w io.Writer, name, url string, report *data.OutageReport) {
+	fmt.Fprintf(w, "%s %s: Observed %s | %s\n", name, url, report.Observed.Round(time.Second), report.Summary())
+	if len(report.Outages) == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BEGIN\tEND\tDURATION\tDROPPED\tREASON")
+	for _, o := range report.Outages {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n",
+			o.Begin.Format(time.RFC3339Nano), o.End.Format(time.RFC3339Nano),
+			o.Duration().Round(time.Millisecond), o.Count, o.Reason.String())
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+}
+
+// jsonReport is the schema of the -json output, durations are in nanoseconds.
+type jsonReport struct {
+	File       string       `json:"file"`
+	URL        string       `json:"url"`
+	Outages    []jsonOutage `json:"outages"`
+	ObservedNS int64        `json:"observed_ns"`
+	DowntimeNS int64        `json:"downtime_ns"`
+	Uptime     float64      `json:"uptime"`
+	MTBFNS     int64        `json:"mtbf_ns"`
+	MTTRNS     int64        `json:"mttr_ns"`
+}
+
+type jsonOutage struct {
+	Begin      string `json:"begin"`
+	End        string `json:"end"`
+	Reason     string `json:"reason"`
+	DurationNS int64  `json:"duration_ns"`
+	Dropped    int64  `json:"dropped"`
+	FirstIndex int64  `json:"first_index"`
+}
+
+func toJSON(name, url string, report *data.OutageReport) jsonReport {
+	outages := make([]jsonOutage, len(report.Outages))
+	for i, o := range report.Outages {
+		outages[i] = jsonOutage{
+			Begin:      o.Begin.Format(time.RFC3339Nano),
+			End:        o.End.Format(time.RFC3339Nano),
+			Reason:     o.Reason.String(),
+			DurationNS: o.Duration().Nanoseconds(),
+			Dropped:    o.Count,
+			FirstIndex: o.FirstIndex,
+		}
+	}
+	return jsonReport{
+		File:       name,
+		URL:        url,
+		Outages:    outages,
+		ObservedNS: report.Observed.Nanoseconds(),
+		DowntimeNS: report.Downtime.Nanoseconds(),
+		Uptime:     report.Uptime(),
+		MTBFNS:     report.MTBF().Nanoseconds(),
+		MTTRNS:     report.MTTR().Nanoseconds(),
+	}
+}
@@ -0,0 +1,62 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package outages_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/outages"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestText(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := outages.Handle(&out, false, data.DefaultOutageOptions, "test.pings", bytes.NewReader(makeTestFile(t)))
+	assert.NilError(t, err)
+	expected := "test.pings www.google.com: Observed 9s | Uptime 55.556% | Outages 1 | Downtime 4s | MTBF 5s | MTTR 4s\n" +
+		"BEGIN                 END                   DURATION  DROPPED  REASON\n" +
+		"2024-08-02T21:04:29Z  2024-08-02T21:04:33Z  4s        4        DNS Query Failed\n\n"
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	err := outages.Handle(&out, true, data.DefaultOutageOptions, "test.pings", bytes.NewReader(makeTestFile(t)))
+	assert.NilError(t, err)
+
+	var doc map[string]any
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Check(t, is.Equal(doc["url"], "www.google.com"))
+	assert.Check(t, is.Equal(doc["downtime_ns"], float64(4*time.Second)))
+	assert.Check(t, is.Equal(doc["mttr_ns"], float64(4*time.Second)))
+	list := doc["outages"].([]any)
+	assert.Assert(t, is.Len(list, 1))
+	outage := list[0].(map[string]any)
+	assert.Check(t, is.Equal(outage["begin"], "2024-08-02T21:04:29Z"))
+	assert.Check(t, is.Equal(outage["reason"], "DNS Query Failed"))
+	assert.Check(t, is.Equal(outage["dropped"], 4.0))
+	assert.Check(t, is.Equal(outage["first_index"], 2.0))
+}
+
+// makeTestFile is a point a second where the 3rd to 6th points failed DNS.
+func makeTestFile(t *testing.T) []byte {
+	t.Helper()
+	return th.WritePingsFile(t, th.Pings(th.PingsStart, 10, time.Second, func(i int, p *ping.PingResults) {
+		if i >= 2 && i < 6 {
+			p.Data = ping.PingDataPoint{Timestamp: p.Data.Timestamp, DropReason: ping.DNSFailure}
+		}
+	}))
+}
@@ -0,0 +1,212 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package rawdata
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+// The types in this file are the schema of the json and ndjson formats, they are documented in
+// docs/rawdata.md which must be kept in sync. All durations are integer nanoseconds (or floating point
+// nanoseconds for statistics) and all timestamps are RFC 3339 strings with nanosecond precision.
+
+type jsonDocument struct {
+	*jsonMeta
+	Points []jsonPoint `json:"points"`
+}
+
+// jsonMeta is everything about a file except the points, it is the first line of the ndjson format.
+type jsonMeta struct {
+	Type        string           `json:"type,omitempty"`
+	URL         string           `json:"url"`
+	Network     []jsonNetwork    `json:"network"`
+	Annotations []jsonAnnotation `json:"annotations"`
+	Header      jsonHeader       `json:"header"`
+	Runs        jsonRuns         `json:"runs"`
+	Version     int              `json:"version"`
+	TotalCount  int64            `json:"total_count"`
+}
+
+type jsonHeader struct {
+	Span  jsonSpan  `json:"span"`
+	Stats jsonStats `json:"stats"`
+}
+
+type jsonSpan struct {
+	Begin      string `json:"begin"`
+	End        string `json:"end"`
+	DurationNS int64  `json:"duration_ns"`
+}
+
+type jsonStats struct {
+	GoodCount           uint64  `json:"good_count"`
+	DroppedCount        uint64  `json:"dropped_count"`
+	PacketLoss          float64 `json:"packet_loss"`
+	MinNS               int64   `json:"min_ns"`
+	MaxNS               int64   `json:"max_ns"`
+	MeanNS              float64 `json:"mean_ns"`
+	VarianceNS2         float64 `json:"variance_ns2"`
+	StandardDeviationNS float64 `json:"standard_deviation_ns"`
+	P50NS               int64   `json:"p50_ns"`
+	P95NS               int64   `json:"p95_ns"`
+	P99NS               int64   `json:"p99_ns"`
+	JitterNS            int64   `json:"jitter_ns"`
+	MeanJitterNS        int64   `json:"mean_jitter_ns"`
+}
+
+type jsonRuns struct {
+	Good    jsonRun `json:"good"`
+	Dropped jsonRun `json:"dropped"`
+}
+
+type jsonRun struct {
+	Longest         uint64 `json:"longest"`
+	LongestEndIndex int64  `json:"longest_end_index"`
+}
+
+type jsonNetwork struct {
+	IP     string     `json:"ip"`
+	Header jsonHeader `json:"header"`
+}
+
+type jsonAnnotation struct {
+	Type      string `json:"type,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Text      string `json:"text"`
+}
+
+type jsonPoint struct {
+	Type           string `json:"type,omitempty"`
+	Timestamp      string `json:"timestamp"`
+	DropReason     string `json:"drop_reason"`
+	IP             string `json:"ip"`
+	Index          int64  `json:"index"`
+	LatencyNS      int64  `json:"latency_ns"`
+	DropReasonCode int    `json:"drop_reason_code"`
+	Dropped        bool   `json:"dropped"`
+}
+
+// handleJSON writes the whole file as a single json document.
+func handleJSON(w io.Writer, s *data.Stream) error {
+	meta := toJSONMeta(s)
+	doc := jsonDocument{jsonMeta: &meta, Points: make([]jsonPoint, 0, s.TotalCount)}
+	err := forEach(s, func(i int64, p ping.PingResults) error {
+		doc.Points = append(doc.Points, toJSONPoint(i, p))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// handleNDJSON writes a line for the meta data, then a line per point and finally a line per annotation. Each
+// line has a "type" field to tell them apart.
+func handleNDJSON(w io.Writer, s *data.Stream) error {
+	enc := json.NewEncoder(w)
+	meta := toJSONMeta(s)
+	meta.Type = "meta"
+	if err := enc.Encode(meta); err != nil {
+		return err
+	}
+	err := forEach(s, func(i int64, p ping.PingResults) error {
+		point := toJSONPoint(i, p)
+		point.Type = "point"
+		return enc.Encode(point)
+	})
+	if err != nil {
+		return err
+	}
+	for _, a := range s.Annotations {
+		annotation := toJSONAnnotation(a)
+		annotation.Type = "annotation"
+		if err := enc.Encode(annotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toJSONMeta(s *data.Stream) jsonMeta {
+	ret := jsonMeta{
+		URL:        s.URL,
+		Version:    int(s.PingsMeta),
+		TotalCount: s.TotalCount,
+		Header:     toJSONHeader(s.Header),
+		Runs: jsonRuns{
+			Good:    jsonRun{Longest: s.Runs.GoodPackets.Longest, LongestEndIndex: s.Runs.GoodPackets.LongestIndexEnd},
+			Dropped: jsonRun{Longest: s.Runs.DroppedPackets.Longest, LongestEndIndex: s.Runs.DroppedPackets.LongestIndexEnd},
+		},
+		Network:     make([]jsonNetwork, len(s.Network.IPs)),
+		Annotations: make([]jsonAnnotation, len(s.Annotations)),
+	}
+	for i, ip := range s.Network.IPs {
+		ret.Network[i] = jsonNetwork{IP: ip.String(), Header: toJSONHeader(s.BlockHeaders[s.Network.BlockIndexes[i]])}
+	}
+	for i, a := range s.Annotations {
+		ret.Annotations[i] = toJSONAnnotation(a)
+	}
+	return ret
+}
+
+func toJSONHeader(h *data.Header) jsonHeader {
+	return jsonHeader{
+		Span: jsonSpan{
+			Begin:      h.TimeSpan.Begin.Format(time.RFC3339Nano),
+			End:        h.TimeSpan.End.Format(time.RFC3339Nano),
+			DurationNS: int64(h.TimeSpan.Duration),
+		},
+		Stats: jsonStats{
+			GoodCount:           h.Stats.GoodCount,
+			DroppedCount:        h.Stats.PacketsDropped,
+			PacketLoss:          finite(h.Stats.PacketLoss()),
+			MinNS:               int64(h.Stats.Min),
+			MaxNS:               int64(h.Stats.Max),
+			MeanNS:              finite(h.Stats.Mean),
+			VarianceNS2:         finite(h.Stats.Variance),
+			StandardDeviationNS: finite(h.Stats.StandardDeviation),
+			P50NS:               int64(h.Stats.Quantile(0.5)),
+			P95NS:               int64(h.Stats.Quantile(0.95)),
+			P99NS:               int64(h.Stats.Quantile(0.99)),
+			JitterNS:            int64(h.Stats.Jitter()),
+			MeanJitterNS:        int64(h.Stats.MeanJitter()),
+		},
+	}
+}
+
+func toJSONPoint(index int64, p ping.PingResults) jsonPoint {
+	return jsonPoint{
+		Index:          index,
+		Timestamp:      p.Data.Timestamp.Format(time.RFC3339Nano),
+		LatencyNS:      int64(p.Data.Duration),
+		Dropped:        p.Data.Dropped(),
+		DropReasonCode: int(p.Data.DropReason),
+		DropReason:     p.Data.DropReason.String(),
+		IP:             p.IP.String(),
+	}
+}
+
+func toJSONAnnotation(a data.Annotation) jsonAnnotation {
+	return jsonAnnotation{Timestamp: a.Timestamp.Format(time.RFC3339Nano), Text: a.Text}
+}
+
+// finite replaces NaN and infinities (e.g. the packet loss of an empty file) with zero as they can't be
+// represented in json.
+func finite(f float64) float64 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return f
+}
@@ -1,28 +1,46 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
 package rawdata
 
 import (
+	"bufio"
+	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
 	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
 	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/errors"
 	"github.com/Lexer747/acci-ping/utils/exit"
 )
 
+const (
+	summaryFormat   = "summary"
+	allFormat       = "all"
+	csvFormat       = "csv"
+	jsonFormat      = "json"
+	ndjsonFormat    = "ndjson"
+	anomaliesFormat = "anomalies"
+)
+
 type Config struct {
 	*tabflags.FlagSet
 
 	printAll *bool
 	toCSV    *bool
+	format   *string
 }
 
 func GetFlags() *Config {
@@ -30,14 +48,17 @@ func GetFlags() *Config {
 	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
 	ret := &Config{
 		FlagSet:  tf,
-		printAll: tf.Bool("all", false, "prints all raw values otherwise only summarises '.pings' files"),
-		toCSV:    tf.Bool("csv", false, "writes '.pings' files as '.csv'"),
+		printAll: tf.Bool("all", false, "prints all raw values otherwise only summarises '.pings' files, the same as -format all"),
+		toCSV:    tf.Bool("csv", false, "writes '.pings' files as '.csv', the same as -format csv"),
+		format: tf.String("format", summaryFormat, "the output format, one of 'summary', 'all', 'csv', 'json', 'ndjson' or 'anomalies'.\n"+
+			"See docs/rawdata.md for the schema of the csv, json, ndjson and anomalies formats.",
+			tabflags.AutoComplete{Choices: []string{summaryFormat, allFormat, csvFormat, jsonFormat, ndjsonFormat, anomaliesFormat}}),
 	}
 
 	f.Usage = func() {
 		w := flag.CommandLine.Output()
 		fmt.Fprintf(w, "Usage of %s: reads '.pings' files and outputs the raw data to the stdout\n"+
-			"\t rawdata [-all][-csv] FILES\n\n"+
+			"\t rawdata [-all][-csv][-format FORMAT] FILES\n\n"+
 			"e.g. %s rawdata my_ping_capture.ping\n", os.Args[0], os.Args[0])
 		f.PrintDefaults()
 	}
@@ -52,51 +73,183 @@ func RunPrintData(c *Config) {
 		fmt.Fprintf(os.Stderr, "No files found, exiting. Use -h/--help to print usage instructions.\n")
 		exit.Success()
 	}
+	format := c.getFormat()
+	if !slices.Contains([]string{summaryFormat, allFormat, csvFormat, jsonFormat, ndjsonFormat, anomaliesFormat}, format) {
+		fmt.Fprintf(os.Stderr, "Unknown -format %q. Use -h/--help to print usage instructions.\n", format)
+		exit.Silent()
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
 	for _, file := range toPrint {
-		f, err := os.OpenFile(file, os.O_RDONLY, 0)
+		f, err := files.OpenReadOnly(file)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to open %q, %s\n", file, err.Error())
 			continue
 		}
-		d, err := data.ReadData(f)
-		if err != nil {
+		defer f.Close()
+		if err = handle(w, format, f); err != nil {
+			_ = w.Flush()
 			fmt.Fprintf(os.Stderr, "Failed to parse %q, %s\n", file, err.Error())
-			continue
 		}
-		defer f.Close()
-		handle(*c.printAll, *c.toCSV, d)
 	}
 }
 
-func handle(printAll, toCSV bool, d *data.Data) {
+// getFormat resolves the legacy -all and -csv flags into a -format.
+func (c *Config) getFormat() string {
 	// In precedence order of flags
 	switch {
-	case printAll:
-		fmt.Fprintf(os.Stdout, "BEGIN %s: %s\n", d.URL, d.Header.String())
-		for i := range d.TotalCount {
-			p := d.GetFull(i)
-			fmt.Fprintf(os.Stdout, "%d: %s\n", i, p.String())
+	case *c.printAll:
+		return allFormat
+	case *c.toCSV:
+		return csvFormat
+	default:
+		return *c.format
+	}
+}
+
+// handle writes the '.pings' file [r] in the given format. All but the summary are streamed (see
+// [data.Stream]) so that output starts immediately even for very large files. A file which holds many targets
+// is written as if each target was a separate file.
+func handle(w io.Writer, format string, r io.Reader) error {
+	if format == summaryFormat {
+		t, err := data.ReadTargets(r)
+		if err != nil {
+			return err
+		}
+		for _, d := range t.Targets {
+			fmt.Fprintln(w, d.Summary())
+			printAnnotations(w, d.Annotations)
+		}
+		return nil
+	}
+	streams, err := data.NewTargetStreams(r)
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		if err := handleStream(w, format, s); err != nil {
+			return err
 		}
-		fmt.Fprintf(os.Stdout, "END %s: %s\n", d.URL, d.Header.String())
-	case toCSV:
-		handleCSV(d)
+	}
+	return nil
+}
+
+func handleStream(w io.Writer, format string, s *data.Stream) error {
+	switch format {
+	case allFormat:
+		return handleAll(w, s)
+	case csvFormat:
+		return handleCSV(w, s)
+	case jsonFormat:
+		return handleJSON(w, s)
+	case ndjsonFormat:
+		return handleNDJSON(w, s)
+	case anomaliesFormat:
+		return handleAnomalies(w, s)
 	default:
-		fmt.Fprintln(os.Stdout, d.Summary())
+		panic("exhaustive:enforce")
+	}
+}
+
+func handleAll(w io.Writer, s *data.Stream) error {
+	fmt.Fprintf(w, "BEGIN %s: %s\n", s.URL, s.Header.String())
+	err := forEach(s, func(i int64, p ping.PingResults) error {
+		_, err := fmt.Fprintf(w, "%d: %s\n", i, p.String())
+		return err
+	})
+	if err != nil {
+		return err
 	}
+	printAnnotations(w, s.Annotations)
+	fmt.Fprintf(w, "END %s: %s\n", s.URL, s.Header.String())
+	return nil
 }
 
-func handleCSV(d *data.Data) {
-	fmt.Fprintln(os.Stdout, "timestamp(RFC3339Nano),latency,dropped,ip,header")
-	fmt.Fprintf(os.Stdout, ",,,,%q\n", d.String())
-	for i := range d.TotalCount {
-		p := d.GetFull(i)
-		fmt.Fprintf(
-			os.Stdout,
-			"%q,%q,%q,%q,\n",
+func printAnnotations(w io.Writer, annotations []data.Annotation) {
+	for _, a := range annotations {
+		fmt.Fprintf(w, "ANNOTATION %s\n", a.String())
+	}
+}
+
+// forEach calls [f] with every point of the stream in insert order along with it's index.
+func forEach(s *data.Stream, f func(int64, ping.PingResults) error) error {
+	for i := int64(0); ; i++ {
+		p, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = f(i, p); err != nil {
+			return err
+		}
+	}
+}
+
+// csvHeader is the first row written by [handleCSV].
+var csvHeader = []string{"timestamp", "latency_ns", "latency_ms", "drop_reason_code", "drop_reason", "ip", "annotation", "url"}
+
+// handleCSV writes a row per point followed by a row per annotation, see docs/rawdata.md.
+func handleCSV(w io.Writer, s *data.Stream) error {
+	c := csv.NewWriter(w)
+	if err := c.Write(csvHeader); err != nil {
+		return err
+	}
+	err := forEach(s, func(_ int64, p ping.PingResults) error {
+		return c.Write([]string{
 			p.Data.Timestamp.Format(time.RFC3339Nano),
-			p.Data.Duration.String(),
+			strconv.FormatInt(int64(p.Data.Duration), 10),
+			strconv.FormatFloat(float64(p.Data.Duration)/float64(time.Millisecond), 'f', -1, 64),
+			strconv.Itoa(int(p.Data.DropReason)),
 			p.Data.DropReason.String(),
 			p.IP.String(),
-		)
+			"",
+			s.URL,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	for _, a := range s.Annotations {
+		if err := c.Write([]string{a.Timestamp.Format(time.RFC3339Nano), "", "", "", "", "", a.Text, s.URL}); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// anomaliesHeader is the first row written by [handleAnomalies].
+var anomaliesHeader = []string{"timestamp", "index", "kind", "latency_ns", "baseline_ns", "score"}
+
+// handleAnomalies writes a row per anomaly found with the same detection as the graph, see docs/rawdata.md.
+func handleAnomalies(w io.Writer, s *data.Stream) error {
+	detector := data.NewAnomalyDetector(data.DefaultAnomalyOptions)
+	err := forEach(s, func(i int64, p ping.PingResults) error {
+		detector.AddPoint(i, p.Data)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c := csv.NewWriter(w)
+	if err := c.Write(anomaliesHeader); err != nil {
+		return err
+	}
+	for _, a := range detector.Anomalies {
+		err := c.Write([]string{
+			a.Timestamp.Format(time.RFC3339Nano),
+			strconv.FormatInt(a.Index, 10),
+			a.Kind.String(),
+			strconv.FormatInt(int64(a.Duration), 10),
+			strconv.FormatInt(int64(a.Baseline), 10),
+			strconv.FormatFloat(a.Score, 'f', 3, 64),
+		})
+		if err != nil {
+			return err
+		}
 	}
+	c.Flush()
+	return c.Error()
 }
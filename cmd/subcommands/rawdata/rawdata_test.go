@@ -0,0 +1,148 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package rawdata_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/rawdata"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	d := makeTestData()
+	var b bytes.Buffer
+	assert.NilError(t, rawdata.Handle(&b, "json", asReader(t, d)))
+
+	var doc map[string]any
+	assert.NilError(t, json.Unmarshal(b.Bytes(), &doc))
+	assert.Check(t, is.Equal(doc["url"], "www.google.com"))
+	assert.Check(t, is.Equal(doc["total_count"], 2.0))
+	stats := doc["header"].(map[string]any)["stats"].(map[string]any)
+	assert.Check(t, is.Equal(stats["good_count"], 1.0))
+	assert.Check(t, is.Equal(stats["dropped_count"], 1.0))
+	assert.Check(t, is.Equal(stats["min_ns"], 8_000_000.0))
+	assert.Check(t, is.Equal(stats["p99_ns"], 8_000_000.0))
+	points := doc["points"].([]any)
+	assert.Equal(t, len(points), 2)
+	assert.Check(t, is.DeepEqual(points[1], map[string]any{
+		"timestamp":        timestamp(start.Add(time.Second)),
+		"latency_ns":       0.0,
+		"dropped":          true,
+		"drop_reason_code": 1.0,
+		"drop_reason":      "Timeout",
+		"ip":               "142.250.179.228",
+		"index":            1.0,
+	}))
+	assert.Check(t, is.Len(doc["network"], 1))
+	assert.Check(t, is.Len(doc["annotations"], 1))
+}
+
+func TestNDJSON(t *testing.T) {
+	t.Parallel()
+	d := makeTestData()
+	var b bytes.Buffer
+	assert.NilError(t, rawdata.Handle(&b, "ndjson", asReader(t, d)))
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(t, len(lines), 4)
+	types := []string{}
+	for _, line := range lines {
+		var v map[string]any
+		assert.NilError(t, json.Unmarshal([]byte(line), &v))
+		types = append(types, v["type"].(string))
+	}
+	assert.Check(t, is.DeepEqual(types, []string{"meta", "point", "point", "annotation"}))
+}
+
+func TestNDJSONMultipleTargets(t *testing.T) {
+	t.Parallel()
+	other := makeTestData()
+	other.URL = "www.example.com"
+	targets := &data.Targets{Targets: []*data.Data{makeTestData(), other}}
+	var in bytes.Buffer
+	assert.NilError(t, targets.AsCompact(&in))
+	var b bytes.Buffer
+	assert.NilError(t, rawdata.Handle(&b, "ndjson", &in))
+
+	urls := []string{}
+	for line := range strings.SplitSeq(strings.TrimSpace(b.String()), "\n") {
+		var v map[string]any
+		assert.NilError(t, json.Unmarshal([]byte(line), &v))
+		if v["type"] == "meta" {
+			urls = append(urls, v["url"].(string))
+		}
+	}
+	assert.Check(t, is.DeepEqual(urls, []string{"www.google.com", "www.example.com"}))
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+	d := makeTestData()
+	var b bytes.Buffer
+	assert.NilError(t, rawdata.Handle(&b, "csv", asReader(t, d)))
+
+	rows, err := csv.NewReader(&b).ReadAll()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(rows, [][]string{
+		{"timestamp", "latency_ns", "latency_ms", "drop_reason_code", "drop_reason", "ip", "annotation", "url"},
+		{timestamp(start), "8000000", "8", "0", "", "142.250.179.228", "", "www.google.com"},
+		{timestamp(start.Add(time.Second)), "0", "0", "1", "Timeout", "142.250.179.228", "", "www.google.com"},
+		{timestamp(start.Add(500 * time.Millisecond)), "", "", "", "", "", `router "rebooted", again`, "www.google.com"},
+	}))
+}
+
+func asReader(t *testing.T, d *data.Data) io.Reader {
+	t.Helper()
+	return bytes.NewReader(th.WriteData(t, d))
+}
+
+var start = th.PingsStart
+
+// timestamp is the expected output for a time, '.pings' files don't store the timezone so it's always local.
+func timestamp(t time.Time) string {
+	return t.Local().Format(time.RFC3339Nano)
+}
+
+func makeTestData() *data.Data {
+	d := th.MakeData(th.Pings(start, 2, time.Second, func(i int, p *ping.PingResults) {
+		if i == 1 {
+			p.Data = ping.PingDataPoint{Timestamp: p.Data.Timestamp, DropReason: ping.Timeout}
+		}
+	}))
+	d.AddAnnotation(data.Annotation{Timestamp: start.Add(500 * time.Millisecond), Text: `router "rebooted", again`})
+	return d
+}
+
+func TestAnomalies(t *testing.T) {
+	t.Parallel()
+	d := th.MakeData(th.Pings(start, 60, time.Second, func(i int, p *ping.PingResults) {
+		if i == 50 {
+			p.Data.Duration = 80 * time.Millisecond
+		}
+	}))
+	var b bytes.Buffer
+	assert.NilError(t, rawdata.Handle(&b, "anomalies", asReader(t, d)))
+
+	rows, err := csv.NewReader(&b).ReadAll()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(rows, [][]string{
+		{"timestamp", "index", "kind", "latency_ns", "baseline_ns", "score"},
+		{timestamp(start.Add(50 * time.Second)), "50", "Spike", "80000000", "8000000", "180.000"},
+	}))
+}
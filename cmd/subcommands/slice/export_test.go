@@ -0,0 +1,16 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package slice
+
+import (
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+func (c *Config) MakeFilters(d *data.Data) (func(ping.PingResults) bool, func(data.Annotation) bool, error) {
+	return c.makeFilters(d)
+}
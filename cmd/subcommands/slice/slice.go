@@ -0,0 +1,129 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package slice
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/tab_completion/tabflags"
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/utils/check"
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"github.com/Lexer747/acci-ping/utils/exit"
+	"github.com/Lexer747/acci-ping/utils/timeutils"
+)
+
+type Config struct {
+	*tabflags.FlagSet
+
+	from        *string
+	to          *string
+	ip          *string
+	output      *string
+	url         *string
+	droppedOnly *bool
+}
+
+func GetFlags() *Config {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	tf := tabflags.NewAutoCompleteFlagSet(f, true, ".pings")
+	ret := &Config{
+		FlagSet: tf,
+		from: tf.String("from", "", "only keep points at or after this time. Either absolute e.g. \"2024-08-02 21:04\" or relative,\n"+
+			"\"+20m\" being 20 minutes after the start of the capture and \"-20m\" 20 minutes before the end. (default start of capture)",
+			tabflags.AutoComplete{Choices: []string{"-1h", "-20m", "+20m"}}),
+		to: tf.String("to", "", "only keep points at or before this time, in the same form as -from. (default end of capture)",
+			tabflags.AutoComplete{Choices: []string{"-1h", "-20m", "+20m"}}),
+		ip: tf.String("ip", "", "only keep points which were sent to this IP address.", tabflags.AutoComplete{}),
+		output: tf.String("out", "", "the '.pings' file to write the sliced result into, must not already exist.",
+			tabflags.AutoComplete{WantsFile: true, FileExt: ".pings"}),
+		url:         tf.String("url", "", "the url to read from files which hold the pings of many urls (see -multi-target).", tabflags.AutoComplete{}),
+		droppedOnly: tf.Bool("dropped", false, "only keep dropped packets."),
+	}
+
+	f.Usage = func() {
+		var programName = "acci-ping " + ansi.Green("slice")
+
+		w := flag.CommandLine.Output()
+		fmt.Fprintf(w, "Usage of %s: reads a '.pings' file and writes a new '.pings' file containing only some of the points\n"+
+			"\t slice [-from TIME][-to TIME][-ip IP][-dropped][-url URL] -out FILE FILE\n\n"+
+			"e.g. '%s -from \"2024-08-02 21:00\" -to +20m -out outage.pings my_ping_capture.pings'\n", programName, programName)
+		f.PrintDefaults()
+	}
+	return ret
+}
+
+func RunSlice(c *Config) {
+	check.Check(c.Parsed(), "flags not parsed")
+	if c.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Expected exactly one input file. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	if *c.output == "" {
+		fmt.Fprintf(os.Stderr, "No output file given, use -out FILE. Use -h/--help to print usage instructions.\n")
+		exit.Silent()
+	}
+	input := c.Arg(0)
+	d, err := files.LoadTarget(input, *c.url)
+	exit.OnErrorMsgf(err, "Failed to read %q", input)
+
+	keepPoint, keepAnnotation, err := c.makeFilters(d)
+	exit.OnError(err)
+	sliced := d.Filter(keepPoint, keepAnnotation)
+
+	f, err := os.OpenFile(*c.output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o777)
+	exit.OnErrorMsgf(err, "Failed to create %q", *c.output)
+	defer f.Close()
+	exit.OnErrorMsgf(sliced.AsCompact(f), "Failed to write %q", *c.output)
+	fmt.Fprintf(os.Stdout, "Kept %d of %d points in %q\n\t%s\n", sliced.TotalCount, d.TotalCount, *c.output, sliced.String())
+}
+
+// makeFilters converts the flags into the filter functions required by [data.Data.Filter], relative times are
+// parsed against the time span of the capture.
+func (c *Config) makeFilters(d *data.Data) (func(ping.PingResults) bool, func(data.Annotation) bool, error) {
+	span := &data.TimeSpan{Begin: d.Header.TimeSpan.Begin, End: d.Header.TimeSpan.End}
+	location := d.Header.TimeSpan.Begin.Location()
+	var err error
+	if *c.from != "" {
+		span.Begin, err = timeutils.ParseTimeOrOffset(*c.from, d.Header.TimeSpan.Begin, d.Header.TimeSpan.End, location)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid -from")
+		}
+	}
+	if *c.to != "" {
+		span.End, err = timeutils.ParseTimeOrOffset(*c.to, d.Header.TimeSpan.Begin, d.Header.TimeSpan.End, location)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid -to")
+		}
+	}
+	if span.End.Before(span.Begin) {
+		return nil, nil, errors.Errorf("-to %s is before -from %s", span.End.Format(time.RFC3339), span.Begin.Format(time.RFC3339))
+	}
+	var ip net.IP
+	if *c.ip != "" {
+		ip = net.ParseIP(*c.ip)
+		if ip == nil {
+			return nil, nil, errors.Errorf("invalid -ip %q is not an IP address", *c.ip)
+		}
+	}
+	keepPoint := func(p ping.PingResults) bool {
+		return span.Contains(p.Data.Timestamp) &&
+			(ip == nil || ip.Equal(p.IP)) &&
+			(!*c.droppedOnly || p.Data.Dropped())
+	}
+	keepAnnotation := func(a data.Annotation) bool {
+		return span.Contains(a.Timestamp)
+	}
+	return keepPoint, keepAnnotation, nil
+}
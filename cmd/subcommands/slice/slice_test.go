@@ -0,0 +1,104 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package slice_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/cmd/subcommands/slice"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestMakeFilters(t *testing.T) {
+	t.Parallel()
+	d, start := makeTestData()
+	tests := []struct {
+		name string
+		args []string
+		// expectedPoints are the minutes after the start of the points kept.
+		expectedPoints      []int
+		expectedAnnotations int
+		expectedError       string
+	}{
+		{name: "everything", args: []string{}, expectedPoints: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, expectedAnnotations: 2},
+		{name: "absolute", args: []string{"-from", "2024-08-02 21:02", "-to", "2024-08-02 21:04"}, expectedPoints: []int{2, 3, 4}},
+		{name: "RFC3339", args: []string{"-from", "2024-08-02T21:08:00Z"}, expectedPoints: []int{8, 9}, expectedAnnotations: 1},
+		{name: "offset from start", args: []string{"-to", "+1m30s"}, expectedPoints: []int{0, 1}, expectedAnnotations: 1},
+		{name: "offset from end", args: []string{"-from", "-2m"}, expectedPoints: []int{7, 8, 9}, expectedAnnotations: 1},
+		{name: "both offsets", args: []string{"-from", "+3m", "-to", "-5m"}, expectedPoints: []int{3, 4}},
+		{name: "ip", args: []string{"-ip", "10.0.0.2"}, expectedPoints: []int{1, 3, 5, 7, 9}, expectedAnnotations: 2},
+		{name: "dropped", args: []string{"-dropped"}, expectedPoints: []int{2, 6}, expectedAnnotations: 2},
+		{name: "ip and dropped", args: []string{"-ip", "10.0.0.1", "-dropped", "-to", "+5m"}, expectedPoints: []int{2}, expectedAnnotations: 1},
+		{name: "bad from", args: []string{"-from", "yesterday"}, expectedError: "invalid -from"},
+		{name: "bad to", args: []string{"-to", "+forever"}, expectedError: "invalid -to"},
+		{name: "to before from", args: []string{"-from", "+5m", "-to", "+2m"}, expectedError: "-to 2024-08-02T21:02:00Z is before -from 2024-08-02T21:05:00Z"},
+		{name: "bad ip", args: []string{"-ip", "10.0.0"}, expectedError: `invalid -ip "10.0.0" is not an IP address`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			c := slice.GetFlags()
+			assert.NilError(t, c.Parse(test.args))
+			keepPoint, keepAnnotation, err := c.MakeFilters(d)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			kept := []int{}
+			for i := range d.TotalCount {
+				p := d.GetFull(i)
+				if keepPoint(p) {
+					kept = append(kept, int(p.Data.Timestamp.Sub(start)/time.Minute))
+				}
+			}
+			assert.Check(t, is.DeepEqual(kept, test.expectedPoints))
+			annotations := 0
+			for _, a := range d.Annotations {
+				if keepAnnotation(a) {
+					annotations++
+				}
+			}
+			assert.Check(t, is.Equal(annotations, test.expectedAnnotations))
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	c := slice.GetFlags()
+	var b strings.Builder
+	c.SetOutput(&b)
+	c.PrintDefaults()
+	for _, name := range []string{"-from", "-to", "-ip", "-out", "-dropped"} {
+		assert.Check(t, is.Contains(b.String(), name))
+	}
+}
+
+// makeTestData is a point a minute for ten minutes alternating between two IPs, every fourth is dropped. The
+// first and last are good so that the offsets are from them. There's an annotation a minute after the
+// start and another a minute before the end.
+func makeTestData() (*data.Data, time.Time) {
+	start := time.Date(2024, time.August, 2, 21, 0, 0, 0, time.UTC)
+	ips := []net.IP{net.IPv4(10, 0, 0, 1), net.IPv4(10, 0, 0, 2)}
+	d := th.MakeData(th.Pings(start, 10, time.Minute, func(i int, p *ping.PingResults) {
+		if i%4 == 2 {
+			p.Data = ping.PingDataPoint{Timestamp: p.Data.Timestamp, DropReason: ping.Timeout}
+		}
+		p.IP = ips[i%2]
+	}))
+	d.AddAnnotation(data.Annotation{Timestamp: start.Add(time.Minute), Text: "first"})
+	d.AddAnnotation(data.Annotation{Timestamp: start.Add(8 * time.Minute), Text: "last"})
+	return d, start
+}
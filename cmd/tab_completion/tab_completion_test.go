@@ -102,6 +102,10 @@ func TestGetChoices(t *testing.T) {
 		})
 		start := sliceutils.TakeRandom(starting)
 		expectedFlags := sliceutils.Remove(acciPingNonDebugFlags(), start)
+		if ac := accipingFlags.Fs.GetAutoCompleteFor(start); ac != nil && len(ac.Choices) != 0 {
+			// A flag with choices completes to them instead of the other flags.
+			expectedFlags = ac.Choices
+		}
 
 		actual, err := runGetChoices("acci-ping", start, "")
 		assert.NilError(t, err)
@@ -196,6 +200,10 @@ func make_acciping_Flags() Command {
 	_ = tf.Bool("follow", false, "skipped for test")
 	_ = tf.Int("debug-fps", 240, "skipped for test")
 	_ = tf.Bool("logarithmic", false, "skipped for test")
+	_ = tf.Bool("multi-target", false, "skipped for test")
+	_ = tf.Int("rotate-size-mb", 0, "skipped for test")
+	_ = tf.Int("retention-days", 0, "skipped for test")
+	_ = tf.String("retention", "downsample", "skipped for test", tabflags.AutoComplete{Choices: []string{"downsample", "delete"}})
 	return Command{Cmd: "acci-ping", Fs: tf}
 }
 
@@ -210,6 +218,8 @@ func make_drawframe_Flags() Command {
 	_ = tf.String("theme", "", "skipped for test",
 		tabflags.AutoComplete{Choices: themes.GetBuiltInNames(), WantsFile: true})
 	_ = tf.Bool("log-scale", false, "skipped for test")
+	_ = tf.Bool("periodicity", false, "skipped for test")
+	_ = tf.Bool("stitch", false, "skipped for test")
 	return Command{Cmd: "drawframe", Fs: tf}
 }
 
@@ -55,9 +55,35 @@ func main() {
 			},
 		},
 	}
+	// The special keys e.g. arrow keys, pastes and mouse clicks, print the name of the key decoded
+	keyNameListener := terminal.KeyListener{
+		Name: "keyName",
+		Applicable: func(k terminal.Key) bool {
+			if k.Code == terminal.KeyMouse {
+				// Ignore the mouse just moving, there's far too many of them.
+				return k.Mouse.Action != terminal.MouseMotion
+			}
+			return k.Code != terminal.KeyRune || k.Modifiers != 0
+		},
+		Action: func(k terminal.Key) error {
+			switch k.Code {
+			case terminal.KeyPaste:
+				return t.Print(themes.Highlight("Pasted:" + strconv.Quote(k.Paste) + " "))
+			case terminal.KeyMouse:
+				return t.Print(themes.Highlight(fmt.Sprintf("Mouse:%d,%d ", k.Mouse.X, k.Mouse.Y)))
+			}
+			return t.Print(themes.Highlight("Key:" + k.String() + " "))
+		},
+	}
 	// Actually start the terminal program. Note that the listeners are applied in order, so if more than one
 	// is applicable then the last entry will happen last
-	cleanup, err := t.StartRaw(ctx, cancelFunc, []terminal.ConditionalListener{clearScreenListener}, []terminal.Listener{writeLineListener})
+	cleanup, err := t.StartRaw(
+		ctx,
+		cancelFunc,
+		[]terminal.ConditionalListener{clearScreenListener},
+		[]terminal.KeyListener{keyNameListener},
+		[]terminal.Listener{writeLineListener},
+	)
 	defer cleanup()
 	if err != nil {
 		panic(err.Error())
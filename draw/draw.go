@@ -44,18 +44,29 @@ func (b *Buffer) Reset(toReset ...Index) {
 }
 
 var (
-	BarIndex      = newIndex()
-	ControlIndex  = newIndex()
-	DataIndex     = newIndex()
-	DroppedIndex  = newIndex()
-	EmojiIndex    = newIndex()
-	GradientIndex = newIndex()
-	HelpIndex     = newIndex()
-	KeyIndex      = newIndex()
-	SpinnerIndex  = newIndex()
-	ToastIndex    = newIndex()
-	XAxisIndex    = newIndex()
-	YAxisIndex    = newIndex()
+	AnnotationIndex  = newIndex()
+	AnomalyIndex     = newIndex()
+	AnomalyListIndex = newIndex()
+	BarIndex         = newIndex()
+	ControlIndex     = newIndex()
+	DataIndex        = newIndex()
+	DroppedIndex     = newIndex()
+	EmojiIndex       = newIndex()
+	GradientIndex    = newIndex()
+	GuideIndex       = newIndex()
+	HeatmapIndex     = newIndex()
+	HelpIndex        = newIndex()
+	HistogramIndex   = newIndex()
+	InputIndex       = newIndex()
+	InspectIndex     = newIndex()
+	KeyIndex         = newIndex()
+	OverlayIndex     = newIndex()
+	SpinnerIndex     = newIndex()
+	ToastIndex       = newIndex()
+	TooltipIndex     = newIndex()
+	WindowsIndex     = newIndex()
+	XAxisIndex       = newIndex()
+	YAxisIndex       = newIndex()
 )
 
 // PaintOrder is the Z-order is top to bottom so the first item added to ret is at the back, the last item is
@@ -63,32 +74,61 @@ var (
 var PaintOrder = []Index{
 	// gradient is on the bottom since it's the most "fluffy" part of the presentation, it's interpolated data
 	GradientIndex,
+	// guides are faint markers of the periods in the data, everything else should be readable over them.
+	GuideIndex,
 	// bars are the span indicators, telling the user when a break in the continuous time axis occurs.
 	BarIndex,
 	// dropped bars are the dropped packets indicators.
 	DroppedIndex,
+	// an overlaid series is only there for comparison so the data is drawn over it.
+	OverlayIndex,
 	// bars should be overwritten by data and axis
 	DataIndex,
+	// anomalies are marked over the data points they belong to.
+	AnomalyIndex,
 	YAxisIndex,
 	XAxisIndex,
 	// key is inside the frame itself so should come on top of data to be readable
 	KeyIndex,
+	// annotation labels are user notes, like the key they should be readable on top of the data. The markers
+	// themselves are drawn with the bars.
+	AnnotationIndex,
+	// the histogram, heatmap and anomaly list are large boxes which cover the graph, so they're below the
+	// smaller GUI boxes.
+	AnomalyListIndex,
+	HeatmapIndex,
+	HistogramIndex,
+	// the recent stats are a small box in the corner like the controls.
+	WindowsIndex,
+	// the inspect cursor is drawn over the graph and the boxes it would otherwise be hidden behind.
+	InspectIndex,
 	// Notifications can appear above the graph as they're ephemeral
 	ToastIndex,
 	ControlIndex,
 	HelpIndex,
+	// user input is the most recent interaction so is drawn above the other GUI boxes
+	InputIndex,
 	EmojiIndex,
+	// the tooltip follows the mouse so it's what the user is looking at.
+	TooltipIndex,
 	// if we can't see the spinner we may be worried the program is dead
 	SpinnerIndex,
 }
 
 // GraphIndexes is the [PaintOrder] with the GUI indexes removed
 var GraphIndexes = sliceutils.Remove(PaintOrder,
+	AnomalyListIndex,
 	ControlIndex,
 	EmojiIndex,
+	HeatmapIndex,
 	HelpIndex,
+	HistogramIndex,
+	InputIndex,
+	InspectIndex,
 	SpinnerIndex,
 	ToastIndex,
+	TooltipIndex,
+	WindowsIndex,
 )
 
 // GUIIndexes is the above paint order with the [GraphIndexes] indexes removed
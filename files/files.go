@@ -1,72 +1,303 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
 package files
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Lexer747/acci-ping/graph/data"
 	"github.com/Lexer747/acci-ping/utils/check"
 	"github.com/Lexer747/acci-ping/utils/errors"
 )
 
-// LoadFile will read a '.pings' file returning the data and the file handle (opened in read/write), or any
-// error if a disk issue occurs or the data format was un-parsable.
-func LoadFile(path string) (*data.Data, *os.File, error) {
-	f, err := os.OpenFile(path, os.O_RDWR, 0o777)
+// Every '.pings' file is protected by two independent advisory byte range locks. A capture holds the capture
+// lock for as long as it's running so that a second capture can't start. The data lock is taken shared by
+// readers while reading so they always see a consistent snapshot, and exclusive by a capture only while it
+// rewrites the file. Neither lock is ever converted so the capture lock can't be lost while writing.
+
+// ErrLocked is returned when a '.pings' file can't be captured to because another process is using it.
+var ErrLocked = errors.New("the file is in use by another acci-ping process, only one capture can write to a file at a time")
+
+// ErrWrongURL is returned when capturing a url to a '.pings' file which only holds the pings of other urls
+// and adding another target wasn't allowed, see [StreamOrCreateFile].
+var ErrWrongURL = errors.New("the file holds the pings of a different url")
+
+// ErrBusy is returned by [CaptureFile.Overwrite] when a reader is part way through reading the file, nothing
+// was written. The last write of a file should use [CaptureFile.OverwriteWait] so that it's never skipped.
+var ErrBusy = errors.New("the file is being read by another process")
+
+type lockMode int
+
+const (
+	shared lockMode = iota
+	exclusive
+)
+
+// lockRange is which of the locks of a '.pings' file to take.
+type lockRange int
+
+const (
+	// dataLock covers the contents of the file.
+	dataLock lockRange = iota
+	// captureLock is a single byte far past the end of the file, no data is ever written there.
+	captureLock
+)
+
+// captureLockOffset is where the [captureLock] is, the [dataLock] is everything before it.
+const captureLockOffset = 1 << 62
+
+// span is the start and length in bytes of the range.
+func (r lockRange) span() (int64, int64) {
+	if r == captureLock {
+		return captureLockOffset, 1
+	}
+	return 0, captureLockOffset
+}
+
+// errWouldBlock is returned by lock when it's not waiting and the lock is held elsewhere.
+var errWouldBlock = errors.New("lock would block")
+
+// startupAttempts and startupWait bound how long a new capture waits for retention to release the file before
+// assuming the lock is held by another capture.
+const (
+	startupAttempts = 20
+	startupWait     = 50 * time.Millisecond
+)
+
+// LoadFile will read a consistent snapshot of a '.pings' file, or any error if a disk issue occurs or the
+// data format was un-parsable. This is safe to call while another process is capturing to the file.
+func LoadFile(path string) (*data.Data, error) {
+	f, err := OpenReadOnly(path)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	defer f.Close()
+
+	return data.ReadData(f)
+}
 
-	// File exists, read the data from it
-	existingData := &data.Data{}
-	fromFile, err := io.ReadAll(f)
+// LoadTargets is [LoadFile] for a '.pings' file which may hold many targets, see [data.Targets].
+func LoadTargets(path string) (*data.Targets, error) {
+	f, err := OpenReadOnly(path)
 	if err != nil {
-		f.Close()
-		return nil, nil, err
+		return nil, err
 	}
-	_, err = existingData.FromCompact(fromFile)
+	defer f.Close()
+
+	return data.ReadTargets(f)
+}
+
+// LoadTarget reads only the target of the [url] from a '.pings' file. If [url] is empty the file must hold a
+// single target otherwise [data.ErrMultipleTargets] is returned, if the file doesn't hold the pings of the
+// [url] then [ErrWrongURL] is returned.
+func LoadTarget(path string, url string) (*data.Data, error) {
+	t, err := LoadTargets(path)
 	if err != nil {
+		return nil, err
+	}
+	if url == "" {
+		if len(t.Targets) != 1 {
+			return nil, errors.WrapErr(errors.Errorf("it holds %s, pick one with -url", quoteURLs(t.URLs())), data.ErrMultipleTargets)
+		}
+		return t.Targets[0], nil
+	}
+	index := t.Find(url)
+	if index < 0 {
+		return nil, errors.WrapErr(errors.Errorf("it holds %s not %q", quoteURLs(t.URLs()), url), ErrWrongURL)
+	}
+	return t.Targets[index], nil
+}
+
+// OpenReadOnly opens a '.pings' file for reading while holding the shared data lock, a capture won't write to
+// the file until it's closed.
+func OpenReadOnly(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := lock(f, dataLock, shared, true); err != nil {
 		f.Close()
-		return nil, nil, err
+		return nil, errors.Wrapf(err, "while locking %q", path)
 	}
+	return f, nil
+}
+
+// CaptureFile is a '.pings' file which is being captured to, see [StreamOrCreateFile]. It holds the capture
+// lock until closed.
+type CaptureFile struct {
+	*os.File
 
-	return existingData, f, nil
+	// targets is set when the file holds many targets, every other target is written back unchanged with
+	// each update.
+	targets *data.Targets
+	// index is the target being captured to within [targets].
+	index int
 }
 
-func MakeNewEmptyFile(path string, url string) (*data.Data, *os.File, error) {
-	newFile, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o777)
+// Overwrite replaces the contents of the file with [d], or the target being captured to if the file holds
+// many targets. The exclusive data lock is only held for the duration of the write, if a reader currently
+// holds the shared data lock [ErrBusy] is returned instead of waiting, the caller should write again with the
+// next update.
+func (f *CaptureFile) Overwrite(d *data.Data) error {
+	return f.overwrite(d, false)
+}
+
+// OverwriteWait is [CaptureFile.Overwrite] but waits for any readers to finish instead of returning [ErrBusy],
+// it's for the last write of a file when there won't be a next update.
+func (f *CaptureFile) OverwriteWait(d *data.Data) error {
+	return f.overwrite(d, true)
+}
+
+func (f *CaptureFile) overwrite(d *data.Data, wait bool) error {
+	var toWrite data.Compact = d
+	if f.targets != nil {
+		f.targets.Targets[f.index] = d
+		toWrite = f.targets
+	}
+	err := lock(f.File, dataLock, exclusive, wait)
+	if errors.Is(err, errWouldBlock) {
+		return ErrBusy
+	}
 	if err != nil {
-		return nil, nil, err
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err == nil {
+		err = toWrite.AsCompact(f.File)
 	}
-	d := data.NewData(url)
-	// Write the initial data to the file on exit
-	return d, newFile, d.AsCompact(newFile)
+	if err == nil {
+		var end int64
+		end, err = f.Seek(0, io.SeekCurrent)
+		if err == nil {
+			err = f.Truncate(end)
+		}
+	}
+	return errors.Join(err, unlock(f.File, dataLock))
 }
 
-// LoadOrCreateFile will read a '.pings' file returning the data and the file handle (opened in read/write),
-// or any error if a disk issue occurs or the data format was un-parsable. If the file isn't found at the
-// given path then this specific error is swallowed and a new file is created with empty data pointing the
-// given url.
-func LoadOrCreateFile(path string, url string) (*data.Data, *os.File, error) {
-	d, f, err := LoadFile(path)
-	switch {
-	case err != nil && !errors.Is(err, os.ErrNotExist):
-		// Some error we are not expecting
+// StreamOrCreateFile will open a '.pings' file returning a [data.Stream] of it's contents and the file handle
+// (opened in read/write), or any error if a disk issue occurs or the data format was un-parsable. Only the
+// meta data is read, the points are read by the caller. If the file isn't found at the given path then this
+// specific error is swallowed and a new file is created with empty data pointing the given url.
+//
+// If the file doesn't hold the pings of the url then [ErrWrongURL] is returned, unless [multiTarget] is set in
+// which case the url is added as a new target (see [data.Targets]). If another acci-ping process is already
+// capturing to the file then [ErrLocked] is returned.
+func StreamOrCreateFile(path string, url string, multiTarget bool) (*data.Stream, *CaptureFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o777)
+	if err != nil {
 		return nil, nil, err
-	case err != nil && errors.Is(err, os.ErrNotExist):
-		d, f, err = MakeNewEmptyFile(path, url)
-		if err != nil {
+	}
+	if err := lockForCapture(f); err != nil {
+		f.Close()
+		return nil, nil, errors.Wrapf(err, "cannot capture to %q", path)
+	}
+	// Keep readers out until the file is known to be valid.
+	if err := lock(f, dataLock, exclusive, true); err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if stat.Size() == 0 {
+		// A new file, write the initial data
+		if err := data.NewData(url).AsCompact(f); err != nil {
+			f.Close()
 			return nil, nil, err
 		}
 	}
-	check.Check(d != nil && f != nil && d.URL == url, "data should be initialised")
-	// Once the data is written/read reset the handle back to the start
-	_, seekErr := f.Seek(0, 0)
-	return d, f, errors.Join(seekErr, err)
+	// Also resets the handle back to the start
+	streams, err := data.NewTargetStreams(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	ret := &CaptureFile{File: f}
+	s, err := ret.pickTarget(streams, url, multiTarget)
+	if err != nil {
+		f.Close()
+		return nil, nil, errors.Wrapf(err, "cannot capture to %q", path)
+	}
+	check.Check(s.URL == url, "data should be initialised")
+	// Now the file is known to be valid allow readers in.
+	if err := unlock(f, dataLock); err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return s, ret, nil
+}
+
+// pickTarget returns the stream of the [url] from all the [streams] in the file. If the file holds (or is
+// about to hold) more than one target then every other target is read into memory, so that they can be
+// written back with each update.
+func (f *CaptureFile) pickTarget(streams []*data.Stream, url string, multiTarget bool) (*data.Stream, error) {
+	index := slices.IndexFunc(streams, func(s *data.Stream) bool { return s.URL == url })
+	if index >= 0 && len(streams) == 1 {
+		return streams[index], nil
+	}
+	if index < 0 && !multiTarget {
+		urls := make([]string, len(streams))
+		for i, s := range streams {
+			urls[i] = s.URL
+		}
+		return nil, errors.WrapErr(errors.Errorf("it holds %s not %q", quoteURLs(urls), url), ErrWrongURL)
+	}
+	f.targets = &data.Targets{Targets: make([]*data.Data, len(streams))}
+	for i, s := range streams {
+		if i == index {
+			continue
+		}
+		d, err := s.ToData()
+		if err != nil {
+			return nil, errors.Wrapf(err, "while reading target %q", s.URL)
+		}
+		f.targets.Targets[i] = d
+	}
+	if index >= 0 {
+		f.index = index
+		return streams[index], nil
+	}
+	// A new target, which has no points yet.
+	empty := data.NewData(url)
+	f.targets.Targets = append(f.targets.Targets, empty)
+	f.index = len(f.targets.Targets) - 1
+	var b bytes.Buffer
+	if err := empty.AsCompact(&b); err != nil {
+		return nil, err
+	}
+	return data.NewStream(bytes.NewReader(b.Bytes()))
+}
+
+func quoteURLs(urls []string) string {
+	quoted := make([]string, len(urls))
+	for i, url := range urls {
+		quoted[i] = fmt.Sprintf("%q", url)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// lockForCapture takes the exclusive capture lock, retention only holds the lock briefly so it's waited on for
+// a short time. Any longer and it's assumed another capture holds the lock.
+func lockForCapture(f *os.File) error {
+	for range startupAttempts {
+		err := lock(f, captureLock, exclusive, false)
+		if !errors.Is(err, errWouldBlock) {
+			return err
+		}
+		time.Sleep(startupWait)
+	}
+	return ErrLocked
 }
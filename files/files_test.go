@@ -0,0 +1,141 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package files_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const testURL = "www.example.com"
+
+func TestSecondCaptureIsLocked(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "capture.pings")
+	_, f, err := files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+
+	_, _, err = files.StreamOrCreateFile(path, testURL, false)
+	assert.Check(t, is.ErrorIs(err, files.ErrLocked))
+
+	// Once the first capture stops another can start
+	assert.NilError(t, f.Close())
+	_, f, err = files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+}
+
+func TestReadDuringCapture(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "capture.pings")
+	_, f, err := files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	d, err := files.LoadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(d.URL, testURL))
+}
+
+func TestOverwriteWhileReading(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "capture.pings")
+	_, f, err := files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	reader, err := files.OpenReadOnly(path)
+	assert.NilError(t, err)
+	err = f.Overwrite(data.NewData(testURL))
+	assert.Check(t, is.ErrorIs(err, files.ErrBusy))
+
+	// The capture still holds the capture lock so another capture can't start
+	_, _, err = files.StreamOrCreateFile(path, testURL, false)
+	assert.Check(t, is.ErrorIs(err, files.ErrLocked))
+
+	assert.NilError(t, reader.Close())
+	assert.NilError(t, f.Overwrite(data.NewData(testURL)))
+	_, _, err = files.StreamOrCreateFile(path, testURL, false)
+	assert.Check(t, is.ErrorIs(err, files.ErrLocked))
+	d, err := files.LoadFile(path)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(d.URL, testURL))
+}
+
+func TestOverwriteWaitForReader(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "capture.pings")
+	_, f, err := files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	reader, err := files.OpenReadOnly(path)
+	assert.NilError(t, err)
+	written := make(chan error)
+	go func() { written <- f.OverwriteWait(data.NewData(testURL)) }()
+	select {
+	case err := <-written:
+		t.Fatalf("wrote while the file was being read: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.NilError(t, reader.Close())
+	assert.NilError(t, <-written)
+}
+
+func TestCaptureDifferentURL(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "capture.pings")
+	_, f, err := files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+	first := data.NewData(testURL)
+	first.AddPoint(ping.PingResults{Data: ping.PingDataPoint{Timestamp: time.Now(), Duration: time.Millisecond}, IP: net.IPv4bcast})
+	assert.NilError(t, f.Overwrite(first))
+	assert.NilError(t, f.Close())
+
+	_, _, err = files.StreamOrCreateFile(path, "www.google.com", false)
+	assert.Check(t, is.ErrorIs(err, files.ErrWrongURL))
+
+	// Opt-in to adding the url as a second target
+	s, f, err := files.StreamOrCreateFile(path, "www.google.com", true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(s.URL, "www.google.com"))
+	assert.NilError(t, f.Overwrite(data.NewData("www.google.com")))
+	assert.NilError(t, f.Close())
+
+	_, err = files.LoadFile(path)
+	assert.Check(t, is.ErrorIs(err, data.ErrMultipleTargets))
+	r, err := files.OpenReadOnly(path)
+	assert.NilError(t, err)
+	targets, err := data.ReadTargets(r)
+	assert.NilError(t, err)
+	assert.NilError(t, r.Close())
+	assert.Check(t, is.DeepEqual(targets.URLs(), []string{testURL, "www.google.com"}))
+	assert.Check(t, is.Equal(targets.Targets[0].TotalCount, int64(1)))
+
+	// A reader of a single target has to pick which
+	_, err = files.LoadTarget(path, "")
+	assert.Check(t, is.ErrorIs(err, data.ErrMultipleTargets))
+	d, err := files.LoadTarget(path, testURL)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(d.TotalCount, int64(1)))
+	_, err = files.LoadTarget(path, "www.bing.com")
+	assert.Check(t, is.ErrorIs(err, files.ErrWrongURL))
+
+	// Either target can be captured to again without opting in
+	s, f, err = files.StreamOrCreateFile(path, testURL, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(s.TotalCount, int64(1)))
+	assert.NilError(t, f.Close())
+}
@@ -0,0 +1,16 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package files
+
+import "golang.org/x/sys/unix"
+
+// Open file description locks belong to the file handle like flock, rather than the process, so a capture and
+// a reader in the same process still exclude each other.
+const (
+	setLockCmd     = unix.F_OFD_SETLK
+	setLockWaitCmd = unix.F_OFD_SETLKW
+)
@@ -0,0 +1,18 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+//go:build unix && !linux
+
+package files
+
+import "golang.org/x/sys/unix"
+
+// Without open file description locks these are POSIX record locks, which belong to the process. So they only
+// exclude other processes and are all released when any handle of the file is closed by the process.
+const (
+	setLockCmd     = unix.F_SETLK
+	setLockWaitCmd = unix.F_SETLKW
+)
@@ -0,0 +1,55 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+//go:build unix
+
+package files
+
+import (
+	"io"
+	"os"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"golang.org/x/sys/unix"
+)
+
+func lock(f *os.File, r lockRange, mode lockMode, wait bool) error {
+	how := int16(unix.F_RDLCK)
+	if mode == exclusive {
+		how = unix.F_WRLCK
+	}
+	return setLock(f, r, how, wait)
+}
+
+func unlock(f *os.File, r lockRange) error {
+	return setLock(f, r, unix.F_UNLCK, false)
+}
+
+// removeLocked removes the file at [path] while [f] still holds it's locks, so that a capture can't start
+// writing to it first.
+func removeLocked(f *os.File, path string) error {
+	return os.Remove(path)
+}
+
+func setLock(f *os.File, r lockRange, how int16, wait bool) error {
+	cmd := setLockCmd
+	if wait {
+		cmd = setLockWaitCmd
+	}
+	start, length := r.span()
+	lk := unix.Flock_t{Type: how, Whence: io.SeekStart, Start: start, Len: length}
+	for {
+		err := unix.FcntlFlock(f.Fd(), cmd, &lk)
+		switch {
+		case errors.Is(err, unix.EINTR):
+			continue
+		case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EACCES):
+			return errWouldBlock
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package files
+
+import (
+	"os"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+	"golang.org/x/sys/windows"
+)
+
+// lock is the windows equivalent of a byte range lock, which like the unix locks belongs to the file handle.
+func lock(f *os.File, r lockRange, mode lockMode, wait bool) error {
+	var flags uint32
+	if mode == exclusive {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	if !wait {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+	start, length := r.span()
+	err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, low(length), high(length), overlapped(start))
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errWouldBlock
+	}
+	return err
+}
+
+func unlock(f *os.File, r lockRange) error {
+	start, length := r.span()
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, low(length), high(length), overlapped(start))
+}
+
+// removeLocked removes the file at [path] which [f] holds the locks of. Windows can't remove an open file so
+// it's closed first, if a capture opened the file in the meantime the remove fails instead.
+func removeLocked(f *os.File, path string) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func overlapped(offset int64) *windows.Overlapped {
+	return &windows.Overlapped{Offset: low(offset), OffsetHigh: high(offset)}
+}
+
+// low and high split the positive [n] into the two halves windows expects.
+func low(n int64) uint32 {
+	//nolint:gosec
+	// G115 the ranges are positive.
+	return uint32(n)
+}
+
+func high(n int64) uint32 {
+	return low(n >> 32)
+}
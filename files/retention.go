@@ -0,0 +1,141 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package files
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// RetentionMode is what happens to the files of a [Template] once they're older than [Retention.MaxAge].
+type RetentionMode int
+
+const (
+	// Downsample keeps only the fastest, slowest and first and last dropped point of each minute, see
+	// [data.Data.Downsample].
+	Downsample RetentionMode = iota
+	// Delete removes the file.
+	Delete
+)
+
+// DownsampleResolution is the width of time which keeps the extreme points when a file is downsampled.
+const DownsampleResolution = time.Minute
+
+// ParseRetentionMode parses the name of a mode, either "downsample" or "delete".
+func ParseRetentionMode(mode string) (RetentionMode, error) {
+	switch mode {
+	case "downsample":
+		return Downsample, nil
+	case "delete":
+		return Delete, nil
+	default:
+		return Downsample, errors.Errorf("unknown retention mode %q, must be either \"downsample\" or \"delete\"", mode)
+	}
+}
+
+// Retention is applied to files which haven't been written to for longer than [MaxAge], see [Template.Retain].
+type Retention struct {
+	// MaxAge of 0 means files are kept forever.
+	MaxAge time.Duration
+	Mode   RetentionMode
+}
+
+// Retain applies the retention to every file of the template which was last modified before [now]-MaxAge.
+// Files which are being captured to by any acci-ping process are skipped. A downsampled file keeps it's
+// modification time so it's not downsampled again until it changes.
+func (t Template) Retain(r Retention, now time.Time) error {
+	if r.MaxAge == 0 {
+		return nil
+	}
+	paths, err := t.Files()
+	if err != nil {
+		return errors.Wrap(err, "while applying retention")
+	}
+	var errs []error
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if now.Sub(stat.ModTime()) < r.MaxAge {
+			continue
+		}
+		if err := retainFile(path, r.Mode, stat.ModTime()); err != nil {
+			errs = append(errs, errors.Wrapf(err, "while applying retention to %q", path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func retainFile(path string, mode RetentionMode, modified time.Time) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = lock(f, captureLock, exclusive, false)
+	if errors.Is(err, errWouldBlock) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := lock(f, dataLock, exclusive, true); err != nil {
+		return err
+	}
+	switch mode {
+	case Delete:
+		return removeLocked(f, path)
+	case Downsample:
+		if err := downsampleFile(f); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Chtimes(path, modified, modified)
+	default:
+		panic("exhaustive:enforce")
+	}
+}
+
+// downsampleFile rewrites every target of the file with [data.Data.Downsample], the file is untouched if
+// this wouldn't remove any points.
+func downsampleFile(f *os.File) error {
+	t, err := data.ReadTargets(f)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i, d := range t.Targets {
+		t.Targets[i] = d.Downsample(DownsampleResolution)
+		changed = changed || t.Targets[i].TotalCount != d.TotalCount
+	}
+	if !changed {
+		return nil
+	}
+	var toWrite data.Compact = t
+	if len(t.Targets) == 1 {
+		toWrite = t.Targets[0]
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := toWrite.AsCompact(f); err != nil {
+		return err
+	}
+	end, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	return f.Truncate(end)
+}
@@ -0,0 +1,215 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// Template is a '-file' path which may contain the verbs %Y, %m, %d and %H (the year, month, day and hour) in
+// the file name, e.g. "captures/%Y-%m-%d.pings". A capture moves on to a new file whenever the expanded path
+// changes, so the smallest verb used is the rotation period. "%%" is a literal '%'.
+//
+// If [Template.MaxSize] is set then a capture also moves on once a file reaches that many bytes, the next
+// file of the same period has a sequence number before the extension e.g. "captures/2024-08-02.1.pings".
+type Template struct {
+	dir  string
+	name string
+	ext  string
+	// matcher matches the file name of every file of the template, see [Template.Files].
+	matcher *regexp.Regexp
+	// MaxSize is the size in bytes after which the capture moves on to a new file, 0 means no limit.
+	MaxSize int64
+}
+
+// ParseTemplate validates the [path], verbs are only allowed in the file name and not the directory or
+// extension.
+func ParseTemplate(path string, maxSize int64) (Template, error) {
+	dir, name := filepath.Split(path)
+	if name == "" {
+		return Template{}, errors.Errorf("%q is a directory not a file", path)
+	}
+	if strings.Contains(dir, "%") {
+		return Template{}, errors.Errorf("%q: date verbs are only allowed in the file name", path)
+	}
+	ext := filepath.Ext(name)
+	if strings.Contains(ext, "%") {
+		return Template{}, errors.Errorf("%q: date verbs are not allowed in the file extension", path)
+	}
+	if maxSize < 0 {
+		return Template{}, errors.Errorf("%q: the maximum file size cannot be negative", path)
+	}
+	name = strings.TrimSuffix(name, ext)
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(name); i++ {
+		if name[i] != '%' {
+			pattern.WriteString(regexp.QuoteMeta(name[i : i+1]))
+			continue
+		}
+		i++
+		if i == len(name) {
+			return Template{}, errors.Errorf("%q: unfinished verb at the end of the file name", path)
+		}
+		switch name[i] {
+		case 'Y':
+			pattern.WriteString(`\d{4}`)
+		case 'm', 'd', 'H':
+			pattern.WriteString(`\d{2}`)
+		case '%':
+			pattern.WriteString("%")
+		default:
+			return Template{}, errors.Errorf("%q: unknown verb %%%c, only %%Y, %%m, %%d, %%H and %%%% are supported", path, name[i])
+		}
+	}
+	pattern.WriteString(`(\.\d+)?` + regexp.QuoteMeta(ext) + "$")
+	return Template{
+		dir:     dir,
+		name:    name,
+		ext:     ext,
+		matcher: regexp.MustCompile(pattern.String()),
+		MaxSize: maxSize,
+	}, nil
+}
+
+// Path expands the template for the time [at], a [sequence] greater than zero is the nth file of the period.
+func (t Template) Path(at time.Time, sequence int) string {
+	var b strings.Builder
+	for i := 0; i < len(t.name); i++ {
+		if t.name[i] != '%' {
+			b.WriteByte(t.name[i])
+			continue
+		}
+		i++
+		switch t.name[i] {
+		case 'Y':
+			b.WriteString(at.Format("2006"))
+		case 'm':
+			b.WriteString(at.Format("01"))
+		case 'd':
+			b.WriteString(at.Format("02"))
+		case 'H':
+			b.WriteString(at.Format("15"))
+		case '%':
+			b.WriteByte('%')
+		}
+	}
+	if sequence > 0 {
+		b.WriteString("." + strconv.Itoa(sequence))
+	}
+	b.WriteString(t.ext)
+	return filepath.Join(t.dir, b.String())
+}
+
+// Files lists every file which was created by the template, in name order. Files of the same period are in
+// sequence order when the sequence is less than 10.
+func (t Template) Files() ([]string, error) {
+	dir := t.dir
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && t.matcher.MatchString(entry.Name()) {
+			ret = append(ret, filepath.Join(t.dir, entry.Name()))
+		}
+	}
+	return ret, nil
+}
+
+// full reports if the file at [path] has reached [Template.MaxSize].
+func (t Template) full(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && t.MaxSize > 0 && stat.Size() >= t.MaxSize
+}
+
+// RotatingFile is a [CaptureFile] of a [Template], see [RotatingFile.Rotate].
+type RotatingFile struct {
+	*CaptureFile
+
+	template    Template
+	url         string
+	multiTarget bool
+	// period is the path of the current file without a sequence number, when this changes it's time to move
+	// on to a new file.
+	period string
+}
+
+// StreamOrCreateTemplate is [StreamOrCreateFile] for the file of the template at the time [now], the
+// directory of the template is created if needed.
+func StreamOrCreateTemplate(t Template, url string, multiTarget bool, now time.Time) (*data.Stream, *RotatingFile, error) {
+	if t.dir != "" {
+		if err := os.MkdirAll(t.dir, 0o777); err != nil {
+			return nil, nil, err
+		}
+	}
+	r := &RotatingFile{template: t, url: url, multiTarget: multiTarget}
+	s, f, err := r.next(now)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.CaptureFile, r.period = f, t.Path(now, 0)
+	return s, r, nil
+}
+
+// Rotate moves on to the next file of the template if a point at [at] doesn't belong in the current file. The
+// returned data is the existing contents of the new file, which is nil if the file didn't change. Before
+// moving on [last] is written to the current file, waiting for any readers, so that an earlier write skipped
+// with [ErrBusy] isn't lost. If moving on fails the current file is kept.
+func (r *RotatingFile) Rotate(at time.Time, last *data.Data) (*data.Data, error) {
+	if r.template.Path(at, 0) == r.period {
+		if r.template.MaxSize == 0 {
+			return nil, nil
+		}
+		stat, err := r.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if stat.Size() < r.template.MaxSize {
+			return nil, nil
+		}
+	}
+	if err := r.OverwriteWait(last); err != nil {
+		return nil, err
+	}
+	s, f, err := r.next(at)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.ToData()
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "failed to read %q", f.Name())
+	}
+	current := r.CaptureFile
+	r.CaptureFile, r.period = f, r.template.Path(at, 0)
+	return d, current.Close()
+}
+
+// next opens the latest file of the period [at] is in, skipping files which are already full.
+func (r *RotatingFile) next(at time.Time) (*data.Stream, *CaptureFile, error) {
+	sequence := 0
+	for r.template.full(r.template.Path(at, sequence)) {
+		sequence++
+	}
+	return StreamOrCreateFile(r.template.Path(at, sequence), r.url, r.multiTarget)
+}
@@ -0,0 +1,156 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package files_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/files"
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+var at = time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+	template, err := files.ParseTemplate(filepath.Join("captures", "%Y-%m-%d_%H%%.pings"), 0)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(template.Path(at, 0), filepath.Join("captures", "2024-08-02_21%.pings")))
+	assert.Check(t, is.Equal(template.Path(at, 3), filepath.Join("captures", "2024-08-02_21%.3.pings")))
+
+	for _, invalid := range []string{"%Y/capture.pings", "capture.%Y", "capture%", "%q.pings", "captures/"} {
+		_, err := files.ParseTemplate(invalid, 0)
+		assert.Check(t, err != nil, invalid)
+	}
+}
+
+func TestTemplateFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"2024-08-02.pings", "2024-08-02.1.pings", "2024-08-03.pings", "notes.pings", "2024-08-02.csv"} {
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	template, err := files.ParseTemplate(filepath.Join(dir, "%Y-%m-%d.pings"), 0)
+	assert.NilError(t, err)
+	found, err := template.Files()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(found, []string{
+		filepath.Join(dir, "2024-08-02.1.pings"),
+		filepath.Join(dir, "2024-08-02.pings"),
+		filepath.Join(dir, "2024-08-03.pings"),
+	}))
+}
+
+func TestRotateByPeriod(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "captures")
+	template, err := files.ParseTemplate(filepath.Join(dir, "%Y-%m-%d.pings"), 0)
+	assert.NilError(t, err)
+	_, f, err := files.StreamOrCreateTemplate(template, testURL, false, at)
+	assert.NilError(t, err)
+	defer f.Close()
+	assert.Check(t, is.Equal(f.Name(), filepath.Join(dir, "2024-08-02.pings")))
+
+	d, err := f.Rotate(at.Add(time.Hour), data.NewData(testURL))
+	assert.NilError(t, err)
+	assert.Check(t, d == nil)
+
+	d, err = f.Rotate(at.Add(24*time.Hour), data.NewData(testURL))
+	assert.NilError(t, err)
+	assert.Assert(t, d != nil)
+	assert.Check(t, is.Equal(d.URL, testURL))
+	assert.Check(t, is.Equal(f.Name(), filepath.Join(dir, "2024-08-03.pings")))
+
+	// The previous file is no longer captured to
+	_, old, err := files.StreamOrCreateFile(filepath.Join(dir, "2024-08-02.pings"), testURL, false)
+	assert.NilError(t, err)
+	assert.NilError(t, old.Close())
+}
+
+func TestRotateBySize(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	template, err := files.ParseTemplate(filepath.Join(dir, "capture.pings"), 1024)
+	assert.NilError(t, err)
+	_, f, err := files.StreamOrCreateTemplate(template, testURL, false, at)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	d := data.NewData(testURL)
+	for i := range 100 {
+		addPoint(d, at.Add(time.Duration(i)*time.Second))
+	}
+	assert.NilError(t, f.Overwrite(d))
+	rotated, err := f.Rotate(at, d)
+	assert.NilError(t, err)
+	assert.Assert(t, rotated != nil)
+	assert.Check(t, is.Equal(rotated.TotalCount, int64(0)))
+	assert.Check(t, is.Equal(f.Name(), filepath.Join(dir, "capture.1.pings")))
+}
+
+func TestRetain(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	template, err := files.ParseTemplate(filepath.Join(dir, "%Y-%m-%d.pings"), 0)
+	assert.NilError(t, err)
+	old := at.Truncate(time.Hour).Add(-10 * 24 * time.Hour)
+	writeFile := func(name string, points int) string {
+		path := filepath.Join(dir, name)
+		d := data.NewData(testURL)
+		for i := range points {
+			addPoint(d, old.Add(time.Duration(i)*time.Second))
+		}
+		f, err := os.Create(path)
+		assert.NilError(t, err)
+		assert.NilError(t, d.AsCompact(f))
+		assert.NilError(t, f.Close())
+		assert.NilError(t, os.Chtimes(path, old, old))
+		return path
+	}
+
+	downsampled := writeFile("2024-07-23.pings", 600)
+	recent := writeFile("2024-08-01.pings", 600)
+	assert.NilError(t, os.Chtimes(recent, at, at))
+	assert.NilError(t, template.Retain(files.Retention{MaxAge: 7 * 24 * time.Hour, Mode: files.Downsample}, at))
+
+	d, err := files.LoadFile(downsampled)
+	assert.NilError(t, err)
+	// 10 minutes of good points, each minute keeps only the fastest and slowest
+	assert.Check(t, is.Equal(d.TotalCount, int64(20)))
+	stat, err := os.Stat(downsampled)
+	assert.NilError(t, err)
+	assert.Check(t, stat.ModTime().Equal(old))
+	d, err = files.LoadFile(recent)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(d.TotalCount, int64(600)))
+
+	// Files being captured to are never touched
+	inUse := writeFile("2024-07-24.pings", 10)
+	_, f, err := files.StreamOrCreateFile(inUse, testURL, false)
+	assert.NilError(t, err)
+	defer f.Close()
+	assert.NilError(t, template.Retain(files.Retention{MaxAge: 7 * 24 * time.Hour, Mode: files.Delete}, at))
+	_, err = os.Stat(downsampled)
+	assert.Check(t, is.ErrorIs(err, os.ErrNotExist))
+	_, err = os.Stat(inUse)
+	assert.NilError(t, err)
+}
+
+// addPoint adds a good point which gets slower every second.
+func addPoint(d *data.Data, timestamp time.Time) {
+	d.AddPoint(ping.PingResults{
+		Data: ping.PingDataPoint{Timestamp: timestamp, Duration: time.Duration(timestamp.Second()+1) * time.Millisecond},
+		IP:   net.IPv4bcast,
+	})
+}
@@ -0,0 +1,81 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+func annotationStartup() {
+	annotationBar = themes.Highlight(typography.Vertical)
+}
+
+var annotationBar string
+
+// maxAnnotationLabelRows is how many rows from the top of the graph an annotation label may be pushed down to
+// avoid overlapping the label of an earlier annotation.
+const maxAnnotationLabelRows = 3
+
+// addAnnotationMarkers draws a vertical marker for every annotation which lies within a drawn span, in the same
+// way the span bars are drawn (see [addYAxisVerticalSpanIndicator]). The text of each annotation is written as
+// a label at the top of the marker, labels which would overlap an earlier label are staggered onto the next
+// row and if there's no room left they are omitted, leaving only the marker.
+//
+// The markers and labels are written to separate buffers so that the markers can sit behind the data while
+// the labels remain readable above it.
+func addAnnotationMarkers(
+	toWriteMarkers, toWriteLabels *bytes.SafeBuffer,
+	s terminal.Size,
+	annotations []data.Annotation,
+	xAxis drawingXAxis,
+	yAxis drawingYAxis,
+) {
+	if len(annotations) == 0 {
+		return
+	}
+	marker := makeBar(annotationBar, s, true)
+	// The right most column drawn by a label in each row.
+	labelEnds := make([]int, max(min(maxAnnotationLabelRows, s.Height-4), 0))
+	for _, a := range annotations {
+		span := xAxis.spanContaining(a.Timestamp)
+		if span == nil {
+			continue
+		}
+		x := getX(a.Timestamp, span, yAxis, s)
+		toWriteMarkers.WriteString(ansi.CursorPosition(2, x) + marker)
+		row := slices.IndexFunc(labelEnds, func(end int) bool { return end < x })
+		text := []rune(a.Text)
+		remaining := s.Width - (x + 1)
+		if row == -1 || remaining <= 0 || len(text) == 0 {
+			continue
+		}
+		text = text[:min(len(text), remaining)]
+		toWriteLabels.WriteString(ansi.CursorPosition(2+row, x+1) + themes.Highlight(string(text)))
+		labelEnds[row] = x + len(text) + 1
+	}
+	// Reset the cursor back to the start of the axis
+	toWriteMarkers.WriteString(ansi.CursorPosition(s.Height, 1))
+	toWriteLabels.WriteString(ansi.CursorPosition(s.Height, 1))
+}
+
+// spanContaining returns the drawn span which contains the timestamp or nil if this timestamp is not drawn.
+func (x drawingXAxis) spanContaining(t time.Time) *XAxisSpanInfo {
+	for _, span := range x.spans {
+		if span.timeSpan.Contains(t) {
+			return span
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+func anomalyStartUp() {
+	spikeSymbol = themes.Highlight(typography.Star)
+	levelShiftSymbol = themes.Emphasis(typography.UpDownDoubleArrow)
+}
+
+var spikeSymbol, levelShiftSymbol string
+
+// AnomalyKey is the symbol each kind of [data.Anomaly] is drawn with, for use in a legend.
+func AnomalyKey(kind data.AnomalyKind) string {
+	switch kind {
+	case data.Spike:
+		return spikeSymbol
+	case data.LevelShift:
+		return levelShiftSymbol
+	default:
+		panic("exhaustive:enforce")
+	}
+}
+
+// drawAnomalies marks each anomaly over the point it was found at, a level shift is drawn at the new level.
+// Anomalies outside the x-axis spans or y-axis aren't drawn and a level shift is drawn over a spike in the
+// same cell.
+func drawAnomalies(
+	toWriteTo *bytes.SafeBuffer,
+	anomalies []data.Anomaly,
+	xAxis drawingXAxis,
+	yAxis drawingYAxis,
+	s terminal.Size,
+) {
+	drawn := map[coords]data.AnomalyKind{}
+	for _, a := range anomalies {
+		span := xAxis.spanOf(a.Timestamp)
+		if span == nil {
+			continue
+		}
+		x, y := translate(ping.PingDataPoint{Timestamp: a.Timestamp, Duration: a.Duration}, span, yAxis, s)
+		c := coords{x: x, y: y}
+		if kind, found := drawn[c]; (found && kind >= a.Kind) || x < 1 || x > s.Width || y < 1 || y > s.Height {
+			continue
+		}
+		drawn[c] = a.Kind
+		toWriteTo.WriteString(ansi.CursorPosition(y, x) + AnomalyKey(a.Kind))
+	}
+}
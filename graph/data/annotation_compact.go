@@ -0,0 +1,53 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"io"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+func (a *Annotation) AsCompact(w io.Writer) error {
+	ret := make([]byte, a.byteLen())
+	_ = a.write(ret)
+	_, err := w.Write(ret)
+	return err
+}
+
+func (a *Annotation) FromCompact(input []byte) (int, error) {
+	i, err := readID(input, AnnotationID)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Annotation")
+	}
+	i += readTime(input[i:], &a.Timestamp)
+	textLen := 0
+	i += readLen(input[i:], &textLen)
+	i += readString(input[i:], &a.Text, textLen)
+	return i, nil
+}
+
+func (a *Annotation) write(ret []byte) int {
+	i := writeByte(ret, AnnotationID)
+	i += writeTime(ret[i:], a.Timestamp)
+	i += writeStringLen(ret[i:], a.Text)
+	i += writeString(ret[i:], a.Text)
+	return i
+}
+
+func (a *Annotation) byteLen() int {
+	return idLen + timeLen + stringLen(a.Text)
+}
+
+// annotationsLen is the equivalent of [sliceLenCompact] for a slice of annotations which are stored by value.
+func annotationsLen(annotations []Annotation) int {
+	i := int64Len // 1 int64 to encode the length
+	for _, a := range annotations {
+		i += a.byteLen()
+	}
+	return i
+}
@@ -0,0 +1,198 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+// AnomalyOptions tune how sensitive an [AnomalyDetector] is, the limits are in standard deviations of the
+// latency around the baseline.
+type AnomalyOptions struct {
+	// Alpha is the weight of each new point in the baseline (an exponentially weighted moving average),
+	// smaller values make the baseline slower to follow the latency.
+	Alpha float64
+	// Limit is how far from the baseline a point must be to be a [Spike].
+	Limit float64
+	// Warmup is how many good points are needed to know the baseline, before then nothing is anomalous.
+	Warmup int
+	// MinDeviation is the smallest standard deviation as a fraction of the baseline, a very stable
+	// connection would otherwise make every tiny wobble an anomaly.
+	MinDeviation float64
+	// ShiftSlack and ShiftLimit are the parameters of the CUSUM change point detection
+	// (https://en.wikipedia.org/wiki/CUSUM): the drift from the baseline which is ignored per point and the
+	// total drift which is a [LevelShift].
+	ShiftSlack float64
+	ShiftLimit float64
+}
+
+var DefaultAnomalyOptions = AnomalyOptions{
+	Alpha:        0.05,
+	Limit:        4,
+	Warmup:       30,
+	MinDeviation: 0.05,
+	ShiftSlack:   0.5,
+	ShiftLimit:   10,
+}
+
+type AnomalyKind int
+
+const (
+	// Spike is a single point far from the baseline.
+	Spike AnomalyKind = iota
+	// LevelShift is a sustained change in the baseline, e.g. a new route or a congested link.
+	LevelShift
+)
+
+func (k AnomalyKind) String() string {
+	switch k {
+	case Spike:
+		return "Spike"
+	case LevelShift:
+		return "Level Shift"
+	default:
+		panic(fmt.Sprintf("unknown AnomalyKind: %d", int(k)))
+	}
+}
+
+// Anomaly is a point (or the first point of a [LevelShift]) which doesn't fit the baseline.
+type Anomaly struct {
+	Kind      AnomalyKind
+	Index     int64
+	Timestamp time.Time
+	// Duration is the latency of a [Spike] or the new baseline after a [LevelShift].
+	Duration time.Duration
+	// Baseline is the expected latency before the anomaly.
+	Baseline time.Duration
+	// Score is how many standard deviations the anomaly is from the baseline, negative if faster.
+	Score float64
+}
+
+func (a Anomaly) String() string {
+	return fmt.Sprintf("%s | %s | %s (baseline %s, %+.1fσ)",
+		a.Timestamp.Format(time.RFC3339Nano), a.Kind.String(),
+		a.Duration.Round(time.Microsecond), a.Baseline.Round(time.Microsecond), a.Score)
+}
+
+// AnomalyDetector finds the [Anomaly]s of the good points as they're added, dropped points are ignored see
+// [OutageFinder] for those. Spikes are found with EWMA control limits, each point is compared to the moving
+// average and variance of the points before it. Level shifts are found with a two sided CUSUM of how far
+// each point is from the baseline, once a shift is found the baseline restarts from the new level.
+type AnomalyDetector struct {
+	options   AnomalyOptions
+	Anomalies []Anomaly
+
+	count    int
+	mean     float64
+	variance float64
+	up, down cusum
+}
+
+// cusum is one side of the change point detection, it tracks the points since the cumulative drift was last
+// zero which is where the shift started.
+type cusum struct {
+	sum   float64
+	start ping.PingDataPoint
+	index int64
+	// total, squares and count are of the points since the start, the new baseline if this is a shift.
+	total, squares float64
+	count          int
+}
+
+func (c *cusum) add(index int64, p ping.PingDataPoint, drift float64) {
+	if c.sum == 0 && drift > 0 {
+		c.start, c.index, c.total, c.squares, c.count = p, index, 0, 0, 0
+	}
+	c.sum = max(c.sum+drift, 0)
+	if c.sum > 0 {
+		c.total += float64(p.Duration)
+		c.squares += float64(p.Duration) * float64(p.Duration)
+		c.count++
+	}
+}
+
+func NewAnomalyDetector(options AnomalyOptions) *AnomalyDetector {
+	return &AnomalyDetector{options: options, Anomalies: []Anomaly{}}
+}
+
+// AddPoint checks if the point at [index] is anomalous, the points must be added in order.
+func (a *AnomalyDetector) AddPoint(index int64, p ping.PingDataPoint) {
+	if p.Dropped() {
+		return
+	}
+	value := float64(p.Duration)
+	if a.count == 0 {
+		a.mean = value
+	}
+	a.count++
+	deviation := max(math.Sqrt(a.variance), a.options.MinDeviation*a.mean)
+	if a.count <= a.options.Warmup || deviation == 0 {
+		a.update(value)
+		return
+	}
+	score := (value - a.mean) / deviation
+	if math.Abs(score) > a.options.Limit {
+		a.Anomalies = append(a.Anomalies, Anomaly{
+			Kind:      Spike,
+			Index:     index,
+			Timestamp: p.Timestamp,
+			Duration:  p.Duration,
+			Baseline:  time.Duration(a.mean),
+			Score:     score,
+		})
+	}
+	// A short burst of spikes isn't a sustained shift, so each point's drift is capped at half the limit.
+	drift := min(max(score, -a.options.Limit/2), a.options.Limit/2)
+	a.up.add(index, p, drift-a.options.ShiftSlack)
+	a.down.add(index, p, -drift-a.options.ShiftSlack)
+	for _, side := range []*cusum{&a.up, &a.down} {
+		if side.sum <= a.options.ShiftLimit {
+			continue
+		}
+		level := side.total / float64(side.count)
+		a.Anomalies = append(a.Anomalies, Anomaly{
+			Kind:      LevelShift,
+			Index:     side.index,
+			Timestamp: side.start.Timestamp,
+			Duration:  time.Duration(level),
+			Baseline:  time.Duration(a.mean),
+			Score:     (level - a.mean) / deviation,
+		})
+		// Restart at the new level, the latency may also be more or less stable than before so it needs to
+		// warm up again.
+		a.mean = level
+		a.variance = max(side.squares/float64(side.count)-level*level, 0)
+		a.count = side.count
+		a.up, a.down = cusum{}, cusum{}
+		return
+	}
+	// Spikes would inflate the variance and drag the baseline, so they only count as far as the limit.
+	a.update(a.mean + min(max(score, -a.options.Limit), a.options.Limit)*deviation)
+}
+
+// update the exponentially weighted moving average and variance
+// (https://en.wikipedia.org/wiki/Exponential_smoothing, Finch 2009 "Incremental calculation of weighted mean
+// and variance").
+func (a *AnomalyDetector) update(value float64) {
+	diff := value - a.mean
+	increment := a.options.Alpha * diff
+	a.mean += increment
+	a.variance = (1 - a.options.Alpha) * (a.variance + diff*increment)
+}
+
+// Anomalies finds every anomaly of the good points, see [AnomalyDetector].
+func (d *Data) Anomalies(options AnomalyOptions) []Anomaly {
+	a := NewAnomalyDetector(options)
+	for i := range d.TotalCount {
+		a.AddPoint(i, d.Get(i))
+	}
+	return a.Anomalies
+}
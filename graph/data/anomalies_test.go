@@ -0,0 +1,75 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestAnomalies(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)
+	d := data.NewData("www.google.com")
+	for i := range 200 {
+		p := ping.PingDataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), Duration: 10 * time.Millisecond}
+		switch {
+		case i == 60:
+			p.Duration = 100 * time.Millisecond
+		case i == 70:
+			p = ping.PingDataPoint{Timestamp: p.Timestamp, DropReason: ping.Timeout}
+		case i >= 120:
+			p.Duration = 30 * time.Millisecond
+		}
+		// A little noise
+		p.Duration += time.Duration(i%3) * 100 * time.Microsecond
+		d.AddPoint(ping.PingResults{Data: p})
+	}
+
+	anomalies := d.Anomalies(data.DefaultAnomalyOptions)
+	assert.Assert(t, len(anomalies) > 2, anomalies)
+	spike := anomalies[0]
+	assert.Check(t, is.Equal(spike.Kind, data.Spike))
+	assert.Check(t, is.Equal(spike.Index, int64(60)))
+	assert.Check(t, is.Equal(spike.Duration, 100*time.Millisecond))
+	assert.Check(t, spike.Baseline > 9*time.Millisecond && spike.Baseline < 11*time.Millisecond, spike.Baseline)
+	assert.Check(t, spike.Score > 4, spike.Score)
+
+	shifts := 0
+	for _, a := range anomalies[1:] {
+		// Until the shift is found the new level is a series of spikes
+		assert.Check(t, a.Index >= 120 && a.Index < 130, a)
+		if a.Kind != data.LevelShift {
+			continue
+		}
+		shifts++
+		assert.Check(t, is.Equal(a.Index, int64(120)))
+		assert.Check(t, is.Equal(a.Timestamp, start.Add(120*time.Second)))
+		assert.Check(t, a.Duration > 29*time.Millisecond && a.Duration < 31*time.Millisecond, a.Duration)
+		assert.Check(t, a.Score > 0, a.Score)
+	}
+	assert.Check(t, is.Equal(shifts, 1))
+}
+
+func TestAnomaliesWarmup(t *testing.T) {
+	t.Parallel()
+	detector := data.NewAnomalyDetector(data.DefaultAnomalyOptions)
+	start := time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)
+	for i := range data.DefaultAnomalyOptions.Warmup {
+		duration := 10 * time.Millisecond
+		if i%10 == 5 {
+			duration = time.Second
+		}
+		detector.AddPoint(int64(i), ping.PingDataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), Duration: duration})
+	}
+	assert.Check(t, is.Len(detector.Anomalies, 0))
+}
@@ -21,7 +21,7 @@ func (b *Block) AsCompact(w io.Writer) error {
 }
 
 func (b *Block) FromCompact(input []byte) (int, error) {
-	header, data := b.twoPhaseRead()
+	header, data := b.twoPhaseRead(currentDataVersion)
 	rawLen := 0
 	i, err := header(input, &rawLen)
 	if err != nil {
@@ -39,10 +39,7 @@ func (b *Block) write(ret []byte) int {
 
 func (b *Block) twoPhaseWrite() (phasedWrite, phasedWrite) {
 	return func(ret []byte) int {
-			i := writeByte(ret, BlockID)
-			i += writeLen(ret[i:], b.Raw)
-			i += b.Header.write(ret[i:])
-			return i
+			return writeBlockHeader(ret, b.Header, len(b.Raw))
 		}, func(ret []byte) int {
 			i := 0
 			for _, raw := range b.Raw {
@@ -54,7 +51,7 @@ func (b *Block) twoPhaseWrite() (phasedWrite, phasedWrite) {
 
 type blockRead = func(input []byte, rawLen int) int
 
-func (b *Block) twoPhaseRead() (
+func (b *Block) twoPhaseRead(version version) (
 	func(input []byte, rawLen *int) (int, error),
 	blockRead,
 ) {
@@ -62,16 +59,7 @@ func (b *Block) twoPhaseRead() (
 		b.Header = &Header{}
 	}
 	return func(input []byte, blockLen *int) (int, error) {
-			i, err := readID(input, BlockID)
-			if err != nil {
-				return i, errors.Wrap(err, "while reading compact Block")
-			}
-			i += readLen(input[i:], blockLen)
-			n, err := b.Header.FromCompact(input[i:])
-			if err != nil {
-				return i, errors.Wrap(err, "while reading compact Block")
-			}
-			return i + n, err
+			return readBlockHeader(input, b.Header, blockLen, version)
 		},
 		func(input []byte, rawLen int) int {
 			b.Raw = make([]ping.PingDataPoint, rawLen)
@@ -84,11 +72,57 @@ func (b *Block) twoPhaseRead() (
 }
 
 func (b *Block) byteLen() int {
-	return idLen + headerLen + sliceLenFixed(b.Raw, pingDataPointLen)
+	return idLen + b.Header.byteLen() + sliceLenFixed(b.Raw, pingDataPointLen)
+}
+
+// writeBlockHeader writes the first phase of a [Block], which is everything but the points. Used directly
+// by [Data] which doesn't store the points of a block together.
+func writeBlockHeader(ret []byte, header *Header, rawLen int) int {
+	i := writeByte(ret, BlockID)
+	i += writeInt(ret[i:], rawLen)
+	i += header.write(ret[i:])
+	return i
+}
+
+// readBlockHeader is the inverse of [writeBlockHeader] for a block header written by [version].
+func readBlockHeader(input []byte, header *Header, rawLen *int, version version) (int, error) {
+	i, err := readID(input, BlockID)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Block")
+	}
+	i += readLen(input[i:], rawLen)
+	n, err := header.fromCompact(input[i:], version)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Block")
+	}
+	return i + n, err
+}
+
+// blockHeaderLen is the length of a block header written by [version] which stores [buckets] sketch buckets,
+// see [statsLenOf].
+func blockHeaderLen(version version, buckets int) int {
+	return idLen + headerLenOf(version, buckets) + sliceLenFixed([]byte{}, 0)
+}
+
+// blockHeaderStatsOffset is where the [Stats] begin within a block header.
+const blockHeaderStatsOffset = idLen + intLen + idLen
+
+// storedBlockHeaderLen is the block header length written in a file by [version], from [wideSketches] on the
+// block headers vary in length so it's 0.
+func storedBlockHeaderLen(version version) int {
+	if version > wideSketches {
+		return 0
+	}
+	return blockHeaderLen(version, sketchBuckets)
 }
 
-func blockHeaderLen() int {
-	return idLen + headerLen + sliceLenFixed([]byte{}, 0)
+// blockHeadersLen is the length of every block header including the count.
+func blockHeadersLen(headers []*Header) int {
+	i := int64Len
+	for _, header := range headers {
+		i += idLen + intLen + header.byteLen()
+	}
+	return i
 }
 
 func writePingDataPoint(b []byte, p ping.PingDataPoint) int {
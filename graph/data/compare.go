@@ -0,0 +1,175 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// Comparison is the difference between two captures, e.g. before and after changing a router or ISP.
+type Comparison struct {
+	Before, After                 *Stats
+	BeforeOutages, AfterOutages   *OutageReport
+	MannWhitney                   MannWhitney
+	KolmogorovSmirnov             KolmogorovSmirnov
+	BeforeSamples, AfterSamples   int
+	BeforeURL, AfterURL           string
+	BeforeTimeSpan, AfterTimeSpan *TimeSpan
+}
+
+// MannWhitney is the result of a two-sided Mann-Whitney U test, which tests whether the latency of one capture
+// tends to be larger than the other.
+type MannWhitney struct {
+	// U is the statistic of the first sample.
+	U float64
+	// Z is U normalised with the tie corrected normal approximation, positive when the first sample tends to
+	// be slower.
+	Z float64
+	P float64
+	// Effect is the probability that a random point of the first sample is slower than a random point of the
+	// second (counting ties as half), 0.5 is no difference.
+	Effect float64
+}
+
+// KolmogorovSmirnov is the result of a two-sided two sample Kolmogorov-Smirnov test, which tests whether the
+// latency of the two captures have a different distribution, of any shape.
+type KolmogorovSmirnov struct {
+	// D is the largest difference between the two cumulative distributions.
+	D float64
+	P float64
+}
+
+// Compare the latency, loss and outages of two captures, the significance tests are on the latency of every
+// good point.
+func Compare(before, after *Data, options OutageOptions) *Comparison {
+	b := goodDurations(before)
+	a := goodDurations(after)
+	return &Comparison{
+		Before:            before.Header.Stats,
+		After:             after.Header.Stats,
+		BeforeOutages:     before.Outages(options),
+		AfterOutages:      after.Outages(options),
+		MannWhitney:       MannWhitneyTest(b, a),
+		KolmogorovSmirnov: KolmogorovSmirnovTest(b, a),
+		BeforeSamples:     len(b),
+		AfterSamples:      len(a),
+		BeforeURL:         before.URL,
+		AfterURL:          after.URL,
+		BeforeTimeSpan:    before.Header.TimeSpan,
+		AfterTimeSpan:     after.Header.TimeSpan,
+	}
+}
+
+func goodDurations(d *Data) []time.Duration {
+	ret := make([]time.Duration, 0, d.Header.Stats.GoodCount)
+	for i := range d.TotalCount {
+		if p := d.Get(i); p.Good() {
+			ret = append(ret, p.Duration)
+		}
+	}
+	return ret
+}
+
+type rankedSample struct {
+	value time.Duration
+	first bool
+}
+
+// MannWhitneyTest of the samples [x] and [y], the order of the samples doesn't matter. If either is empty
+// there's no evidence of a difference so P is 1.
+func MannWhitneyTest(x, y []time.Duration) MannWhitney {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if len(x) == 0 || len(y) == 0 {
+		return MannWhitney{P: 1, Effect: 0.5}
+	}
+	all := make([]rankedSample, 0, len(x)+len(y))
+	for _, v := range x {
+		all = append(all, rankedSample{value: v, first: true})
+	}
+	for _, v := range y {
+		all = append(all, rankedSample{value: v})
+	}
+	slices.SortFunc(all, func(a, b rankedSample) int { return cmp.Compare(a.value, b.value) })
+
+	// Tied values share the average of their ranks, which also needs a correction to the variance.
+	rankSum, tieCorrection := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for _, s := range all[i:j] {
+			if s.first {
+				rankSum += rank
+			}
+		}
+		ties := float64(j - i)
+		tieCorrection += ties*ties*ties - ties
+		i = j
+	}
+	n := n1 + n2
+	u := rankSum - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	ret := MannWhitney{U: u, P: 1, Effect: u / (n1 * n2)}
+	if variance <= 0 {
+		// Every value is the same
+		return ret
+	}
+	// With a continuity correction towards the mean
+	difference := u - mean
+	difference -= math.Copysign(min(0.5, math.Abs(difference)), difference)
+	ret.Z = difference / math.Sqrt(variance)
+	ret.P = min(1, math.Erfc(math.Abs(ret.Z)/math.Sqrt2))
+	return ret
+}
+
+// KolmogorovSmirnovTest of the samples [x] and [y] using the asymptotic distribution, which is accurate enough
+// for the number of points in a capture. If either is empty P is 1.
+func KolmogorovSmirnovTest(x, y []time.Duration) KolmogorovSmirnov {
+	if len(x) == 0 || len(y) == 0 {
+		return KolmogorovSmirnov{P: 1}
+	}
+	x = slices.Sorted(slices.Values(x))
+	y = slices.Sorted(slices.Values(y))
+	n1, n2 := float64(len(x)), float64(len(y))
+	d := 0.0
+	for i, j := 0, 0; i < len(x) && j < len(y); {
+		v := min(x[i], y[j])
+		for i < len(x) && x[i] == v {
+			i++
+		}
+		for j < len(y) && y[j] == v {
+			j++
+		}
+		d = max(d, math.Abs(float64(i)/n1-float64(j)/n2))
+	}
+	en := math.Sqrt(n1 * n2 / (n1 + n2))
+	return KolmogorovSmirnov{D: d, P: kolmogorovQ((en + 0.12 + 0.11/en) * d)}
+}
+
+// kolmogorovQ is the survival function of the Kolmogorov distribution, see Numerical Recipes 14.3.
+func kolmogorovQ(lambda float64) float64 {
+	if lambda < 0.2 {
+		// The series converges too slowly and is 1 to many decimal places
+		return 1
+	}
+	sum, sign := 0.0, 1.0
+	for j := 1.0; j <= 100; j++ {
+		term := sign * 2 * math.Exp(-2*j*j*lambda*lambda)
+		sum += term
+		if math.Abs(term) < 1e-10 {
+			break
+		}
+		sign = -sign
+	}
+	return min(max(sum, 0), 1)
+}
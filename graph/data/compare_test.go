@@ -0,0 +1,86 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func ms(values ...int) []time.Duration {
+	ret := make([]time.Duration, len(values))
+	for i, v := range values {
+		ret[i] = time.Duration(v) * time.Millisecond
+	}
+	return ret
+}
+
+func TestMannWhitney(t *testing.T) {
+	t.Parallel()
+	// The normal approximation with a continuity correction, as scipy.stats.mannwhitneyu(method="asymptotic")
+	result := data.MannWhitneyTest(ms(1, 2, 3), ms(4, 5, 6))
+	assert.Check(t, is.Equal(result.U, 0.0))
+	assert.Check(t, is.Equal(result.Effect, 0.0))
+	th.AssertFloatEqual(t, -1.7457, result.Z, 4)
+	th.AssertFloatEqual(t, 0.080856, result.P, 5)
+
+	// With ties
+	result = data.MannWhitneyTest(ms(1, 2, 2, 3, 7), ms(2, 3, 4, 4, 5, 9))
+	assert.Check(t, is.Equal(result.U, 7.5))
+	th.AssertFloatEqual(t, 0.19504, result.P, 5)
+
+	result = data.MannWhitneyTest(ms(4, 5, 6), ms(4, 5, 6))
+	assert.Check(t, is.Equal(result.Effect, 0.5))
+	assert.Check(t, is.Equal(result.P, 1.0))
+
+	result = data.MannWhitneyTest(ms(4, 4), ms(4, 4))
+	assert.Check(t, is.Equal(result.P, 1.0))
+	result = data.MannWhitneyTest(nil, ms(4))
+	assert.Check(t, is.Equal(result.P, 1.0))
+}
+
+func TestKolmogorovSmirnov(t *testing.T) {
+	t.Parallel()
+	result := data.KolmogorovSmirnovTest(ms(1, 2, 3), ms(4, 5, 6))
+	assert.Check(t, is.Equal(result.D, 1.0))
+	th.AssertFloatEqual(t, 0.032622, result.P, 5)
+
+	result = data.KolmogorovSmirnovTest(ms(1, 2, 2, 3, 7), ms(2, 3, 4, 4, 5, 9))
+	th.AssertFloatEqual(t, 0.466667, result.D, 5)
+
+	result = data.KolmogorovSmirnovTest(ms(4, 5, 6), ms(6, 5, 4))
+	assert.Check(t, is.Equal(result.D, 0.0))
+	assert.Check(t, is.Equal(result.P, 1.0))
+	result = data.KolmogorovSmirnovTest(ms(4), nil)
+	assert.Check(t, is.Equal(result.P, 1.0))
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	before := readTestFile(t, filepath.Join("testdata", "input", "medium-minute-gaps.pings"))
+	after := readTestFile(t, filepath.Join("testdata", "input", "verybad-london.pings"))
+
+	same := data.Compare(before, before, data.DefaultOutageOptions)
+	assert.Check(t, is.Equal(same.MannWhitney.P, 1.0))
+	assert.Check(t, is.Equal(same.KolmogorovSmirnov.D, 0.0))
+
+	c := data.Compare(before, after, data.DefaultOutageOptions)
+	assert.Check(t, is.Equal(c.BeforeSamples, int(before.Header.Stats.GoodCount)))
+	assert.Check(t, is.Equal(c.AfterSamples, int(after.Header.Stats.GoodCount)))
+	assert.Check(t, is.Len(c.BeforeOutages.Outages, 0))
+	assert.Check(t, is.Len(c.AfterOutages.Outages, 2))
+	assert.Check(t, c.MannWhitney.P < 0.001, c.MannWhitney.P)
+	assert.Check(t, c.KolmogorovSmirnov.P < 0.001, c.KolmogorovSmirnov.P)
+	// The hotel wifi in london is slower
+	assert.Check(t, c.MannWhitney.Z < 0, c.MannWhitney.Z)
+}
@@ -20,14 +20,19 @@ import (
 )
 
 type Data struct {
-	Header      *Header
-	Network     *Network
-	Runs        *Runs
-	URL         string
-	InsertOrder []DataIndexes
-	Blocks      []*Block
-	TotalCount  int64
-	PingsMeta   version
+	Header  *Header
+	Network *Network
+	Runs    *Runs
+	URL     string
+	// BlockHeaders are the [Header] of the points for each IP, see [Network.BlockIndexes] for which IP a block
+	// belongs to.
+	BlockHeaders []*Header
+	Annotations  []Annotation
+	// Rollups are the points aggregated into buckets of time, see [Rollups].
+	Rollups    *Rollups
+	points     points
+	TotalCount int64
+	PingsMeta  version
 }
 
 type DataIndexes struct {
@@ -40,50 +45,54 @@ func NewData(URL string) *Data {
 
 func newVersionedData(URL string, v version) *Data {
 	d := &Data{
-		URL:         URL,
-		Header:      &Header{Stats: &Stats{}, TimeSpan: &TimeSpan{Begin: time.UnixMilli(0), End: time.UnixMilli(0), Duration: 0}},
-		Network:     &Network{IPs: []net.IP{}, BlockIndexes: []int{}, curBlockIndex: 0},
-		InsertOrder: []DataIndexes{},
-		Blocks:      []*Block{},
-		TotalCount:  0,
-		Runs:        &Runs{GoodPackets: &Run{}, DroppedPackets: &Run{}},
-		PingsMeta:   v,
+		URL:          URL,
+		Header:       &Header{Stats: &Stats{}, TimeSpan: &TimeSpan{Begin: time.UnixMilli(0), End: time.UnixMilli(0), Duration: 0}},
+		Network:      &Network{IPs: []net.IP{}, BlockIndexes: []int{}, curBlockIndex: 0},
+		BlockHeaders: []*Header{},
+		TotalCount:   0,
+		Runs:         &Runs{GoodPackets: &Run{}, DroppedPackets: &Run{}},
+		Annotations:  []Annotation{},
+		Rollups:      NewRollups(),
+		PingsMeta:    v,
 	}
 	return d
 }
 
 func (d *Data) AddPoint(p ping.PingResults) {
 	blockIndex := d.Network.AddPoint(p.IP)
-	if blockIndex >= len(d.Blocks) {
-		d.addBlock()
+	if blockIndex >= len(d.BlockHeaders) {
+		d.BlockHeaders = append(d.BlockHeaders, &Header{Stats: &Stats{}, TimeSpan: &TimeSpan{}})
 	}
-	curBlock := d.getBlock(blockIndex)
-	rawIndex := curBlock.AddPoint(p.Data)
+	d.BlockHeaders[blockIndex].AddPoint(p.Data)
+	d.points.add(p.Data, blockIndex)
+	d.Rollups.AddPoint(p.Data)
 	d.Header.AddPoint(p.Data)
 	d.Runs.AddPoint(d.TotalCount, p.Data)
 	d.TotalCount++
-	d.InsertOrder = append(d.InsertOrder, DataIndexes{
-		BlockIndex: blockIndex,
-		RawIndex:   rawIndex,
-	})
 }
 
+// AddAnnotation stores the annotation in timestamp order, annotations are independent of the data points so
+// may be added at any time.
+func (d *Data) AddAnnotation(a Annotation) {
+	i, _ := slices.BinarySearchFunc(d.Annotations, a, func(a, b Annotation) int { return a.Timestamp.Compare(b.Timestamp) })
+	d.Annotations = slices.Insert(d.Annotations, i, a)
+}
+
+// Get returns the point at the [index] in insert order. The timestamp is in the location of the first point
+// added.
 func (d *Data) Get(index int64) ping.PingDataPoint {
-	this := d.InsertOrder[index]
-	return d.Blocks[this.BlockIndex].Raw[this.RawIndex]
+	return d.points.get(index)
 }
 func (d *Data) GetFull(index int64) ping.PingResults {
-	this := d.InsertOrder[index]
-	dataPoint := d.Blocks[this.BlockIndex].Raw[this.RawIndex]
-	i := slices.Index(d.Network.BlockIndexes, this.BlockIndex)
+	i := slices.Index(d.Network.BlockIndexes, d.points.block(index))
 	ip := d.Network.IPs[i]
 	return ping.PingResults{
-		Data: dataPoint,
+		Data: d.points.get(index),
 		IP:   ip,
 	}
 }
 func (d *Data) End(index int64) bool {
-	return int(index) == len(d.InsertOrder)
+	return int(index) == d.points.len()
 }
 func (d *Data) IsLast(index int64) bool {
 	return d.End(index - 1)
@@ -112,18 +121,77 @@ func (d *Data) In(tz *time.Location) *Data {
 		p.Data.Timestamp = p.Data.Timestamp.In(tz)
 		ret.AddPoint(p)
 	}
+	for _, a := range d.Annotations {
+		a.Timestamp = a.Timestamp.In(tz)
+		ret.AddAnnotation(a)
+	}
 	return ret
 }
 
-func (d *Data) addBlock() {
-	d.Blocks = append(d.Blocks, &Block{
-		Header: &Header{Stats: &Stats{}, TimeSpan: &TimeSpan{}},
-		Raw:    make([]ping.PingDataPoint, 0, 1024),
-	})
+// Filter creates a new [Data] containing only the points and annotations for which the keep functions return
+// true. Points are re-added in insert order so that the headers, network and runs are all recomputed for the
+// new subset of data.
+func (d *Data) Filter(keepPoint func(ping.PingResults) bool, keepAnnotation func(Annotation) bool) *Data {
+	ret := NewData(d.URL)
+	for i := range d.TotalCount {
+		p := d.GetFull(i)
+		if keepPoint(p) {
+			ret.AddPoint(p)
+		}
+	}
+	for _, a := range d.Annotations {
+		if keepAnnotation(a) {
+			ret.AddAnnotation(a)
+		}
+	}
+	return ret
 }
 
-func (d *Data) getBlock(blockIndex int) *Block {
-	return d.Blocks[blockIndex]
+// Downsample creates a new [Data] which only keeps the points that best represent each [resolution] wide
+// bucket of time, these are the same points as [Rollup.Extremes]. All the annotations are kept. As only some
+// points are kept the statistics of the result will differ.
+func (d *Data) Downsample(resolution time.Duration) *Data {
+	const fastest, slowest, firstDropped, lastDropped = 0, 1, 2, 3
+	buckets := map[time.Time]*[4]int64{}
+	for i := range d.TotalCount {
+		p := d.Get(i)
+		begin := p.Timestamp.Truncate(resolution).UTC()
+		kept, ok := buckets[begin]
+		if !ok {
+			kept = &[4]int64{-1, -1, -1, -1}
+			buckets[begin] = kept
+		}
+		replace := func(which int, better func(current ping.PingDataPoint) bool) {
+			if kept[which] < 0 || better(d.Get(kept[which])) {
+				kept[which] = i
+			}
+		}
+		if p.Dropped() {
+			replace(firstDropped, func(current ping.PingDataPoint) bool { return p.Timestamp.Before(current.Timestamp) })
+			replace(lastDropped, func(current ping.PingDataPoint) bool { return p.Timestamp.After(current.Timestamp) })
+		} else {
+			replace(fastest, func(current ping.PingDataPoint) bool { return p.Duration < current.Duration })
+			replace(slowest, func(current ping.PingDataPoint) bool { return p.Duration > current.Duration })
+		}
+	}
+	keep := make([]bool, d.TotalCount)
+	for _, kept := range buckets {
+		for _, i := range kept {
+			if i >= 0 {
+				keep[i] = true
+			}
+		}
+	}
+	ret := NewData(d.URL)
+	for i := range d.TotalCount {
+		if keep[i] {
+			ret.AddPoint(d.GetFull(i))
+		}
+	}
+	for _, a := range d.Annotations {
+		ret.AddAnnotation(a)
+	}
+	return ret
 }
 
 // TimeSpan is the time properties of a given thing
@@ -362,6 +430,19 @@ func (r *Run) withTimestamp(str string, getTimestamp func(int64) time.Time) stri
 	return fmt.Sprintf("%s %d %s", str, r.Longest, span.String())
 }
 
+// Annotation is a short note provided by the user which is attached to a point in time during a capture, e.g.
+// "router rebooted".
+type Annotation struct {
+	Timestamp time.Time
+	Text      string
+}
+
+func (a Annotation) String() string {
+	return fmt.Sprintf("%s %q", a.Timestamp.Format(time.RFC3339Nano), a.Text)
+}
+
+// Block is the serialised form of the points for a single IP, in memory [Data] stores the points as columns
+// and only keeps the [Header] of each block.
 type Block struct {
 	Header *Header
 	Raw    []ping.PingDataPoint
@@ -382,12 +463,16 @@ type Stats struct {
 	StandardDeviation float64
 	PacketsDropped    uint64
 	sumOfSquares      float64
+	// quantiles is a sketch of every good point, see [Stats.Quantile].
+	quantiles sketch
+	jitter    jitter
 }
 
 // Merge combines two [Stats] pointers into a new [Stats] pointer containing all the data from both
 // inputs. This means the total count will be the sum of the two input counts. If either is nil then
 // a new [Stats] is not created and the non-nil pointer is returned. If both are nil then this
-// panics.
+// panics. The points of [other] are assumed to come after those of [s], which
+// only matters for the jitter.
 func (s *Stats) Merge(other *Stats) *Stats {
 	if s == nil {
 		return other
@@ -410,9 +495,45 @@ func (s *Stats) Merge(other *Stats) *Stats {
 		float64(s.GoodCount)*deltaSquare + // The sum of squares of set [s] is compared to the [ret] mean
 		float64(other.GoodCount)*otherDeltaSquare // The sum of squares of set [other] is compared to the [ret] mean
 	ret.computeVariance()
+	ret.quantiles = s.quantiles.merge(&other.quantiles)
+	ret.jitter = s.jitter.merge(&other.jitter)
 	return ret
 }
 
+// Quantile is the duration which [q] (between 0 and 1) of the good points are faster than, e.g. 0.99 is the
+// 99th percentile. Any quantile is within [SketchAccuracy] of the true value, the 0th and 1st are exactly
+// [Stats.Min] and [Stats.Max]. Returns 0 if there are no quantiles, see [Stats.HasQuantiles].
+func (s *Stats) Quantile(q float64) time.Duration {
+	switch {
+	case !s.HasQuantiles():
+		return 0
+	case q <= 0:
+		return s.Min
+	case q >= 1:
+		return s.Max
+	}
+	return min(max(s.quantiles.quantile(q), s.Min), s.Max)
+}
+
+// HasQuantiles is false if there are no good points.
+func (s *Stats) HasQuantiles() bool {
+	return s.quantiles.count > 0
+}
+
+// Jitter is the RFC 3550 smoothed jitter (https://www.rfc-editor.org/rfc/rfc3550#appendix-A.8) of the good
+// points in the order they were added, it's weighted towards the most recent differences between
+// consecutive durations. Returns 0 with less than two good points.
+func (s *Stats) Jitter() time.Duration {
+	return time.Duration(s.jitter.smoothed)
+}
+
+// MeanJitter is the mean absolute difference between consecutive durations of the good points (the mean
+// IPDV), unlike [Stats.Jitter] every difference has the same weight. Returns 0 with less than two good
+// points.
+func (s *Stats) MeanJitter() time.Duration {
+	return time.Duration(s.jitter.mean())
+}
+
 func (s *Stats) PacketLoss() float64 {
 	return float64(s.PacketsDropped) / float64(s.GoodCount+s.PacketsDropped)
 }
@@ -444,6 +565,8 @@ func (s *Stats) AddPoint(input time.Duration) {
 
 	s.Mean = newMean
 	s.computeVariance()
+	s.quantiles.add(input)
+	s.jitter.add(input)
 }
 
 func (s *Stats) AddPoints(values []time.Duration) {
@@ -460,6 +583,10 @@ func stringFloatTime(f float64) string {
 func (s *Stats) PickString(remainingSpace int) string {
 	// heuristic is good enough for now
 	switch {
+	case remainingSpace > 175 && s.HasQuantiles() && s.GoodCount > 1:
+		return s.longString() + s.quantilesString() + s.jitterString()
+	case remainingSpace > 140 && s.HasQuantiles():
+		return s.longString() + s.quantilesString()
 	case remainingSpace > 100:
 		return s.longString()
 	case remainingSpace > 80 && s.PacketsDropped > 0:
@@ -544,13 +671,42 @@ func (s *Stats) longString() string {
 	return b.String()
 }
 
+func (s *Stats) quantilesString() string {
+	var b strings.Builder
+	for _, q := range []struct {
+		name string
+		q    float64
+	}{{"p50", 0.5}, {"p95", 0.95}, {"p99", 0.99}} {
+		fmt.Fprintf(&b, " | %s %s", q.name, stringFloatTime(numeric.RoundToNearestSigFig(float64(s.Quantile(q.q)), 4)))
+	}
+	return b.String()
+}
+
+func (s *Stats) jitterString() string {
+	return fmt.Sprintf(" | Jitter %s | Mean Jitter %s",
+		stringFloatTime(numeric.RoundToNearestSigFig(float64(s.Jitter()), 4)),
+		stringFloatTime(numeric.RoundToNearestSigFig(float64(s.MeanJitter()), 4)))
+}
+
 type version byte
 
 const (
 	// ping files which come from commit 8368ecdbc7c3a7ea5b0e773990a724a3efae152d or earlier (since serialisation was added)
 	noRuns version = iota + 1
-	// ping files which come from commit 54a4f5f1bebd4695624262836248f80b9904cadd to current (as of writing)
+	// ping files which come from commit 54a4f5f1bebd4695624262836248f80b9904cadd up until runs were indexed
 	runsWithNoIndex
+	// ping files which have indexed runs but were written before annotations were added
+	noAnnotations
+	// ping files which have annotations but were written before rollups were stored
+	noRollups
+	// ping files which have rollups but were written before stats stored quantiles
+	noQuantiles
+	// ping files which have quantiles but were written before stats stored jitter
+	noJitter
+	// ping files which have jitter but were written with every level of the rollups, including the finest
+	secondRollups
+	// ping files which were written with every bucket of the quantile sketches, including the empty ones
+	wideSketches
 	// reserved as the moving end-cap. Keep this name when you add a new version, ensure [Data.write] produces
 	// the correct output for this version and that a new readVersion[N-1] is added.
 	currentDataVersion
@@ -570,6 +726,21 @@ func (d *Data) migrate() {
 				p := d.Get(i)
 				d.Runs.AddPoint(i, p)
 			}
+		case noAnnotations:
+			if d.Annotations == nil {
+				d.Annotations = []Annotation{}
+			}
+		case noRollups:
+			d.Rollups = NewRollups()
+			for i := range d.TotalCount {
+				d.Rollups.AddPoint(d.Get(i))
+			}
+		case noQuantiles:
+			recomputeStats(d, func(s *Stats) *sketch { return &s.quantiles })
+		case noJitter:
+			recomputeStats(d, func(s *Stats) *jitter { return &s.jitter })
+		case secondRollups, wideSketches:
+			// Only the layout changed, so nothing needs rebuilding.
 		case currentDataVersion:
 			return
 		}
@@ -577,3 +748,22 @@ func (d *Data) migrate() {
 		startingVersion++
 	}
 }
+
+// recomputeStats resets part of the [Stats] of the header and every block and then adds every good point to
+// it again, for migrating files which didn't store that part.
+func recomputeStats[T any, P interface {
+	*T
+	add(time.Duration)
+}](d *Data, part func(*Stats) P) {
+	*part(d.Header.Stats) = *new(T)
+	for _, header := range d.BlockHeaders {
+		*part(header.Stats) = *new(T)
+	}
+	for i := range d.TotalCount {
+		p := d.Get(i)
+		if !p.Dropped() {
+			part(d.Header.Stats).add(p.Duration)
+			part(d.BlockHeaders[d.points.block(i)].Stats).add(p.Duration)
+		}
+	}
+}
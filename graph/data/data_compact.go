@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -26,34 +26,47 @@ func (d *Data) AsCompact(w io.Writer) error {
 
 func (d *Data) write(ret []byte) int {
 	networkHeader, networkData := d.Network.twoPhaseWrite()
+	blockSizes := d.points.blockSizes(len(d.BlockHeaders))
 	i := writeByte(ret, DataID)
 	// We explicitly do not preserve the version in this data, we have migrated and the write code only ever
 	// supports the latest version.
 	i += writeByte(ret[i:], currentDataVersion)
-	i += writeLen(ret[i:], d.InsertOrder)
+	i += writeInt(ret[i:], d.points.len())
 	i += writeInt64(ret[i:], d.TotalCount)
 	i += networkHeader(ret[i:])
-	i += writeInt(ret[i:], blockHeaderLen())
-	i += writeLen(ret[i:], d.Blocks)
-	deferredData := make([]phasedWrite, len(d.Blocks))
-	for blockIndex, block := range d.Blocks {
-		header, data := block.twoPhaseWrite()
-		deferredData[blockIndex] = data
-		i += header(ret[i:])
+	i += writeInt(ret[i:], storedBlockHeaderLen(currentDataVersion))
+	i += writeLen(ret[i:], d.BlockHeaders)
+	for blockIndex, header := range d.BlockHeaders {
+		i += writeBlockHeader(ret[i:], header, blockSizes[blockIndex])
 	}
 	i += writeStringLen(ret[i:], d.URL)
 	i += d.Runs.write(ret[i:])
 	i += d.Header.write(ret[i:])
 
-	// Phase 2 the variable length data
-	for _, insert := range d.InsertOrder {
-		i += insert.write(ret[i:])
-	}
+	// Phase 2 the variable length data, the points are stored grouped by block while in memory they are in
+	// insert order so first work out where each block starts.
+	insertOrder := ret[i:]
+	i += d.points.len() * dataIndexesLen
 	i += networkData(ret[i:])
-	for _, blockData := range deferredData {
-		i += blockData(ret[i:])
+	blocks := make([][]byte, len(d.BlockHeaders))
+	for blockIndex, size := range blockSizes {
+		blocks[blockIndex] = ret[i:]
+		i += size * pingDataPointLen
+	}
+	rawIndexes := make([]int, len(d.BlockHeaders))
+	for index := range int64(d.points.len()) {
+		blockIndex := d.points.block(index)
+		insert := DataIndexes{BlockIndex: blockIndex, RawIndex: rawIndexes[blockIndex]}
+		insertOrder = insertOrder[insert.write(insertOrder):]
+		blocks[blockIndex] = blocks[blockIndex][writePingDataPoint(blocks[blockIndex], d.points.get(index)):]
+		rawIndexes[blockIndex]++
 	}
 	i += writeString(ret[i:], d.URL)
+	i += writeLen(ret[i:], d.Annotations)
+	for _, annotation := range d.Annotations {
+		i += annotation.write(ret[i:])
+	}
+	i += d.Rollups.write(ret[i:])
 	return i
 }
 
@@ -87,7 +100,7 @@ func (d *Data) FromCompact(input []byte) (int, error) {
 		i += n
 		d.migrate()
 		return i, nil
-	case runsWithNoIndex, currentDataVersion:
+	case runsWithNoIndex, noAnnotations:
 		n, err := d.readVersion2(i, input)
 		if err != nil {
 			return i, errors.Wrap(err, "while reading compact Data")
@@ -95,6 +108,22 @@ func (d *Data) FromCompact(input []byte) (int, error) {
 		i += n
 		d.migrate()
 		return i, nil
+	case noRollups:
+		n, err := d.readVersion4(i, input)
+		if err != nil {
+			return i, errors.Wrap(err, "while reading compact Data")
+		}
+		i += n
+		d.migrate()
+		return i, nil
+	case noQuantiles, noJitter, secondRollups, wideSketches, currentDataVersion:
+		n, err := d.readVersion5(i, input)
+		if err != nil {
+			return i, errors.Wrap(err, "while reading compact Data")
+		}
+		i += n
+		d.migrate()
+		return i, nil
 	default:
 		panic("exhaustive:enforce")
 	}
@@ -109,7 +138,9 @@ func (d *Data) byteLen() int {
 		d.Network.byteLen() +
 		intLen + // blockHeaderLen
 		// Begin Variable sized items:
-		sliceLenCompact(d.Blocks) +
-		sliceLenFixed(d.InsertOrder, dataIndexesLen) +
-		stringLen(d.URL)
+		blockHeadersLen(d.BlockHeaders) + d.points.len()*pingDataPointLen + // Blocks
+		int64Len + d.points.len()*dataIndexesLen + // InsertOrder
+		stringLen(d.URL) +
+		annotationsLen(d.Annotations) +
+		d.Rollups.byteLen()
 }
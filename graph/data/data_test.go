@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/rand/v2"
 	"net"
+	"runtime"
 	"slices"
 	"strconv"
 	"testing"
@@ -243,7 +244,7 @@ func TestData(t *testing.T) {
 			},
 			ExpectedTotalCount: 1,
 			//nolint:lll
-			ExpectedSummary: "www.google.com: PingsMeta#3 [224.0.0.2] | 01 Jan 2000 00:00:00 -> 00:00:00 (0s) | Average μ 5ms | SD σ 0s | Dropped 0 | Good Packets 1 | Packet Count 1 | Longest Streak 1",
+			ExpectedSummary: "www.google.com: PingsMeta#9 [224.0.0.2] | 01 Jan 2000 00:00:00 -> 00:00:00 (0s) | Average μ 5ms | SD σ 0s | Dropped 0 | Good Packets 1 | Packet Count 1 | p50 5ms | p95 5ms | p99 5ms | Longest Streak 1",
 		},
 		{
 			Values: sameIP([]ping.PingDataPoint{
@@ -271,7 +272,7 @@ func TestData(t *testing.T) {
 			}},
 			ExpectedTotalCount: 5,
 			//nolint:lll
-			ExpectedSummary: "www.google.com: PingsMeta#3 [224.0.0.2] | 01 Jan 2000 00:00:00 -> 00:04:00 (4m0s) | Average μ 5.2ms | SD σ 1.483239ms | Dropped 0 | Good Packets 5 | Packet Count 5 | Longest Streak 5 01 Jan 2000 00:00:00 -> 00:04:00 (4m0s)",
+			ExpectedSummary: "www.google.com: PingsMeta#9 [224.0.0.2] | 01 Jan 2000 00:00:00 -> 00:04:00 (4m0s) | Average μ 5.2ms | SD σ 1.483239ms | Dropped 0 | Good Packets 5 | Packet Count 5 | p50 4.985ms | p95 6.088ms | p99 6.088ms | Jitter 473.6µs | Mean Jitter 2ms | Longest Streak 5 01 Jan 2000 00:00:00 -> 00:04:00 (4m0s)",
 		},
 		{
 			Values: slices.Concat(
@@ -339,7 +340,7 @@ func TestData(t *testing.T) {
 			}},
 			ExpectedTotalCount: 10,
 			//nolint:lll
-			ExpectedSummary: "www.google.com: PingsMeta#3 [224.0.0.2,255.255.255.255] | 01 Jan 2000 00:00:00 -> 00:00:00 (9ns) | Average μ 5ns | SD σ 1ns | Dropped 0 | Good Packets 10 | Packet Count 10 | Longest Streak 10 01 Jan 2000 00:00:00 -> 00:00:00 (9ns)",
+			ExpectedSummary: "www.google.com: PingsMeta#9 [224.0.0.2,255.255.255.255] | 01 Jan 2000 00:00:00 -> 00:00:00 (9ns) | Average μ 5ns | SD σ 1ns | Dropped 0 | Good Packets 10 | Packet Count 10 | p50 5ns | p95 6ns | p99 6ns | Jitter 0s | Mean Jitter 2ns | Longest Streak 10 01 Jan 2000 00:00:00 -> 00:00:00 (9ns)",
 		},
 		{
 			Values: sameIP([]ping.PingDataPoint{
@@ -383,7 +384,7 @@ func TestData(t *testing.T) {
 				Current:         0,
 			}},
 			//nolint:lll
-			ExpectedSummary: "www.google.com: PingsMeta#3 [224.0.0.2] | 01 Jan 2000 00:00:00 -> 00:40:00 (40m0s) | Average μ 15.25ms | SD σ 1.707825ms | PacketLoss 20.0% | Dropped 1 | Good Packets 4 | Packet Count 5 | Longest Streak 2 01 Jan 2000 00:00:00 -> 00:10:00 (10m0s) | Longest Drop Streak 1",
+			ExpectedSummary: "www.google.com: PingsMeta#9 [224.0.0.2] | 01 Jan 2000 00:00:00 -> 00:40:00 (40m0s) | Average μ 15.25ms | SD σ 1.707825ms | PacketLoss 20.0% | Dropped 1 | Good Packets 4 | Packet Count 5 | p50 15.28ms | p95 15.9ms | p99 15.9ms | Jitter 363.5µs | Mean Jitter 2ms | Longest Streak 2 01 Jan 2000 00:00:00 -> 00:10:00 (10m0s) | Longest Drop Streak 1",
 		},
 	}
 
@@ -432,14 +433,21 @@ func sameIP(input []ping.PingDataPoint) []ping.PingResults {
 // NOTE doesn't iterate the data in duration order.
 func blockVerify(t *testing.T, graphData *data.Data, test DataTestCase) {
 	t.Helper()
-	assert.Assert(t, is.Len(graphData.Blocks, len(test.BlockTest.ExpectedBlocks)))
-	for i, block := range graphData.Blocks {
+	assert.Assert(t, is.Len(graphData.BlockHeaders, len(test.BlockTest.ExpectedBlocks)))
+	for i, header := range graphData.BlockHeaders {
 		expectedBlock := test.BlockTest.ExpectedBlocks[i]
-		assertStatsEqual(t, *expectedBlock.Header.Stats, *block.Header.Stats, 4)
-		assertTimeSpanEqual(t, *expectedBlock.Header.TimeSpan, *block.Header.TimeSpan, 4)
+		assertStatsEqual(t, *expectedBlock.Header.Stats, *header.Stats, 4)
+		assertTimeSpanEqual(t, *expectedBlock.Header.TimeSpan, *header.TimeSpan, 4)
 		if test.BlockTest.CheckRaw {
-			assert.Assert(t, is.Len(block.Raw, len(expectedBlock.Raw)), "block %d was unexpected len", i)
-			for rawIndex, datum := range block.Raw {
+			raw := []ping.PingDataPoint{}
+			for index := range graphData.TotalCount {
+				p := graphData.GetFull(index)
+				if graphData.Network.BlockIndexes[slices.IndexFunc(graphData.Network.IPs, p.IP.Equal)] == i {
+					raw = append(raw, p.Data)
+				}
+			}
+			assert.Assert(t, is.Len(raw, len(expectedBlock.Raw)), "block %d was unexpected len", i)
+			for rawIndex, datum := range raw {
 				assert.Check(t, is.DeepEqual(expectedBlock.Raw[rawIndex], datum), "raw inside block %d at index %d", i, rawIndex)
 			}
 		}
@@ -542,3 +550,86 @@ func TestDataOrdering(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	addTestPoint(d, 1, 5*time.Millisecond, net.IPv4bcast)
+	addTestPoint(d, 2, 0, net.IPv4allsys)
+	addTestPoint(d, 3, 7*time.Millisecond, net.IPv4bcast)
+	addTestPoint(d, 4, 0, net.IPv4bcast)
+	addTestPoint(d, 5, 9*time.Millisecond, net.IPv4bcast)
+	d.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(1000), Text: "dropped"})
+	d.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(3500), Text: "kept"})
+
+	span := &data.TimeSpan{Begin: time.UnixMilli(2000), End: time.UnixMilli(4000)}
+	filtered := d.Filter(
+		func(p ping.PingResults) bool { return span.Contains(p.Data.Timestamp) && p.IP.Equal(net.IPv4bcast) },
+		func(a data.Annotation) bool { return span.Contains(a.Timestamp) },
+	)
+	assert.Equal(t, filtered.TotalCount, int64(2))
+	assert.Check(t, is.DeepEqual(filtered.Get(0).Timestamp, time.UnixMilli(3000)))
+	assert.Check(t, filtered.Get(1).Dropped())
+	assert.Equal(t, len(filtered.Network.IPs), 1)
+	assert.Equal(t, filtered.Header.Stats.GoodCount, uint64(1))
+	assert.Equal(t, filtered.Runs.DroppedPackets.Longest, uint64(1))
+	assert.Check(t, is.DeepEqual(filtered.Header.TimeSpan, &data.TimeSpan{
+		Begin: time.UnixMilli(3000), End: time.UnixMilli(4000), Duration: time.Second,
+	}))
+	assert.Check(t, is.DeepEqual(filtered.Annotations, []data.Annotation{{Timestamp: time.UnixMilli(3500), Text: "kept"}}))
+}
+
+// BenchmarkMemory compares the memory used per point by [data.Data] against storing every point in a
+// [data.Block] with a [data.DataIndexes] for the insert order, which is how [data.Data] used to store points.
+func BenchmarkMemory(b *testing.B) {
+	const pointCount = 1_000_000
+	pings := make([]ping.PingResults, pointCount)
+	ips := []net.IP{net.IPv4allrouter, net.IPv4bcast}
+	for i := range pings {
+		pings[i] = ping.PingResults{
+			Data: ping.PingDataPoint{Duration: time.Duration(i) * time.Microsecond, Timestamp: origin.Add(time.Duration(i) * time.Second)},
+			IP:   ips[i%len(ips)],
+		}
+	}
+	b.Run("Data", func(b *testing.B) {
+		for b.Loop() {
+			benchmarkHeap(b, pointCount, func() any {
+				d := data.NewData("www.google.com")
+				for _, p := range pings {
+					d.AddPoint(p)
+				}
+				return d
+			})
+		}
+	})
+	b.Run("Blocks", func(b *testing.B) {
+		for b.Loop() {
+			benchmarkHeap(b, pointCount, func() any {
+				blocks := make([]*data.Block, len(ips))
+				for i := range blocks {
+					blocks[i] = &data.Block{Header: &data.Header{Stats: &data.Stats{}, TimeSpan: &data.TimeSpan{}}}
+				}
+				insertOrder := []data.DataIndexes{}
+				for i, p := range pings {
+					blockIndex := i % len(ips)
+					rawIndex := blocks[blockIndex].AddPoint(p.Data)
+					insertOrder = append(insertOrder, data.DataIndexes{BlockIndex: blockIndex, RawIndex: rawIndex})
+				}
+				return []any{blocks, insertOrder}
+			})
+		}
+	})
+}
+
+// benchmarkHeap reports the bytes per point still in use after [build] returns.
+func benchmarkHeap(b *testing.B, pointCount int, build func() any) {
+	b.Helper()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	kept := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(kept)
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(pointCount), "bytes/point")
+}
@@ -27,6 +27,10 @@ func (h *Header) write(ret []byte) int {
 }
 
 func (h *Header) FromCompact(input []byte) (int, error) {
+	return h.fromCompact(input, currentDataVersion)
+}
+
+func (h *Header) fromCompact(input []byte, version version) (int, error) {
 	i, err := readID(input, HeaderID)
 	if err != nil {
 		return i, errors.Wrap(err, "while reading compact Header")
@@ -34,7 +38,7 @@ func (h *Header) FromCompact(input []byte) (int, error) {
 	if h.Stats == nil {
 		h.Stats = &Stats{}
 	}
-	n, err := h.Stats.FromCompact(input[i:])
+	n, err := h.Stats.fromCompact(input[i:], version)
 	if err != nil {
 		return i, errors.Wrap(err, "while reading compact Header")
 	}
@@ -51,5 +55,5 @@ func (h *Header) FromCompact(input []byte) (int, error) {
 }
 
 func (h *Header) byteLen() int {
-	return headerLen
+	return idLen + timeSpanLen + h.Stats.byteLen()
 }
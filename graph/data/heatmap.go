@@ -0,0 +1,119 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"io"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// HeatmapOptions decide the cells of a [Heatmap].
+type HeatmapOptions struct {
+	// Bucket is the time of day each cell covers, it must divide a day evenly.
+	Bucket time.Duration
+	// Location is the time zone which decides when each day starts.
+	Location *time.Location
+}
+
+var DefaultHeatmapOptions = HeatmapOptions{Bucket: time.Hour, Location: time.Local}
+
+// Heatmap is a calendar of a capture, the points are grouped by the day and by the time of the day they
+// were recorded, so that congestion which repeats (e.g. every evening at 8pm) lines up in the same column.
+type Heatmap struct {
+	// Days is midnight of each day from the first point to the last, including days without any points.
+	Days    []time.Time
+	Options HeatmapOptions
+	// Cells are the stats of each day (the same index as Days) and then each bucket of that day, nil if there
+	// are no points in the bucket.
+	Cells [][]*Stats
+}
+
+func NewHeatmap(options HeatmapOptions) *Heatmap {
+	return &Heatmap{Options: options}
+}
+
+// BucketsPerDay is the number of cells in each day. A day with a daylight saving change still has the same
+// number of cells, the extra hour is counted in the last cell.
+func (h *Heatmap) BucketsPerDay() int {
+	return int(24 * time.Hour / h.Options.Bucket)
+}
+
+// Bucket is the time of day which the cell [i] starts at.
+func (h *Heatmap) Bucket(i int) time.Duration {
+	return time.Duration(i) * h.Options.Bucket
+}
+
+// AddPoint to the cell of the day and time of day of the point, the points can be in any order.
+func (h *Heatmap) AddPoint(p ping.PingDataPoint) {
+	local := p.Timestamp.In(h.Options.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, h.Options.Location)
+	day := h.dayIndex(midnight)
+	bucket := min(int(local.Sub(midnight)/h.Options.Bucket), h.BucketsPerDay()-1)
+	cell := h.Cells[day][bucket]
+	if cell == nil {
+		cell = &Stats{}
+		h.Cells[day][bucket] = cell
+	}
+	if p.Dropped() {
+		cell.AddDroppedPacket()
+	} else {
+		cell.AddPoint(p.Duration)
+	}
+}
+
+// dayIndex finds the day of [midnight] adding any days needed to reach it.
+func (h *Heatmap) dayIndex(midnight time.Time) int {
+	if len(h.Days) == 0 {
+		h.Days = append(h.Days, midnight)
+		h.Cells = append(h.Cells, make([]*Stats, h.BucketsPerDay()))
+		return 0
+	}
+	for midnight.Before(h.Days[0]) {
+		first := h.Days[0]
+		before := time.Date(first.Year(), first.Month(), first.Day()-1, 0, 0, 0, 0, h.Options.Location)
+		h.Days = append([]time.Time{before}, h.Days...)
+		h.Cells = append([][]*Stats{make([]*Stats, h.BucketsPerDay())}, h.Cells...)
+	}
+	for midnight.After(h.Days[len(h.Days)-1]) {
+		last := h.Days[len(h.Days)-1]
+		h.Days = append(h.Days, time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, h.Options.Location))
+		h.Cells = append(h.Cells, make([]*Stats, h.BucketsPerDay()))
+	}
+	for i, day := range h.Days {
+		if day.Equal(midnight) {
+			return i
+		}
+	}
+	panic("unreachable: " + midnight.String())
+}
+
+// Heatmap groups every point by day and time of day, see [Heatmap].
+func (d *Data) Heatmap(options HeatmapOptions) *Heatmap {
+	h := NewHeatmap(options)
+	for i := range d.TotalCount {
+		h.AddPoint(d.Get(i))
+	}
+	return h
+}
+
+// Heatmap reads all the remaining points grouping them by day and time of day, see [Data.Heatmap].
+func (s *Stream) Heatmap(options HeatmapOptions) (*Heatmap, error) {
+	h := NewHeatmap(options)
+	for {
+		p, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return h, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		h.AddPoint(p.Data)
+	}
+}
@@ -0,0 +1,93 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestHeatmap(t *testing.T) {
+	t.Parallel()
+	options := data.HeatmapOptions{Bucket: 15 * time.Minute, Location: time.UTC}
+	h := data.NewHeatmap(options)
+	day := time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)
+	// Out of order, a day is skipped
+	h.AddPoint(ping.PingDataPoint{Timestamp: day.Add(50*time.Hour + 20*time.Minute), Duration: 8 * time.Millisecond})
+	h.AddPoint(ping.PingDataPoint{Timestamp: day.Add(20 * time.Hour), Duration: 80 * time.Millisecond})
+	h.AddPoint(ping.PingDataPoint{Timestamp: day.Add(20*time.Hour + 14*time.Minute), DropReason: ping.Timeout})
+
+	assert.Check(t, is.Equal(h.BucketsPerDay(), 96))
+	assert.Check(t, is.DeepEqual(h.Days, []time.Time{day, day.AddDate(0, 0, 1), day.AddDate(0, 0, 2)}))
+	evening := h.Cells[0][80]
+	assert.Check(t, is.Equal(evening.GoodCount, uint64(1)))
+	assert.Check(t, is.Equal(evening.PacketsDropped, uint64(1)))
+	assert.Check(t, is.Equal(h.Cells[2][9].Max, 8*time.Millisecond))
+	for _, cell := range h.Cells[1] {
+		assert.Check(t, is.Nil(cell))
+	}
+	assert.Check(t, is.Equal(h.Bucket(9), 2*time.Hour+15*time.Minute))
+}
+
+func TestHeatmapDaylightSaving(t *testing.T) {
+	t.Parallel()
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no time zone database", err)
+	}
+	h := data.NewHeatmap(data.HeatmapOptions{Bucket: time.Hour, Location: london})
+	// The clocks go back at 2am BST on the 27th of October, so the day is 25 hours long.
+	lastHour := time.Date(2024, time.October, 27, 23, 30, 0, 0, london)
+	h.AddPoint(ping.PingDataPoint{Timestamp: lastHour, Duration: time.Millisecond})
+	assert.Check(t, is.Len(h.Days, 1))
+	assert.Check(t, h.Cells[0][23] != nil)
+}
+
+func TestHeatmapStream(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join("testdata", "input", "huge-over-days.pings")
+	d := readTestFile(t, path)
+	options := data.HeatmapOptions{Bucket: time.Hour, Location: time.UTC}
+	expected := d.Heatmap(options)
+	good, dropped := uint64(0), uint64(0)
+	for _, day := range expected.Cells {
+		for _, cell := range day {
+			if cell != nil {
+				good += cell.GoodCount
+				dropped += cell.PacketsDropped
+			}
+		}
+	}
+	assert.Check(t, is.Equal(good, d.Header.Stats.GoodCount))
+	assert.Check(t, is.Equal(dropped, d.Header.Stats.PacketsDropped))
+	assert.Check(t, len(expected.Days) > 1, len(expected.Days))
+
+	var b bytes.Buffer
+	assert.NilError(t, d.AsCompact(&b))
+	s, err := data.NewStream(&b)
+	assert.NilError(t, err)
+	actual, err := s.Heatmap(options)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(actual.Days, expected.Days))
+	for i := range expected.Cells {
+		for j := range expected.Cells[i] {
+			if expected.Cells[i][j] == nil {
+				assert.Check(t, is.Nil(actual.Cells[i][j]))
+				continue
+			}
+			assert.Check(t, is.Equal(actual.Cells[i][j].GoodCount, expected.Cells[i][j].GoodCount))
+			assert.Check(t, is.Equal(actual.Cells[i][j].Quantile(0.95), expected.Cells[i][j].Quantile(0.95)))
+		}
+	}
+}
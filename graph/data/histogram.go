@@ -0,0 +1,85 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"math"
+	"time"
+)
+
+// Bin is a single bar of a [Histogram], it counts the good points between Low (inclusive) and High.
+type Bin struct {
+	Low, High time.Duration
+	Count     uint64
+}
+
+// Histogram is the distribution of the good points between [Stats.Min] and [Stats.Max].
+type Histogram struct {
+	Bins []Bin
+	// Total is the sum of the count of every bin.
+	Total uint64
+	// Log is true if the bins are logarithmically sized, i.e. each bin is a constant multiple wider than the
+	// last, otherwise every bin is the same width.
+	Log bool
+}
+
+// Largest is the count of the fullest bin.
+func (h *Histogram) Largest() uint64 {
+	ret := uint64(0)
+	for _, b := range h.Bins {
+		ret = max(ret, b.Count)
+	}
+	return ret
+}
+
+// Histogram of the good points split into (at most) [bins] bins, the counts come from the quantile sketch
+// so they're only as accurate as [SketchAccuracy], a point may be counted in a neighbouring bin. If all the
+// good points are the same there's only one bin. Returns nil if there are no good points, see
+// [Stats.HasQuantiles].
+func (s *Stats) Histogram(bins int, log bool) *Histogram {
+	if !s.HasQuantiles() || bins <= 0 {
+		return nil
+	}
+	if s.Min == s.Max {
+		bins = 1
+	}
+	ret := &Histogram{Bins: make([]Bin, bins), Log: log}
+	// Nanoseconds are too small to matter so log bins of a zero duration start at 1ns.
+	low, high := float64(s.Min), float64(s.Max)
+	if log {
+		low, high = math.Log(max(low, 1)), math.Log(max(high, 1))
+	}
+	width := (high - low) / float64(bins)
+	edge := func(i int) time.Duration {
+		switch {
+		case i == 0:
+			return s.Min
+		case i == bins:
+			return s.Max
+		case log:
+			return time.Duration(math.Exp(low + width*float64(i)))
+		default:
+			return time.Duration(low + width*float64(i))
+		}
+	}
+	for i := range ret.Bins {
+		ret.Bins[i].Low, ret.Bins[i].High = edge(i), edge(i+1)
+	}
+	for index, count := range s.quantiles.buckets() {
+		value := float64(min(max(sketchValue(index), s.Min), s.Max))
+		if log {
+			value = math.Log(max(value, 1))
+		}
+		bin := 0
+		if width > 0 {
+			bin = min(int((value-low)/width), bins-1)
+		}
+		ret.Bins[max(bin, 0)].Count += count
+		ret.Total += count
+	}
+	return ret
+}
@@ -0,0 +1,66 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+	s := &data.Stats{}
+	s.AddPoints(ms(10, 10, 10, 11, 12, 50, 51, 95, 100))
+
+	h := s.Histogram(9, false)
+	assert.Check(t, is.Len(h.Bins, 9))
+	assert.Check(t, is.Equal(h.Total, uint64(9)))
+	assert.Check(t, is.Equal(h.Bins[0].Low, 10*time.Millisecond))
+	assert.Check(t, is.Equal(h.Bins[8].High, 100*time.Millisecond))
+	assert.Check(t, is.Equal(h.Bins[0].High, 20*time.Millisecond))
+	counts := []uint64{}
+	for _, b := range h.Bins {
+		counts = append(counts, b.Count)
+	}
+	// Two modes, around 10ms and 50ms
+	assert.Check(t, is.DeepEqual(counts, []uint64{5, 0, 0, 0, 2, 0, 0, 0, 2}))
+	assert.Check(t, is.Equal(h.Largest(), uint64(5)))
+
+	h = s.Histogram(2, true)
+	assert.Check(t, is.Equal(h.Bins[0].High, time.Duration(31622776)))
+	assert.Check(t, is.Equal(h.Bins[0].Count, uint64(5)))
+	assert.Check(t, is.Equal(h.Bins[1].Count, uint64(4)))
+}
+
+func TestHistogramSingleValue(t *testing.T) {
+	t.Parallel()
+	s := &data.Stats{}
+	s.AddPoints(ms(7, 7, 7))
+	h := s.Histogram(10, false)
+	assert.Check(t, is.Len(h.Bins, 1))
+	assert.Check(t, is.Equal(h.Bins[0].Count, uint64(3)))
+
+	assert.Check(t, is.Nil((&data.Stats{}).Histogram(10, false)))
+}
+
+func TestHistogramFile(t *testing.T) {
+	t.Parallel()
+	d := readTestFile(t, filepath.Join("testdata", "input", "verybad-london.pings"))
+	for _, log := range []bool{false, true} {
+		h := d.Header.Stats.Histogram(20, log)
+		assert.Check(t, is.Equal(h.Total, d.Header.Stats.GoodCount))
+		for i := 1; i < len(h.Bins); i++ {
+			assert.Check(t, h.Bins[i-1].High == h.Bins[i].Low)
+			assert.Check(t, h.Bins[i].Low < h.Bins[i].High)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// Layout is the structure of the bytes of a '.pings' file, it's intended for debugging the format. See
+// [Inspect].
+type Layout struct {
+	Sections []*Section
+	// Problems are the inconsistencies found in the file, a file with problems may still be readable.
+	Problems []string
+	Size     int64
+}
+
+// Section is a contiguous range of bytes of a '.pings' file.
+type Section struct {
+	Name string
+	// Detail is a short human readable description of what was decoded.
+	Detail string
+	// Preview is up to the first [PreviewLen] bytes of the section.
+	Preview  []byte
+	Children []*Section
+	Offset   int64
+	Length   int64
+	// Identifier is only set for sections which begin with one.
+	Identifier Identifier
+	// Version is only set for [Data] sections.
+	Version byte
+}
+
+// PreviewLen is the maximum length of [Section.Preview].
+const PreviewLen = 16
+
+// Inspect walks the bytes of a '.pings' file of any version with the same readers used by [ReadData],
+// recording where each section is and what it contains. Rather than stopping at the first inconsistency
+// it's recorded in [Layout.Problems] and the walk continues, only if a section can't be decoded at all does
+// the walk stop. The returned error is only set if reading [r] fails.
+func Inspect(r io.Reader) (*Layout, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "while inspecting")
+	}
+	in := &inspector{input: input, layout: &Layout{Size: int64(len(input))}}
+	root := &Section{}
+	var end int
+	if len(input) > 0 && Identifier(input[0]) == TargetsID {
+		end = in.targets(root)
+	} else {
+		end = in.data(root)
+	}
+	in.layout.Sections = root.Children
+	if !in.failed && end < len(input) {
+		in.problemf("%d unexpected bytes after the end of the file at offset %d", len(input)-end, end)
+	}
+	return in.layout, nil
+}
+
+// inspector walks [input], which begins at [base] within the whole file.
+type inspector struct {
+	layout *Layout
+	input  []byte
+	base   int
+	// failed is set once a section couldn't be decoded, no further sections are read.
+	failed bool
+}
+
+func (in *inspector) problemf(format string, args ...any) {
+	in.layout.Problems = append(in.layout.Problems, fmt.Sprintf(format, args...))
+}
+
+// open begins a section which is made up of child sections, it must be closed with the offset of the end of
+// the last child.
+func (in *inspector) open(parent *Section, name string, id Identifier, i int) *Section {
+	s := &Section{Name: name, Identifier: id, Offset: int64(in.base + i)}
+	parent.Children = append(parent.Children, s)
+	return s
+}
+
+func (in *inspector) close(s *Section, i int) {
+	s.Length = int64(in.base+i) - s.Offset
+	start := int(s.Offset) - in.base
+	s.Preview = in.input[start : start+min(PreviewLen, int(s.Length))]
+}
+
+// read decodes a section at offset [i] with [f], which returns how many bytes it consumed and the detail of
+// the section. [i] is moved to the end of the section.
+func (in *inspector) read(parent *Section, name string, id Identifier, i *int, f func(b []byte) (int, string, error)) {
+	if in.failed {
+		return
+	}
+	n, detail, err := decodeSafely(in.input[*i:], f)
+	if err != nil {
+		in.problemf("failed to read %s at offset %d: %s", name, in.base+*i, err.Error())
+		in.failed = true
+		return
+	}
+	s := &Section{Name: name, Detail: detail, Identifier: id, Offset: int64(in.base + *i), Length: int64(n)}
+	s.Preview = in.input[*i : *i+min(PreviewLen, n)]
+	parent.Children = append(parent.Children, s)
+	*i += n
+}
+
+// decodeSafely converts any panic from reading past the end of [b] into an error, the readers of each type
+// trust the lengths they're given.
+func decodeSafely(b []byte, f func(b []byte) (int, string, error)) (n int, detail string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("the file is truncated or corrupt (%v)", r)
+		}
+	}()
+	n, detail, err = f(b)
+	if err == nil && n > len(b) {
+		err = errors.Errorf("the section needs %d bytes but only %d remain", n, len(b))
+	}
+	return n, detail, err
+}
+
+// fits checks that [count] items of [size] bytes fit within [b], this prevents a corrupt length from
+// allocating huge amounts of memory.
+func fits(b []byte, count, size int) error {
+	if count < 0 || count > len(b)/size {
+		return errors.Errorf("%d items of %d bytes don't fit in the remaining %d bytes", count, size, len(b))
+	}
+	return nil
+}
+
+func (in *inspector) targets(parent *Section) int {
+	s := in.open(parent, "Targets", TargetsID, 0)
+	i := 0
+	targetsLen := 0
+	in.read(s, "Identifier & count", TargetsID, &i, func(b []byte) (int, string, error) {
+		n, err := readID(b, TargetsID)
+		if err != nil {
+			return n, "", err
+		}
+		n += readLen(b[n:], &targetsLen)
+		return n, fmt.Sprintf("%d targets", targetsLen), fits(b[n:], targetsLen, int64Len)
+	})
+	for index := range targetsLen {
+		if in.failed {
+			break
+		}
+		dataLen := 0
+		in.read(s, fmt.Sprintf("Target %d length", index), 0, &i, func(b []byte) (int, string, error) {
+			n := readLen(b, &dataLen)
+			return n, fmt.Sprintf("%d bytes", dataLen), fits(b[n:], dataLen, 1)
+		})
+		if in.failed {
+			break
+		}
+		target := &inspector{layout: in.layout, input: in.input[i : i+dataLen], base: in.base + i}
+		end := target.data(s)
+		in.failed = target.failed
+		if !in.failed && end != dataLen {
+			in.problemf("target %d has %d unexpected bytes after its data", index, dataLen-end)
+		}
+		i += dataLen
+	}
+	in.close(s, i)
+	return i
+}
+
+// data follows the same layout as [Data.readVersion5] and all the previous versions.
+func (in *inspector) data(parent *Section) int {
+	d := &Data{Network: &Network{}, Header: &Header{}, Runs: &Runs{}, Rollups: &Rollups{}}
+	s := in.open(parent, "Data", DataID, 0)
+	i := 0
+	in.read(s, "Identifier & version", DataID, &i, func(b []byte) (int, string, error) {
+		n, err := readID(b, DataID)
+		if err != nil {
+			return n, "", err
+		}
+		n += readByte(b[n:], &d.PingsMeta)
+		if d.PingsMeta < noRuns || d.PingsMeta > currentDataVersion {
+			return n, "", errors.Errorf("unknown version %d", d.PingsMeta)
+		}
+		s.Version = byte(d.PingsMeta)
+		return n, fmt.Sprintf("version %d", d.PingsMeta), nil
+	})
+	insertOrderLen := 0
+	in.read(s, "Counts", 0, &i, func(b []byte) (int, string, error) {
+		n := readLen(b, &insertOrderLen)
+		n += readInt64(b[n:], &d.TotalCount)
+		return n, fmt.Sprintf("insert order length %d, total count %d", insertOrderLen, d.TotalCount), nil
+	})
+	if !in.failed && int64(insertOrderLen) != d.TotalCount {
+		in.problemf("the insert order length %d doesn't match the total count %d", insertOrderLen, d.TotalCount)
+	}
+
+	networkHeaderReader, networkDataReader := d.Network.twoPhaseRead()
+	var IPsLen, blockIndexesLen int
+	in.read(s, "Network header", NetworkID, &i, func(b []byte) (int, string, error) {
+		n, err := networkHeaderReader(b, &IPsLen, &blockIndexesLen)
+		return n, fmt.Sprintf("%d IPs, %d block indexes", IPsLen, blockIndexesLen), err
+	})
+	headerLen := 0
+	in.read(s, "Block header length", 0, &i, func(b []byte) (int, string, error) {
+		n := readInt(b, &headerLen)
+		if headerLen == 0 {
+			return n, "variable", nil
+		}
+		return n, fmt.Sprintf("%d bytes", headerLen), nil
+	})
+	if expected := storedBlockHeaderLen(d.PingsMeta); !in.failed && headerLen != expected {
+		in.problemf("the block header length is %d bytes, expected %d", headerLen, expected)
+	}
+	blocks := in.open(s, "Block headers", 0, i)
+	blockLen := 0
+	in.read(blocks, "Count", 0, &i, func(b []byte) (int, string, error) {
+		n := readLen(b, &blockLen)
+		return n, fmt.Sprintf("%d blocks", blockLen), fits(b[n:], blockLen, blockHeaderLen(d.PingsMeta, 0))
+	})
+	if in.failed {
+		blockLen = 0
+	}
+	d.BlockHeaders = make([]*Header, blockLen)
+	blockSizes := make([]int, blockLen)
+	for index := range blockLen {
+		d.BlockHeaders[index] = &Header{}
+		in.read(blocks, fmt.Sprintf("Block %d", index), BlockID, &i, func(b []byte) (int, string, error) {
+			n, err := readBlockHeader(b, d.BlockHeaders[index], &blockSizes[index], d.PingsMeta)
+			return n, fmt.Sprintf("%d points, %s", blockSizes[index], d.BlockHeaders[index].String()), err
+		})
+	}
+	in.close(blocks, i)
+	pointsLen := 0
+	for _, size := range blockSizes {
+		pointsLen += size
+	}
+	if !in.failed && pointsLen != insertOrderLen {
+		in.problemf("the blocks hold %d points but the insert order length is %d", pointsLen, insertOrderLen)
+	}
+
+	URLLen := 0
+	in.read(s, "URL length", 0, &i, func(b []byte) (int, string, error) {
+		n := readLen(b, &URLLen)
+		return n, fmt.Sprintf("%d bytes", URLLen), nil
+	})
+	if d.PingsMeta != noRuns {
+		in.read(s, "Runs", RunsID, &i, func(b []byte) (int, string, error) {
+			n, err := d.Runs.fromCompact(b, d.PingsMeta)
+			return n, d.Runs.String(), err
+		})
+	}
+	in.read(s, "Header", HeaderID, &i, func(b []byte) (int, string, error) {
+		n, err := d.Header.fromCompact(b, d.PingsMeta)
+		return n, d.Header.String(), err
+	})
+	if !in.failed && d.Header.Stats != nil {
+		if counted := d.Header.Stats.GoodCount + d.Header.Stats.PacketsDropped; int64(counted) != d.TotalCount { //nolint:gosec
+			in.problemf("the header counts %d points but the total count is %d", counted, d.TotalCount)
+		}
+		if quantiles := d.Header.Stats.quantiles.count; d.PingsMeta > noQuantiles && quantiles != d.Header.Stats.GoodCount {
+			in.problemf("the header quantiles count %d points but there are %d good points", quantiles, d.Header.Stats.GoodCount)
+		}
+		if jitter := d.Header.Stats.jitter.count; d.PingsMeta > noJitter && jitter != d.Header.Stats.GoodCount {
+			in.problemf("the header jitter counts %d points but there are %d good points", jitter, d.Header.Stats.GoodCount)
+		}
+	}
+
+	// Phase 2 the variable sized data
+	in.read(s, "Insert order", 0, &i, func(b []byte) (int, string, error) {
+		if err := fits(b, insertOrderLen, dataIndexesLen); err != nil {
+			return 0, "", err
+		}
+		invalid := 0
+		for index := range insertOrderLen {
+			var di DataIndexes
+			_, _ = di.FromCompact(b[index*dataIndexesLen:])
+			if di.BlockIndex < 0 || di.BlockIndex >= blockLen || di.RawIndex < 0 || di.RawIndex >= blockSizes[di.BlockIndex] {
+				invalid++
+			}
+		}
+		if invalid > 0 {
+			in.problemf("%d of the insert order indexes are outside of the blocks", invalid)
+		}
+		return insertOrderLen * dataIndexesLen, fmt.Sprintf("%d indexes", insertOrderLen), nil
+	})
+	in.read(s, "Network data", 0, &i, func(b []byte) (int, string, error) {
+		if err := fits(b, IPsLen, netIPLen); err != nil {
+			return 0, "", err
+		}
+		if err := fits(b[IPsLen*netIPLen:], blockIndexesLen, intLen); err != nil {
+			return 0, "", err
+		}
+		n := networkDataReader(b, IPsLen, blockIndexesLen)
+		ips := make([]string, len(d.Network.IPs))
+		for index, ip := range d.Network.IPs {
+			ips[index] = ip.String()
+		}
+		return n, strings.Join(ips, ", "), nil
+	})
+	if !in.failed {
+		if len(d.Network.IPs) != len(d.Network.BlockIndexes) {
+			in.problemf("there are %d IPs but %d block indexes", len(d.Network.IPs), len(d.Network.BlockIndexes))
+		}
+		for index, blockIndex := range d.Network.BlockIndexes {
+			if blockIndex < 0 || blockIndex >= blockLen {
+				in.problemf("IP %d has block index %d but there are %d blocks", index, blockIndex, blockLen)
+			}
+		}
+	}
+	for index, size := range blockSizes {
+		in.read(s, fmt.Sprintf("Block %d points", index), 0, &i, func(b []byte) (int, string, error) {
+			if err := fits(b, size, pingDataPointLen); err != nil {
+				return 0, "", err
+			}
+			return size * pingDataPointLen, fmt.Sprintf("%d points", size), nil
+		})
+	}
+	in.read(s, "URL", 0, &i, func(b []byte) (int, string, error) {
+		if err := fits(b, URLLen, 1); err != nil {
+			return 0, "", err
+		}
+		n := readString(b, &d.URL, URLLen)
+		return n, strconv.Quote(d.URL), nil
+	})
+
+	if d.PingsMeta >= noRollups {
+		annotations := in.open(s, "Annotations", 0, i)
+		annotationsLen := 0
+		in.read(annotations, "Count", 0, &i, func(b []byte) (int, string, error) {
+			n := readLen(b, &annotationsLen)
+			return n, fmt.Sprintf("%d annotations", annotationsLen), fits(b[n:], annotationsLen, idLen)
+		})
+		for index := range annotationsLen {
+			in.read(annotations, fmt.Sprintf("Annotation %d", index), AnnotationID, &i, func(b []byte) (int, string, error) {
+				var a Annotation
+				n, err := a.FromCompact(b)
+				return n, a.String(), err
+			})
+		}
+		in.close(annotations, i)
+	}
+	if d.PingsMeta > noRollups {
+		var stored [][]Rollup
+		in.read(s, "Rollups", RollupsID, &i, func(b []byte) (int, string, error) {
+			n, err := d.Rollups.FromCompact(b)
+			levels := []string{}
+			for level, buckets := range d.Rollups.Levels {
+				// Only the stored levels are described, the rest are rebuilt from the points when read.
+				if buckets != nil {
+					levels = append(levels, fmt.Sprintf("%s: %d buckets", RollupResolutions[level], len(buckets)))
+					stored = append(stored, buckets)
+				}
+			}
+			return n, strings.Join(levels, ", "), err
+		})
+		if !in.failed && len(stored) > 0 {
+			var counted uint64
+			for _, bucket := range stored[0] {
+				counted += bucket.GoodCount + bucket.DroppedCount
+			}
+			if int64(counted) != d.TotalCount { //nolint:gosec
+				in.problemf("the rollups count %d points but the total count is %d", counted, d.TotalCount)
+			}
+		}
+	}
+	in.close(s, i)
+	return i
+}
@@ -0,0 +1,133 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestInspectTestFiles(t *testing.T) {
+	t.Parallel()
+	inputs, err := filepath.Glob("testdata/input/*.pings")
+	assert.NilError(t, err)
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			t.Parallel()
+			f, err := os.Open(input)
+			assert.NilError(t, err)
+			defer f.Close()
+			layout, err := data.Inspect(f)
+			assert.NilError(t, err)
+			assert.Check(t, is.Len(layout.Problems, 0))
+			checkContiguous(t, layout.Sections, 0, layout.Size)
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		d.AddPoint(p)
+	}
+	d.AddAnnotation(data.Annotation{Timestamp: d.Get(0).Timestamp, Text: "router rebooted"})
+	b := targetBytes(t, d)
+
+	layout, err := data.Inspect(bytes.NewReader(b))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(layout.Problems, 0))
+	assert.Assert(t, is.Len(layout.Sections, 1))
+	root := layout.Sections[0]
+	assert.Check(t, is.Equal(root.Identifier, data.DataID))
+	assert.Check(t, is.Equal(int(root.Version), 9))
+	assert.Check(t, is.DeepEqual(root.Preview, b[:data.PreviewLen]))
+	names := []string{}
+	for _, s := range root.Children {
+		names = append(names, s.Name)
+	}
+	assert.Check(t, is.Contains(names, "Annotations"))
+	assert.Check(t, is.Equal(names[len(names)-1], "Rollups"))
+	checkContiguous(t, layout.Sections, 0, layout.Size)
+}
+
+func TestInspectProblems(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		d.AddPoint(p)
+	}
+	b := targetBytes(t, d)
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+		layout, err := data.Inspect(bytes.NewReader(b[:len(b)/2]))
+		assert.NilError(t, err)
+		assert.Assert(t, is.Len(layout.Problems, 1))
+		assert.Check(t, is.Contains(layout.Problems[0], "failed to read"))
+	})
+	t.Run("total count", func(t *testing.T) {
+		t.Parallel()
+		corrupt := bytes.Clone(b)
+		// After the identifier, version and insert order length
+		binary.LittleEndian.PutUint64(corrupt[10:], 1)
+		layout, err := data.Inspect(bytes.NewReader(corrupt))
+		assert.NilError(t, err)
+		assert.Assert(t, len(layout.Problems) > 0)
+		assert.Check(t, is.Contains(layout.Problems[0], "doesn't match the total count 1"))
+	})
+	t.Run("trailing", func(t *testing.T) {
+		t.Parallel()
+		layout, err := data.Inspect(bytes.NewReader(append(bytes.Clone(b), 0, 0)))
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(layout.Problems, []string{
+			"2 unexpected bytes after the end of the file at offset " + strconv.Itoa(len(b)),
+		}))
+	})
+}
+
+func TestInspectTargets(t *testing.T) {
+	t.Parallel()
+	var b bytes.Buffer
+	assert.NilError(t, makeTestTargets().AsCompact(&b))
+	layout, err := data.Inspect(bytes.NewReader(b.Bytes()))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(layout.Problems, 0))
+	assert.Assert(t, is.Len(layout.Sections, 1))
+	targets := layout.Sections[0]
+	assert.Check(t, is.Equal(targets.Identifier, data.TargetsID))
+	dataSections := 0
+	for _, s := range targets.Children {
+		if s.Identifier == data.DataID {
+			dataSections++
+		}
+	}
+	assert.Check(t, is.Equal(dataSections, 3))
+	checkContiguous(t, layout.Sections, 0, layout.Size)
+}
+
+// checkContiguous asserts that the sections cover exactly [offset, end) with no gaps or overlaps, at every
+// level of children.
+func checkContiguous(t *testing.T, sections []*data.Section, offset, end int64) {
+	t.Helper()
+	for _, s := range sections {
+		assert.Check(t, is.Equal(s.Offset, offset), s.Name)
+		if len(s.Children) > 0 {
+			checkContiguous(t, s.Children, s.Offset, s.Offset+s.Length)
+		}
+		offset += s.Length
+	}
+	assert.Check(t, is.Equal(offset, end))
+}
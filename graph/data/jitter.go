@@ -0,0 +1,69 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"math"
+	"time"
+)
+
+// jitterSmoothing is the 1/16 gain of the RFC 3550 jitter estimator.
+const jitterSmoothing = 16
+
+// jitter tracks the variation between consecutive durations in the order they're added, see [Stats.Jitter]
+// and [Stats.MeanJitter].
+type jitter struct {
+	// smoothed is the RFC 3550 estimate, https://www.rfc-editor.org/rfc/rfc3550#appendix-A.8, each new
+	// difference moves it 1/16th of the way.
+	smoothed float64
+	// sumOfDifferences is the sum of the absolute differences between consecutive durations.
+	sumOfDifferences float64
+	first, last      time.Duration
+	count            uint64
+}
+
+func (j *jitter) add(d time.Duration) {
+	if j.count == 0 {
+		j.first = d
+	} else {
+		j.addDifference(d - j.last)
+	}
+	j.last = d
+	j.count++
+}
+
+func (j *jitter) addDifference(d time.Duration) {
+	difference := math.Abs(float64(d))
+	j.sumOfDifferences += difference
+	j.smoothed += (difference - j.smoothed) / jitterSmoothing
+}
+
+// merge assumes every duration of [other] came after [j]. The smoothed estimate is a weighted sum of the
+// differences, so the estimate of [other] is continued from [j] rather than from zero.
+func (j *jitter) merge(other *jitter) jitter {
+	if j.count == 0 {
+		return *other
+	}
+	if other.count == 0 {
+		return *j
+	}
+	ret := *j
+	ret.addDifference(other.first - j.last)
+	decay := math.Pow(1-1.0/jitterSmoothing, float64(other.count-1))
+	ret.smoothed = other.smoothed + decay*ret.smoothed
+	ret.sumOfDifferences += other.sumOfDifferences
+	ret.last = other.last
+	ret.count += other.count
+	return ret
+}
+
+func (j *jitter) mean() float64 {
+	if j.count < 2 {
+		return 0
+	}
+	return j.sumOfDifferences / float64(j.count-1)
+}
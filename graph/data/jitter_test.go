@@ -0,0 +1,80 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestJitter(t *testing.T) {
+	t.Parallel()
+	s := &data.Stats{}
+	s.AddPoint(10 * time.Millisecond)
+	assert.Check(t, is.Equal(s.Jitter(), time.Duration(0)))
+	assert.Check(t, is.Equal(s.MeanJitter(), time.Duration(0)))
+	s.AddDroppedPacket()
+	s.AddPoint(26 * time.Millisecond)
+	s.AddPoint(10 * time.Millisecond)
+	// Each difference of 16ms moves the jitter 1/16th of the way: 1ms then 1ms + 15ms/16.
+	assert.Check(t, is.Equal(s.Jitter(), 1*time.Millisecond+937500*time.Nanosecond))
+	assert.Check(t, is.Equal(s.MeanJitter(), 16*time.Millisecond))
+}
+
+func TestJitterMerge(t *testing.T) {
+	t.Parallel()
+	values := []time.Duration{}
+	for i := range 1000 {
+		values = append(values, time.Duration(8_000_000+(i*7919)%3_000_000))
+	}
+	all := &data.Stats{}
+	all.AddPoints(values)
+	for _, size := range []int{1, 10, 333} {
+		var merged *data.Stats
+		for chunk := range slices.Chunk(values, size) {
+			toMerge := &data.Stats{}
+			toMerge.AddPoints(chunk)
+			merged = merged.Merge(toMerge)
+		}
+		th.AssertFloatEqual(t, float64(all.Jitter()), float64(merged.Jitter()), 6, "Jitter %d", size)
+		th.AssertFloatEqual(t, float64(all.MeanJitter()), float64(merged.MeanJitter()), 9, "MeanJitter %d", size)
+	}
+	// Empty stats don't change anything
+	merged := (&data.Stats{}).Merge(all).Merge(&data.Stats{})
+	assert.Check(t, is.Equal(merged.Jitter(), all.Jitter()))
+	assert.Check(t, is.Equal(merged.MeanJitter(), all.MeanJitter()))
+}
+
+func TestJitterMigrated(t *testing.T) {
+	t.Parallel()
+	d := readTestFile(t, filepath.Join("testdata", "input", "medium-minute-gaps.pings"))
+	expected := &data.Stats{}
+	previous := time.Duration(-1)
+	sum := 0.0
+	for i := range d.TotalCount {
+		if p := d.Get(i); !p.Dropped() {
+			expected.AddPoint(p.Duration)
+			if previous >= 0 {
+				sum += math.Abs(float64(p.Duration - previous))
+			}
+			previous = p.Duration
+		}
+	}
+	assert.Check(t, is.Equal(d.Header.Stats.Jitter(), expected.Jitter()))
+	assert.Check(t, is.Equal(d.Header.Stats.MeanJitter(), time.Duration(sum/float64(expected.GoodCount-1))))
+	for _, header := range d.BlockHeaders {
+		assert.Check(t, header.Stats.MeanJitter() > 0)
+	}
+}
@@ -0,0 +1,74 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"slices"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/check"
+)
+
+// Merge combines all the inputs into a single new [Data]. Every point is re-added in timestamp order so that
+// the [Network] blocks, [Runs] and all the statistics are rebuilt from scratch. Points which are identical
+// (same timestamp, duration, drop reason and IP) are only added once, this makes merging overlapping captures
+// (or the same capture twice) safe. Annotations are combined and de-duplicated in the same way.
+//
+// The URL of the result is taken from the first input, it's up to the caller to decide if inputs with
+// differing URLs should be merged.
+func Merge(toMerge ...*Data) *Data {
+	check.Check(len(toMerge) > 0, "Merge requires at least one input")
+	points := []ping.PingResults{}
+	annotations := []Annotation{}
+	for _, d := range toMerge {
+		for i := range d.TotalCount {
+			points = append(points, d.GetFull(i))
+		}
+		annotations = append(annotations, d.Annotations...)
+	}
+	slices.SortStableFunc(points, func(a, b ping.PingResults) int { return a.Data.Timestamp.Compare(b.Data.Timestamp) })
+
+	ret := NewData(toMerge[0].URL)
+	for i, p := range points {
+		if seenBefore(points[:i], p) {
+			continue
+		}
+		ret.AddPoint(p)
+	}
+	for i, a := range annotations {
+		if slices.ContainsFunc(annotations[:i], func(other Annotation) bool { return sameAnnotation(a, other) }) {
+			continue
+		}
+		ret.AddAnnotation(a)
+	}
+	return ret
+}
+
+// seenBefore searches backwards through the timestamp sorted [before] for an identical point, stopping as
+// soon as the timestamps no longer match.
+func seenBefore(before []ping.PingResults, p ping.PingResults) bool {
+	for i := len(before) - 1; i >= 0; i-- {
+		if !before[i].Data.Timestamp.Equal(p.Data.Timestamp) {
+			return false
+		}
+		if samePoint(before[i], p) {
+			return true
+		}
+	}
+	return false
+}
+
+func samePoint(a, b ping.PingResults) bool {
+	return a.Data.Timestamp.Equal(b.Data.Timestamp) &&
+		a.Data.Duration == b.Data.Duration &&
+		a.Data.DropReason == b.Data.DropReason &&
+		a.IP.Equal(b.IP)
+}
+
+func sameAnnotation(a, b Annotation) bool {
+	return a.Timestamp.Equal(b.Timestamp) && a.Text == b.Text
+}
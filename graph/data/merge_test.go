@@ -0,0 +1,72 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	first := data.NewData("www.google.com")
+	second := data.NewData("www.google.com")
+	addTestPoint(first, 1, 5*time.Millisecond, net.IPv4bcast)
+	addTestPoint(first, 3, 7*time.Millisecond, net.IPv4bcast)
+	// Overlaps with [second]
+	addTestPoint(first, 5, 6*time.Millisecond, net.IPv4bcast)
+	addTestPoint(second, 2, 0, net.IPv4allsys)
+	addTestPoint(second, 4, 8*time.Millisecond, net.IPv4allsys)
+	addTestPoint(second, 5, 6*time.Millisecond, net.IPv4bcast)
+	first.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(3000), Text: "router rebooted"})
+	second.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(3000), Text: "router rebooted"})
+	second.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(1000), Text: "start"})
+
+	merged := data.Merge(first, second)
+
+	assert.Equal(t, merged.URL, "www.google.com")
+	assert.Equal(t, merged.TotalCount, int64(5))
+	for i := range merged.TotalCount {
+		assert.Check(t, is.DeepEqual(merged.Get(i).Timestamp, time.UnixMilli((i+1)*1000)))
+	}
+	assert.Check(t, merged.GetFull(1).Data.Dropped())
+	assert.Check(t, merged.GetFull(1).IP.Equal(net.IPv4allsys))
+	assert.Equal(t, len(merged.Network.IPs), 2)
+	assert.Equal(t, merged.Header.Stats.GoodCount, uint64(4))
+	assert.Equal(t, merged.Header.Stats.PacketsDropped, uint64(1))
+	assert.Equal(t, merged.Runs.GoodPackets.Longest, uint64(3))
+	assert.Equal(t, merged.Runs.DroppedPackets.Longest, uint64(1))
+	assert.Check(t, is.DeepEqual(merged.Annotations, []data.Annotation{
+		{Timestamp: time.UnixMilli(1000), Text: "start"},
+		{Timestamp: time.UnixMilli(3000), Text: "router rebooted"},
+	}))
+}
+
+func TestMergeIsIdempotent(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		d.AddPoint(p)
+	}
+	merged := data.Merge(d, d)
+	assert.Check(t, is.DeepEqual(d, merged, th.AllowAllUnexported))
+}
+
+func addTestPoint(d *data.Data, second int64, duration time.Duration, ip net.IP) {
+	p := ping.PingDataPoint{Duration: duration, Timestamp: time.UnixMilli(second * 1000)}
+	if duration == 0 {
+		p.DropReason = ping.TestDrop
+	}
+	d.AddPoint(ping.PingResults{Data: p, IP: ip})
+}
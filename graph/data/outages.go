@@ -0,0 +1,197 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// OutageOptions decide which dropped points are an [Outage].
+type OutageOptions struct {
+	// MinDropped is the fewest consecutive dropped points (for any reason, e.g. timeouts or DNS failures)
+	// which are an outage.
+	MinDropped int
+	// MaxGap is the longest time between two consecutive points which still counts as observed, longer gaps
+	// are when nothing was capturing and count as neither up nor down.
+	MaxGap time.Duration
+}
+
+var DefaultOutageOptions = OutageOptions{MinDropped: 3, MaxGap: 5 * time.Minute}
+
+// Outage is a run of consecutive dropped points.
+type Outage struct {
+	// Begin is the first dropped point and End is the first good point after the outage, or the last dropped
+	// point if the capture ended or stopped during the outage.
+	Begin, End time.Time
+	// FirstIndex is the index of the first dropped point.
+	FirstIndex int64
+	Count      int64
+	// Reason is the most common reason the points were dropped.
+	Reason ping.Dropped
+}
+
+func (o Outage) Duration() time.Duration {
+	return o.End.Sub(o.Begin)
+}
+
+func (o Outage) String() string {
+	span := TimeSpan{Begin: o.Begin, End: o.End, Duration: o.Duration()}
+	return fmt.Sprintf("%s | %d dropped | %s", span.String(), o.Count, o.Reason.String())
+}
+
+// OutageReport is every [Outage] of a capture and the availability figures which follow from them.
+type OutageReport struct {
+	Outages []Outage
+	// Observed is the total time the capture was running, see [OutageOptions.MaxGap].
+	Observed time.Duration
+	// Downtime is the total duration of the outages.
+	Downtime time.Duration
+}
+
+// Uptime is the fraction of the observed time which wasn't during an outage, between 0 and 1.
+func (r *OutageReport) Uptime() float64 {
+	if r.Observed <= 0 {
+		return 1
+	}
+	return max(0, 1-float64(r.Downtime)/float64(r.Observed))
+}
+
+// MTTR is the mean time to recovery, the mean duration of an outage. 0 if there were no outages.
+func (r *OutageReport) MTTR() time.Duration {
+	if len(r.Outages) == 0 {
+		return 0
+	}
+	return r.Downtime / time.Duration(len(r.Outages))
+}
+
+// MTBF is the mean time between failures, the observed time which wasn't an outage divided by the number of
+// outages. 0 if there were no outages.
+func (r *OutageReport) MTBF() time.Duration {
+	if len(r.Outages) == 0 {
+		return 0
+	}
+	return (r.Observed - r.Downtime) / time.Duration(len(r.Outages))
+}
+
+// Summary is a single line of the availability figures.
+func (r *OutageReport) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Uptime %.3f%% | Outages %d", r.Uptime()*100, len(r.Outages))
+	if len(r.Outages) > 0 {
+		fmt.Fprintf(&b, " | Downtime %s | MTBF %s | MTTR %s",
+			r.Downtime.Round(time.Millisecond), r.MTBF().Round(time.Second), r.MTTR().Round(time.Millisecond))
+	}
+	return b.String()
+}
+
+// OutageFinder builds an [OutageReport] one point at a time, so that it works with both [Data] and [Stream].
+// Points must be added in timestamp order.
+type OutageFinder struct {
+	options OutageOptions
+	report  OutageReport
+	// current is the run of dropped points so far, it's only an outage once it's long enough.
+	current Outage
+	reasons map[ping.Dropped]int64
+	last    ping.PingDataPoint
+	started bool
+}
+
+func NewOutageFinder(options OutageOptions) *OutageFinder {
+	return &OutageFinder{options: options, report: OutageReport{Outages: []Outage{}}, reasons: map[ping.Dropped]int64{}}
+}
+
+func (f *OutageFinder) AddPoint(index int64, p ping.PingDataPoint) {
+	gap := p.Timestamp.Sub(f.last.Timestamp)
+	captured := f.started && gap <= f.options.MaxGap
+	if captured {
+		f.report.Observed += gap
+	}
+	if f.current.Count > 0 && (p.Good() || !captured) {
+		end := f.last.Timestamp
+		if captured {
+			// Recovered, the outage lasted until now.
+			end = p.Timestamp
+		}
+		f.finish(end)
+	}
+	if p.Dropped() {
+		if f.current.Count == 0 {
+			f.current = Outage{Begin: p.Timestamp, FirstIndex: index}
+		}
+		f.current.Count++
+		f.reasons[p.DropReason]++
+	}
+	f.last = p
+	f.started = true
+}
+
+// Report is every outage found so far, including one which hasn't recovered yet.
+func (f *OutageFinder) Report() *OutageReport {
+	ret := f.report
+	ret.Outages = append([]Outage{}, f.report.Outages...)
+	if f.current.Count >= int64(f.options.MinDropped) {
+		o := f.current
+		o.End = f.last.Timestamp
+		o.Reason = f.dominantReason()
+		ret.Outages = append(ret.Outages, o)
+		ret.Downtime += o.Duration()
+	}
+	return &ret
+}
+
+func (f *OutageFinder) finish(end time.Time) {
+	if f.current.Count >= int64(f.options.MinDropped) {
+		f.current.End = end
+		f.current.Reason = f.dominantReason()
+		f.report.Outages = append(f.report.Outages, f.current)
+		f.report.Downtime += f.current.Duration()
+	}
+	f.current = Outage{}
+	clear(f.reasons)
+}
+
+// dominantReason is the most common reason of the current outage, ties go to the smallest reason.
+func (f *OutageFinder) dominantReason() ping.Dropped {
+	var ret ping.Dropped
+	most := int64(0)
+	for reason, count := range f.reasons {
+		if count > most || (count == most && reason < ret) {
+			ret, most = reason, count
+		}
+	}
+	return ret
+}
+
+// Outages finds every outage of the data in insert order.
+func (d *Data) Outages(options OutageOptions) *OutageReport {
+	f := NewOutageFinder(options)
+	for i := range d.TotalCount {
+		f.AddPoint(i, d.Get(i))
+	}
+	return f.Report()
+}
+
+// Outages reads all the remaining points finding every outage, see [Data.Outages].
+func (s *Stream) Outages(options OutageOptions) (*OutageReport, error) {
+	f := NewOutageFinder(options)
+	for index := int64(0); ; index++ {
+		p, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return f.Report(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.AddPoint(index, p.Data)
+	}
+}
@@ -0,0 +1,104 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+var outageStart = time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)
+
+// outageData is a point a second with [reasons] as the drop reason of each point.
+func outageData(reasons ...ping.Dropped) *data.Data {
+	d := data.NewData("www.google.com")
+	for i, reason := range reasons {
+		p := ping.PingDataPoint{Timestamp: outageStart.Add(time.Duration(i) * time.Second), DropReason: reason}
+		if reason == ping.NotDropped {
+			p.Duration = 8 * time.Millisecond
+		}
+		d.AddPoint(ping.PingResults{Data: p, IP: net.IPv4(142, 250, 179, 228)})
+	}
+	return d
+}
+
+const (
+	good = ping.NotDropped
+	to   = ping.Timeout
+	dns  = ping.DNSFailure
+)
+
+func TestOutages(t *testing.T) {
+	t.Parallel()
+	d := outageData(good, to, to, good, dns, dns, to, dns, good, good, to, to, to)
+	report := d.Outages(data.OutageOptions{MinDropped: 3, MaxGap: time.Minute})
+
+	expected := []data.Outage{
+		{Begin: outageStart.Add(4 * time.Second), End: outageStart.Add(8 * time.Second), FirstIndex: 4, Count: 4, Reason: dns},
+		// Not recovered yet so ends on the last point
+		{Begin: outageStart.Add(10 * time.Second), End: outageStart.Add(12 * time.Second), FirstIndex: 10, Count: 3, Reason: to},
+	}
+	assert.Check(t, is.DeepEqual(report.Outages, expected))
+	assert.Check(t, is.Equal(report.Observed, 12*time.Second))
+	assert.Check(t, is.Equal(report.Downtime, 6*time.Second))
+	assert.Check(t, is.Equal(report.Uptime(), 0.5))
+	assert.Check(t, is.Equal(report.MTTR(), 3*time.Second))
+	assert.Check(t, is.Equal(report.MTBF(), 3*time.Second))
+	assert.Check(t, is.Equal(report.Summary(), "Uptime 50.000% | Outages 2 | Downtime 6s | MTBF 3s | MTTR 3s"))
+
+	// The shorter drop streak now counts
+	report = d.Outages(data.OutageOptions{MinDropped: 2, MaxGap: time.Minute})
+	assert.Check(t, is.Len(report.Outages, 3))
+	assert.Check(t, is.Equal(report.Outages[0].Duration(), 2*time.Second))
+}
+
+func TestOutagesGap(t *testing.T) {
+	t.Parallel()
+	d := outageData(good, to, to, to)
+	// Nothing was recording for an hour, the outage ends when the recording stopped.
+	d.AddPoint(ping.PingResults{
+		Data: ping.PingDataPoint{Timestamp: outageStart.Add(time.Hour), DropReason: to},
+		IP:   net.IPv4(142, 250, 179, 228),
+	})
+	report := d.Outages(data.OutageOptions{MinDropped: 3, MaxGap: time.Minute})
+	assert.Assert(t, is.Len(report.Outages, 1))
+	assert.Check(t, is.Equal(report.Outages[0].End, outageStart.Add(3*time.Second)))
+	assert.Check(t, is.Equal(report.Observed, 3*time.Second))
+}
+
+func TestOutagesNone(t *testing.T) {
+	t.Parallel()
+	report := outageData(good, to, good, good).Outages(data.DefaultOutageOptions)
+	assert.Check(t, is.Len(report.Outages, 0))
+	assert.Check(t, is.Equal(report.Uptime(), 1.0))
+	assert.Check(t, is.Equal(report.MTBF(), time.Duration(0)))
+	assert.Check(t, is.Equal(report.Summary(), "Uptime 100.000% | Outages 0"))
+}
+
+func TestOutagesStream(t *testing.T) {
+	t.Parallel()
+	for _, file := range []string{"medium-309-with-induced-drops-02-08-2024.pings", "verybad-london.pings", "broken-network.pings"} {
+		path := filepath.Join("testdata", "input", file)
+		d := readTestFile(t, path)
+		b, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		s, err := data.NewStream(bytes.NewReader(b))
+		assert.NilError(t, err)
+		report, err := s.Outages(data.DefaultOutageOptions)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(report, d.Outages(data.DefaultOutageOptions)), file)
+	}
+}
@@ -0,0 +1,395 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"cmp"
+	"io"
+	"math"
+	"math/cmplx"
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// PeriodicityOptions tune which periods [Data.Periodicity] looks for.
+type PeriodicityOptions struct {
+	// Step is the interval the latency is resampled to, if zero the median interval between the points is used.
+	Step time.Duration
+	// MinPeriod is the shortest period looked for, it's at least two steps.
+	MinPeriod time.Duration
+	// MaxPeriod is the longest period looked for, if zero this is a third of the capture. A period must repeat
+	// at least three times in the capture to be found.
+	MaxPeriod time.Duration
+	// Top is the most periods reported, the most dominant are kept.
+	Top int
+	// MinConfidence is how sure it must be that a period isn't just noise, between 0 and 1.
+	MinConfidence float64
+}
+
+var DefaultPeriodicityOptions = PeriodicityOptions{
+	MinPeriod:     10 * time.Second,
+	Top:           3,
+	MinConfidence: 0.99,
+}
+
+// Period is a pattern in the latency which repeats, e.g. a backup job which runs every 10 minutes.
+type Period struct {
+	Period time.Duration
+	// Peak is when the latency is highest in the first cycle of the period, the following peaks are at
+	// multiples of the period after this.
+	Peak time.Time
+	// Strength is the autocorrelation of the latency at the period, 1 is perfectly periodic. This is of the
+	// latency once the more dominant periods are removed.
+	Strength float64
+	// Confidence is the probability that the period isn't noise, adjusted for the number of periods tried.
+	Confidence float64
+	// Cycles is how many times the period repeats in the capture.
+	Cycles int
+}
+
+// Guides are the times of each peak of the period from the first which are within the [span].
+func (p Period) Guides(span *TimeSpan) []time.Time {
+	if p.Period <= 0 {
+		return nil
+	}
+	ret := []time.Time{}
+	// The first peak at or after the beginning of the span.
+	first := p.Peak.Add(-p.Peak.Sub(span.Begin).Truncate(p.Period))
+	if first.Before(span.Begin) {
+		first = first.Add(p.Period)
+	}
+	for t := first; !t.After(span.End); t = t.Add(p.Period) {
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+// PeriodicityReport is the result of [Data.Periodicity].
+type PeriodicityReport struct {
+	// Step is the interval the latency was resampled to.
+	Step time.Duration
+	// Observed is the time between the first and last good point.
+	Observed time.Duration
+	// Samples is the number of steps the latency was resampled to.
+	Samples int
+	// Periods are the periods found, the most dominant first, empty if the latency isn't periodic.
+	Periods []Period
+}
+
+// maxPeriodicitySamples limits the memory and time used by very long captures, the step is made longer to fit.
+const maxPeriodicitySamples = 1 << 17
+
+// minPeriodicitySamples is the fewest resampled steps with a point in which could show a pattern.
+const minPeriodicitySamples = 16
+
+// Periodicity finds the periods in the latency of the good points. The latency is resampled to evenly spaced
+// steps (the mean of the points in each step) and then the peaks in the autocorrelation, computed with an FFT,
+// are the periods. Each peak's confidence uses Bartlett's formula for the variance of the autocorrelation
+// (https://en.wikipedia.org/wiki/Correlogram), since the latency is rarely independent from one ping to the
+// next.
+func (d *Data) Periodicity(options PeriodicityOptions) *PeriodicityReport {
+	points := []ping.PingDataPoint{}
+	for i := range d.TotalCount {
+		if p := d.Get(i); p.Good() {
+			points = append(points, p)
+		}
+	}
+	return periodicity(points, options)
+}
+
+// Periodicity reads all the remaining points and finds the periods in their latency, see [Data.Periodicity].
+func (s *Stream) Periodicity(options PeriodicityOptions) (*PeriodicityReport, error) {
+	points := []ping.PingDataPoint{}
+	for {
+		p, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return periodicity(points, options), nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "while finding periods")
+		}
+		if p.Data.Good() {
+			points = append(points, p.Data)
+		}
+	}
+}
+
+func periodicity(points []ping.PingDataPoint, options PeriodicityOptions) *PeriodicityReport {
+	ret := &PeriodicityReport{Periods: []Period{}}
+	if len(points) < minPeriodicitySamples {
+		return ret
+	}
+	slices.SortFunc(points, func(a, b ping.PingDataPoint) int { return a.Timestamp.Compare(b.Timestamp) })
+	begin := points[0].Timestamp
+	ret.Observed = points[len(points)-1].Timestamp.Sub(begin)
+	ret.Step = options.Step
+	if ret.Step <= 0 {
+		ret.Step = medianInterval(points)
+	}
+	if ret.Step <= 0 || ret.Observed <= 0 {
+		return ret
+	}
+	ret.Step = max(ret.Step, ret.Observed/maxPeriodicitySamples+1)
+	ret.Samples = int(ret.Observed/ret.Step) + 1
+
+	series, filled := resample(points, begin, ret.Step, ret.Samples)
+	filledCount := 0
+	for _, f := range filled {
+		if f {
+			filledCount++
+		}
+	}
+	if filledCount < minPeriodicitySamples {
+		return ret
+	}
+	minLag := max(int(options.MinPeriod/ret.Step), 2)
+	maxLag := ret.Samples / 3
+	if options.MaxPeriod > 0 {
+		maxLag = min(maxLag, int(options.MaxPeriod/ret.Step))
+	}
+	if minLag >= maxLag {
+		return ret
+	}
+	// Each period found is removed from the latency before looking for the next, otherwise its multiples and
+	// where it coincides with other periods would also be found.
+	for len(ret.Periods) < max(options.Top, 1) {
+		acf := autocorrelation(series)
+		if acf == nil {
+			break
+		}
+		peaks := acfPeaks(acf, filledCount, minLag, maxLag)
+		// Removing a period is never perfect, what's left of it isn't a new period.
+		peaks = slices.DeleteFunc(peaks, func(c acfPeak) bool {
+			return slices.ContainsFunc(ret.Periods, func(p Period) bool {
+				return isMultiple(time.Duration(c.lag*float64(ret.Step)), p.Period, ret.Step)
+			})
+		})
+		c, found := fundamental(peaks, options.MinConfidence)
+		if !found {
+			break
+		}
+		profile := fold(series, filled, c.lag)
+		peak := 0
+		for step, v := range profile {
+			if v > profile[peak] {
+				peak = step
+			}
+		}
+		period := time.Duration(c.lag * float64(ret.Step))
+		ret.Periods = append(ret.Periods, Period{
+			Period:     period,
+			Peak:       begin.Add(time.Duration(peak) * ret.Step),
+			Strength:   c.strength,
+			Confidence: c.confidence,
+			Cycles:     int(ret.Observed / period),
+		})
+		for i := range series {
+			if filled[i] {
+				series[i] -= profile[foldStep(i, c.lag)]
+			}
+		}
+	}
+	return ret
+}
+
+// medianInterval is the median time between the consecutive [points], gaps in the capture don't move the
+// median.
+func medianInterval(points []ping.PingDataPoint) time.Duration {
+	intervals := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		if interval := points[i].Timestamp.Sub(points[i-1].Timestamp); interval > 0 {
+			intervals = append(intervals, interval)
+		}
+	}
+	if len(intervals) == 0 {
+		return 0
+	}
+	slices.Sort(intervals)
+	return intervals[len(intervals)/2]
+}
+
+// resample the latency of [points] into [samples] steps, each step is the mean latency of its points less the
+// mean latency of every point. Steps without a point are zero so they don't add to the autocorrelation.
+func resample(points []ping.PingDataPoint, begin time.Time, step time.Duration, samples int) ([]float64, []bool) {
+	series := make([]float64, samples)
+	counts := make([]int, samples)
+	for _, p := range points {
+		i := min(int(p.Timestamp.Sub(begin)/step), samples-1)
+		series[i] += float64(p.Duration)
+		counts[i]++
+	}
+	filled := make([]bool, samples)
+	steps, total := 0, 0.0
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		series[i] /= float64(count)
+		total += series[i]
+		filled[i] = true
+		steps++
+	}
+	mean := total / float64(max(steps, 1))
+	for i := range series {
+		if filled[i] {
+			series[i] -= mean
+		}
+	}
+	return series, filled
+}
+
+// autocorrelation of the [series] normalised so that the first lag is 1, nil if the series is constant. This
+// is the inverse FFT of the power spectrum, padded so that the series doesn't wrap around onto itself.
+func autocorrelation(series []float64) []float64 {
+	size := 1
+	for size < 2*len(series) {
+		size <<= 1
+	}
+	spectrum := make([]complex128, size)
+	for i, v := range series {
+		spectrum[i] = complex(v, 0)
+	}
+	fft(spectrum, false)
+	for i, v := range spectrum {
+		spectrum[i] = complex(real(v)*real(v)+imag(v)*imag(v), 0)
+	}
+	fft(spectrum, true)
+	variance := real(spectrum[0])
+	if variance <= 0 {
+		return nil
+	}
+	ret := make([]float64, len(series))
+	for i := range ret {
+		ret[i] = real(spectrum[i]) / variance
+	}
+	return ret
+}
+
+// fft is an in place iterative radix-2 Cooley-Tukey fast Fourier transform, len(a) must be a power of two.
+// The inverse isn't scaled by 1/len(a) since only the ratio between the values is used.
+func fft(a []complex128, inverse bool) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+	for length := 2; length <= n; length <<= 1 {
+		w := cmplx.Rect(1, sign*2*math.Pi/float64(length))
+		for i := 0; i < n; i += length {
+			wn := complex(1, 0)
+			for j := range length / 2 {
+				u, v := a[i+j], a[i+j+length/2]*wn
+				a[i+j], a[i+j+length/2] = u+v, u-v
+				wn *= w
+			}
+		}
+	}
+}
+
+type acfPeak struct {
+	// lag is interpolated between the steps either side of the peak.
+	lag                  float64
+	strength, confidence float64
+}
+
+// acfPeaks finds the local maxima of the autocorrelation between the lags. A peak must also rise above the
+// lowest point since the previous peak by a standard deviation, so that the wobbles of a slowly changing
+// latency which is correlated at every lag aren't peaks.
+func acfPeaks(acf []float64, filled, minLag, maxLag int) []acfPeak {
+	ret := []acfPeak{}
+	// Bartlett's formula, the variance of each lag grows with the correlation at the smaller lags.
+	squares := 0.0
+	trough := acf[0]
+	tried := float64(maxLag - minLag + 1)
+	for k := 1; k < len(acf)-1 && k <= maxLag; k++ {
+		trough = min(trough, acf[k])
+		isPeak := acf[k] > acf[k-1] && acf[k] >= acf[k+1]
+		deviation := math.Sqrt((1 + 2*squares) / float64(filled))
+		if isPeak && k >= minLag && acf[k] > 0 && acf[k]-trough >= deviation {
+			z := acf[k] / deviation
+			// Bonferroni corrected for every lag which could have been a peak.
+			p := min(1, tried*math.Erfc(z/math.Sqrt2)/2)
+			ret = append(ret, acfPeak{
+				lag:        float64(k) + parabolicOffset(acf[k-1], acf[k], acf[k+1]),
+				strength:   acf[k],
+				confidence: 1 - p,
+			})
+		}
+		if isPeak {
+			trough = acf[k]
+		}
+		squares += acf[k] * acf[k]
+	}
+	return ret
+}
+
+// parabolicOffset is where the peak of the parabola through the three points is, relative to the middle one.
+func parabolicOffset(before, peak, after float64) float64 {
+	denominator := before - 2*peak + after
+	if denominator == 0 {
+		return 0
+	}
+	return max(min(0.5*(before-after)/denominator, 0.5), -0.5)
+}
+
+// isMultiple is true if [period] is a whole multiple of [of] (including [of] itself) to within a step or 2%.
+func isMultiple(period, of, step time.Duration) bool {
+	multiple := math.Round(float64(period) / float64(of))
+	if multiple < 1 {
+		return false
+	}
+	difference := math.Abs(float64(period) - multiple*float64(of))
+	return difference <= max(float64(step), 0.02*float64(period))
+}
+
+// fundamental is the period of the autocorrelation [peaks], every multiple of a period is also a peak and
+// where two periods coincide is stronger than either. So it's the shortest confident peak which is at least
+// half as strong as the strongest.
+func fundamental(peaks []acfPeak, minConfidence float64) (acfPeak, bool) {
+	peaks = slices.DeleteFunc(peaks, func(p acfPeak) bool { return p.confidence < minConfidence })
+	if len(peaks) == 0 {
+		return acfPeak{}, false
+	}
+	strongest := slices.MaxFunc(peaks, func(a, b acfPeak) int { return cmp.Compare(a.strength, b.strength) })
+	return peaks[slices.IndexFunc(peaks, func(p acfPeak) bool { return p.strength >= strongest.strength/2 })], true
+}
+
+// fold the filled steps of the series by the period (in steps), the mean of each step of the period over every
+// cycle.
+func fold(series []float64, filled []bool, lag float64) []float64 {
+	profile := make([]float64, int(math.Ceil(lag)))
+	counts := make([]int, len(profile))
+	for i, v := range series {
+		if filled[i] {
+			profile[foldStep(i, lag)] += v
+			counts[foldStep(i, lag)]++
+		}
+	}
+	for step, count := range counts {
+		profile[step] /= float64(max(count, 1))
+	}
+	return profile
+}
+
+// foldStep is which step of the period (in steps) the [i]th step of the series is, rounded to the nearest
+// step so that a fractional period doesn't drift a step early.
+func foldStep(i int, lag float64) int {
+	return int(math.Round(math.Mod(float64(i), lag))) % int(math.Ceil(lag))
+}
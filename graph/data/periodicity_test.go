@@ -0,0 +1,92 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// periodicData is two hours of a ping a second with some noise, [spike] is the extra latency at each second.
+func periodicData(spike func(second int) time.Duration) (*data.Data, time.Time) {
+	start := time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)
+	r := rand.New(rand.NewPCG(1, 2))
+	d := data.NewData("www.google.com")
+	for i := range 2 * 60 * 60 {
+		p := ping.PingDataPoint{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Duration:  10*time.Millisecond + time.Duration(r.IntN(4000))*time.Microsecond + spike(i),
+		}
+		if i%97 == 0 {
+			p = ping.PingDataPoint{Timestamp: p.Timestamp, DropReason: ping.Timeout}
+		}
+		d.AddPoint(ping.PingResults{Data: p})
+	}
+	return d, start
+}
+
+func TestPeriodicity(t *testing.T) {
+	t.Parallel()
+	// A backup job every 5 minutes which slows the link for a few seconds, starting 37 seconds in
+	d, start := periodicData(func(second int) time.Duration {
+		if (second-37)%300 < 3 && second >= 37 {
+			return 80 * time.Millisecond
+		}
+		return 0
+	})
+	report := d.Periodicity(data.DefaultPeriodicityOptions)
+	assert.Check(t, is.Equal(report.Step, time.Second))
+	// The first point is dropped
+	assert.Check(t, is.Equal(report.Samples, 2*60*60-1))
+	assert.Assert(t, is.Len(report.Periods, 1), report.Periods)
+	p := report.Periods[0]
+	assert.Check(t, p.Period > 299*time.Second && p.Period < 301*time.Second, p.Period)
+	assert.Check(t, p.Peak.Sub(start) >= 37*time.Second && p.Peak.Sub(start) < 40*time.Second, p.Peak.Sub(start))
+	assert.Check(t, p.Strength > 0.5, p.Strength)
+	assert.Check(t, p.Confidence > 0.99, p.Confidence)
+	assert.Check(t, is.Equal(p.Cycles, 23))
+
+	guides := p.Guides(&data.TimeSpan{Begin: start.Add(10 * time.Minute), End: start.Add(20 * time.Minute)})
+	assert.Assert(t, is.Len(guides, 2), guides)
+	assert.Check(t, is.Equal(guides[0], p.Peak.Add(2*p.Period)))
+}
+
+func TestPeriodicityTwoPeriods(t *testing.T) {
+	t.Parallel()
+	d, _ := periodicData(func(second int) time.Duration {
+		var ret time.Duration
+		if second%180 == 0 {
+			ret += 100 * time.Millisecond
+		}
+		if second%420 < 2 {
+			ret += 60 * time.Millisecond
+		}
+		return ret
+	})
+	report := d.Periodicity(data.DefaultPeriodicityOptions)
+	assert.Assert(t, is.Len(report.Periods, 2), report.Periods)
+	// The larger spikes are more dominant
+	assert.Check(t, is.Equal(report.Periods[0].Period.Round(time.Second), 180*time.Second), report.Periods[0])
+	assert.Check(t, is.Equal(report.Periods[1].Period.Round(time.Second), 420*time.Second), report.Periods[1])
+}
+
+func TestPeriodicityNoise(t *testing.T) {
+	t.Parallel()
+	d, _ := periodicData(func(int) time.Duration { return 0 })
+	report := d.Periodicity(data.DefaultPeriodicityOptions)
+	assert.Check(t, is.Len(report.Periods, 0), report.Periods)
+
+	empty := data.NewData("www.google.com").Periodicity(data.DefaultPeriodicityOptions)
+	assert.Check(t, is.Len(empty.Periods, 0))
+	assert.Check(t, is.Equal(empty.Samples, 0))
+}
@@ -0,0 +1,75 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+// points stores every point of a [Data] as columns, the values of a single point are at the same index of
+// each column which is the insert order. This avoids storing a whole [ping.PingDataPoint] (mostly the
+// [time.Time]) per point along with a separate index for the insert order, see BenchmarkMemory.
+type points struct {
+	// origin is the timestamp of the first point added, every timestamp is stored relative to it so it's also
+	// the location of every timestamp.
+	origin time.Time
+	// timestamps are nanoseconds since the origin, so only points within ~292 years of the first are
+	// representable.
+	timestamps  []time.Duration
+	durations   []time.Duration
+	blocks      []uint32
+	dropReasons []ping.Dropped
+}
+
+func (p *points) len() int {
+	return len(p.timestamps)
+}
+
+// grow ensures there's space for another [n] points.
+func (p *points) grow(n int) {
+	p.timestamps = slices.Grow(p.timestamps, n)
+	p.durations = slices.Grow(p.durations, n)
+	p.dropReasons = slices.Grow(p.dropReasons, n)
+	p.blocks = slices.Grow(p.blocks, n)
+}
+
+func (p *points) add(point ping.PingDataPoint, blockIndex int) {
+	if len(p.timestamps) == 0 {
+		// Strip the monotonic clock reading, it's meaningless once written to file.
+		p.origin = point.Timestamp.Round(0)
+	}
+	p.timestamps = append(p.timestamps, point.Timestamp.Sub(p.origin))
+	p.durations = append(p.durations, point.Duration)
+	p.dropReasons = append(p.dropReasons, point.DropReason)
+	//nolint:gosec
+	// G115 the block index is the count of IPs seen, which won't reach 2^32.
+	p.blocks = append(p.blocks, uint32(blockIndex))
+}
+
+func (p *points) get(index int64) ping.PingDataPoint {
+	return ping.PingDataPoint{
+		Timestamp:  p.origin.Add(p.timestamps[index]),
+		Duration:   p.durations[index],
+		DropReason: p.dropReasons[index],
+	}
+}
+
+func (p *points) block(index int64) int {
+	return int(p.blocks[index])
+}
+
+// blockSizes counts the points in each block.
+func (p *points) blockSizes(blockLen int) []int {
+	ret := make([]int, blockLen)
+	for _, block := range p.blocks {
+		ret[block]++
+	}
+	return ret
+}
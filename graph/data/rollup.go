@@ -0,0 +1,163 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+// RollupResolutions are the widths of the buckets kept by [Rollups], finest first. The index of a resolution
+// is the index of the level in [Rollups.Levels].
+var RollupResolutions = []time.Duration{time.Second, time.Minute, time.Hour}
+
+// storedRollupLevel is the first level of [RollupResolutions] which is written to file. The finer levels have
+// about a bucket per point which would make a file several times larger, so they're rebuilt from the points
+// when the file is read instead.
+const storedRollupLevel = 1
+
+// Rollups are pre-aggregated buckets of every point at multiple resolutions, a consumer which only needs an
+// overview of many points (e.g. drawing far more points than there are terminal columns) can read the
+// buckets instead.
+type Rollups struct {
+	// Levels are the buckets for each of [RollupResolutions] in time order.
+	Levels [][]Rollup
+}
+
+func NewRollups() *Rollups {
+	r := &Rollups{Levels: make([][]Rollup, len(RollupResolutions))}
+	for level := range r.Levels {
+		r.Levels[level] = []Rollup{}
+	}
+	return r
+}
+
+// AddPoint adds the point to the bucket which contains it at every resolution, points don't need to be added
+// in timestamp order.
+func (r *Rollups) AddPoint(p ping.PingDataPoint) {
+	// Strip the monotonic clock reading, it's meaningless once written to file.
+	p.Timestamp = p.Timestamp.Round(0)
+	for level := range RollupResolutions {
+		r.addPointAt(level, p)
+	}
+}
+
+func (r *Rollups) addPointAt(level int, p ping.PingDataPoint) {
+	begin := p.Timestamp.Truncate(RollupResolutions[level])
+	buckets := r.Levels[level]
+	last := len(buckets) - 1
+	if last >= 0 && buckets[last].Begin.Equal(begin) {
+		// Fast path, points are almost always added in order
+		buckets[last].AddPoint(p)
+		return
+	}
+	i, found := slices.BinarySearchFunc(buckets, begin, func(b Rollup, t time.Time) int { return b.Begin.Compare(t) })
+	if !found {
+		buckets = slices.Insert(buckets, i, Rollup{Begin: begin})
+	}
+	buckets[i].AddPoint(p)
+	r.Levels[level] = buckets
+}
+
+// rebuild fills every level which wasn't read from file (see [storedRollupLevel]) from the points of [d].
+func (r *Rollups) rebuild(d *Data) {
+	for level := range r.Levels {
+		if r.Levels[level] != nil {
+			continue
+		}
+		r.Levels[level] = []Rollup{}
+		for i := range d.TotalCount {
+			p := d.Get(i)
+			p.Timestamp = p.Timestamp.Round(0)
+			r.addPointAt(level, p)
+		}
+	}
+}
+
+// Within returns the buckets of the [level] which contain any part of the [span].
+func (r *Rollups) Within(level int, span *TimeSpan) []Rollup {
+	buckets := r.Levels[level]
+	begin := span.Begin.Truncate(RollupResolutions[level])
+	start, _ := slices.BinarySearchFunc(buckets, begin, func(b Rollup, t time.Time) int { return b.Begin.Compare(t) })
+	end, found := slices.BinarySearchFunc(buckets, span.End, func(b Rollup, t time.Time) int { return b.Begin.Compare(t) })
+	if found {
+		end++
+	}
+	return buckets[start:end]
+}
+
+// Rollup is the aggregate of every point with a timestamp in [Begin, Begin+resolution).
+type Rollup struct {
+	Begin time.Time
+	// Fastest and Slowest are the good points with the smallest and largest duration.
+	Fastest, Slowest ping.PingDataPoint
+	// FirstDropped and LastDropped are the earliest and latest dropped points, they're only set if
+	// [DroppedCount] is non-zero.
+	FirstDropped, LastDropped ping.PingDataPoint
+	// Total is the sum of all the good durations.
+	Total        time.Duration
+	GoodCount    uint64
+	DroppedCount uint64
+}
+
+func (r *Rollup) AddPoint(p ping.PingDataPoint) {
+	if p.Dropped() {
+		if r.DroppedCount == 0 || p.Timestamp.Before(r.FirstDropped.Timestamp) {
+			r.FirstDropped = p
+		}
+		if r.DroppedCount == 0 || p.Timestamp.After(r.LastDropped.Timestamp) {
+			r.LastDropped = p
+		}
+		r.DroppedCount++
+		return
+	}
+	if r.GoodCount == 0 || p.Duration < r.Fastest.Duration {
+		r.Fastest = p
+	}
+	if r.GoodCount == 0 || p.Duration > r.Slowest.Duration {
+		r.Slowest = p
+	}
+	r.Total += p.Duration
+	r.GoodCount++
+}
+
+// Mean is the mean duration of the good points, 0 if there are none.
+func (r *Rollup) Mean() time.Duration {
+	if r.GoodCount == 0 {
+		return 0
+	}
+	//nolint:gosec
+	// G115 the count of points in a single bucket won't reach 2^63.
+	return r.Total / time.Duration(r.GoodCount)
+}
+
+// PacketLoss is the ratio of dropped points to all points in the bucket.
+func (r *Rollup) PacketLoss() float64 {
+	return float64(r.DroppedCount) / float64(r.DroppedCount+r.GoodCount)
+}
+
+// Extremes are the points which best represent this bucket when drawn, in timestamp order. Each point is a
+// real point which was added to the bucket.
+func (r *Rollup) Extremes() []ping.PingDataPoint {
+	ret := make([]ping.PingDataPoint, 0, 4)
+	if r.GoodCount > 0 {
+		ret = append(ret, r.Fastest)
+		if r.GoodCount > 1 && !r.Slowest.Timestamp.Equal(r.Fastest.Timestamp) {
+			ret = append(ret, r.Slowest)
+		}
+	}
+	if r.DroppedCount > 0 {
+		ret = append(ret, r.FirstDropped)
+		if r.DroppedCount > 1 && !r.LastDropped.Timestamp.Equal(r.FirstDropped.Timestamp) {
+			ret = append(ret, r.LastDropped)
+		}
+	}
+	slices.SortFunc(ret, func(a, b ping.PingDataPoint) int { return a.Timestamp.Compare(b.Timestamp) })
+	return ret
+}
@@ -0,0 +1,121 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRollups(t *testing.T) {
+	t.Parallel()
+	r := data.NewRollups()
+	at := func(d time.Duration) time.Time { return origin.Add(d) }
+	// Deliberately out of order
+	r.AddPoint(ping.PingDataPoint{Duration: 5 * time.Millisecond, Timestamp: at(61 * time.Second)})
+	r.AddPoint(ping.PingDataPoint{Duration: 3 * time.Millisecond, Timestamp: at(500 * time.Millisecond)})
+	r.AddPoint(ping.PingDataPoint{Duration: 7 * time.Millisecond, Timestamp: at(100 * time.Millisecond)})
+	r.AddPoint(ping.PingDataPoint{DropReason: ping.TestDrop, Timestamp: at(900 * time.Millisecond)})
+	r.AddPoint(ping.PingDataPoint{DropReason: ping.TestDrop, Timestamp: at(2 * time.Hour)})
+
+	seconds, minutes, hours := r.Levels[0], r.Levels[1], r.Levels[2]
+	assert.Assert(t, is.Len(seconds, 3))
+	assert.Assert(t, is.Len(minutes, 3))
+	assert.Assert(t, is.Len(hours, 2))
+
+	first := seconds[0]
+	assert.Check(t, first.Begin.Equal(origin))
+	assert.Check(t, is.Equal(first.Fastest.Duration, 3*time.Millisecond))
+	assert.Check(t, is.Equal(first.Slowest.Duration, 7*time.Millisecond))
+	assert.Check(t, is.Equal(first.Mean(), 5*time.Millisecond))
+	assert.Check(t, is.Equal(first.PacketLoss(), 1.0/3.0))
+	assert.Check(t, is.DeepEqual(first.Extremes(), []ping.PingDataPoint{
+		{Duration: 7 * time.Millisecond, Timestamp: at(100 * time.Millisecond)},
+		{Duration: 3 * time.Millisecond, Timestamp: at(500 * time.Millisecond)},
+		{DropReason: ping.TestDrop, Timestamp: at(900 * time.Millisecond)},
+	}))
+	assert.Check(t, is.Equal(minutes[0].GoodCount, uint64(2)))
+	assert.Check(t, is.Equal(hours[0].GoodCount, uint64(3)))
+	assert.Check(t, is.Equal(hours[1].DroppedCount, uint64(1)))
+
+	within := r.Within(1, &data.TimeSpan{Begin: at(30 * time.Second), End: at(time.Hour)})
+	assert.Assert(t, is.Len(within, 2))
+	assert.Check(t, within[1].Begin.Equal(at(time.Minute)))
+}
+
+func TestCompactRollups(t *testing.T) {
+	t.Parallel()
+	testRollups := data.NewRollups()
+	for _, p := range makeLargePings() {
+		testRollups.AddPoint(p.Data)
+	}
+	var b bytes.Buffer
+	assert.NilError(t, testRollups.AsCompact(&b))
+	read := &data.Rollups{}
+	_, err := read.FromCompact(b.Bytes())
+	assert.NilError(t, err)
+	// The seconds are only rebuilt when a whole [data.Data] is read
+	assert.Check(t, is.Nil(read.Levels[0]))
+	assert.Check(t, is.DeepEqual(read.Levels[1:], testRollups.Levels[1:]))
+}
+
+func TestRollupsMigrated(t *testing.T) {
+	t.Parallel()
+	d := readTestFile(t, "testdata/input/medium-hour-gaps.pings")
+	expected := data.NewRollups()
+	for i := range d.TotalCount {
+		expected.AddPoint(d.Get(i))
+	}
+	assert.Check(t, is.DeepEqual(d.Rollups, expected))
+	assert.Check(t, len(d.Rollups.Levels[2]) > 1)
+}
+
+func TestDataRollups(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	addTestPoint(d, 1, 5*time.Millisecond, net.IPv4bcast)
+	addTestPoint(d, 1, 0, net.IPv4bcast)
+	addTestPoint(d, 90, 9*time.Millisecond, net.IPv4bcast)
+	assert.Check(t, is.Len(d.Rollups.Levels[1], 2))
+	testCompacter(t, d, &data.Data{})
+
+	large := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		large.AddPoint(p)
+	}
+	testCompacter(t, large, &data.Data{})
+}
+
+func TestDownsample(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		d.AddPoint(p)
+	}
+	d.AddAnnotation(data.Annotation{Timestamp: d.Get(0).Timestamp, Text: "kept"})
+	downsampled := d.Downsample(time.Minute)
+
+	// The same points as the rollups would draw
+	expected := []ping.PingDataPoint{}
+	for _, r := range d.Rollups.Levels[1] {
+		expected = append(expected, r.Extremes()...)
+	}
+	actual := []ping.PingDataPoint{}
+	for i := range downsampled.TotalCount {
+		actual = append(actual, downsampled.Get(i))
+	}
+	assert.Check(t, is.DeepEqual(actual, expected))
+	assert.Check(t, downsampled.TotalCount < d.TotalCount)
+	assert.Check(t, is.DeepEqual(downsampled.Annotations, d.Annotations))
+}
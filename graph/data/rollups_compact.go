@@ -0,0 +1,95 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+func (r *Rollups) AsCompact(w io.Writer) error {
+	ret := make([]byte, r.byteLen())
+	_ = r.write(ret)
+	_, err := w.Write(ret)
+	return err
+}
+
+func (r *Rollups) FromCompact(input []byte) (int, error) {
+	i, err := readID(input, RollupsID)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Rollups")
+	}
+	levelsLen := 0
+	i += readLen(input[i:], &levelsLen)
+	// Levels which weren't stored are left nil, see [Rollups.rebuild].
+	r.Levels = make([][]Rollup, len(RollupResolutions))
+	previous := -1
+	for range levelsLen {
+		var resolution time.Duration
+		i += readDuration(input[i:], &resolution)
+		level := slices.Index(RollupResolutions, resolution)
+		if level <= previous {
+			return i, errors.Errorf("while reading compact Rollups, unexpected resolution %s", resolution)
+		}
+		previous = level
+		bucketsLen := 0
+		i += readLen(input[i:], &bucketsLen)
+		r.Levels[level] = make([]Rollup, bucketsLen)
+		for index := range r.Levels[level] {
+			i += readRollup(input[i:], &r.Levels[level][index])
+		}
+	}
+	return i, nil
+}
+
+func (r *Rollups) write(ret []byte) int {
+	i := writeByte(ret, RollupsID)
+	i += writeLen(ret[i:], r.Levels[storedRollupLevel:])
+	for level, buckets := range r.Levels[storedRollupLevel:] {
+		i += writeDuration(ret[i:], RollupResolutions[storedRollupLevel+level])
+		i += writeLen(ret[i:], buckets)
+		for _, bucket := range buckets {
+			i += writeRollup(ret[i:], bucket)
+		}
+	}
+	return i
+}
+
+func (r *Rollups) byteLen() int {
+	i := idLen + int64Len
+	for _, buckets := range r.Levels[storedRollupLevel:] {
+		i += timeDurationLen + sliceLenFixed(buckets, rollupLen)
+	}
+	return i
+}
+
+func writeRollup(b []byte, r Rollup) int {
+	i := writeTime(b, r.Begin)
+	i += writePingDataPoint(b[i:], r.Fastest)
+	i += writePingDataPoint(b[i:], r.Slowest)
+	i += writePingDataPoint(b[i:], r.FirstDropped)
+	i += writePingDataPoint(b[i:], r.LastDropped)
+	i += writeDuration(b[i:], r.Total)
+	i += writeUint64(b[i:], r.GoodCount)
+	i += writeUint64(b[i:], r.DroppedCount)
+	return i
+}
+
+func readRollup(b []byte, r *Rollup) int {
+	i := readTime(b, &r.Begin)
+	i += readPingDataPoint(b[i:], &r.Fastest)
+	i += readPingDataPoint(b[i:], &r.Slowest)
+	i += readPingDataPoint(b[i:], &r.FirstDropped)
+	i += readPingDataPoint(b[i:], &r.LastDropped)
+	i += readDuration(b[i:], &r.Total)
+	i += readUint64(b[i:], &r.GoodCount)
+	i += readUint64(b[i:], &r.DroppedCount)
+	return i
+}
@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -23,7 +23,7 @@ func (r *Run) fromCompact(input []byte, version version) (int, error) {
 		i := readUint64(input, &r.Longest)
 		i += readUint64(input[i:], &r.Current)
 		return i, nil
-	case currentDataVersion:
+	case noAnnotations, noRollups, noQuantiles, noJitter, secondRollups, wideSketches, currentDataVersion:
 		i := readInt64(input, &r.LongestIndexEnd)
 		i += readUint64(input[i:], &r.Longest)
 		i += readUint64(input[i:], &r.Current)
@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -8,10 +8,12 @@ package data
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 	"math"
 	"time"
 
+	"github.com/Lexer747/acci-ping/ping"
 	"github.com/Lexer747/acci-ping/utils/errors"
 )
 
@@ -19,11 +21,16 @@ import (
 // into [Data] (use a [bytes.Buffer]). This byte stream should've been encoded with [Data.AsCompact],
 // otherwise an error will occur. Note no checksums are in this data format so data-integrity is not
 // guaranteed and this may mean that an unlikely file might trick this decoder.
+//
+// A file which holds many targets can't be read into a single [Data], [ErrMultipleTargets] is returned.
 func ReadData(r io.Reader) (*Data, error) {
 	toReadFrom, err := io.ReadAll(r)
 	if err != nil {
 		return nil, errors.Wrap(err, "While reading into Data{}")
 	}
+	if len(toReadFrom) > 0 && Identifier(toReadFrom[0]) == TargetsID {
+		return nil, ErrMultipleTargets
+	}
 	d := &Data{}
 	_, err = d.FromCompact(toReadFrom)
 	if err != nil {
@@ -48,14 +55,17 @@ type Compact interface {
 //
 // truly re-usable (within the context of serialising) compacting functions should be here in this file.
 
+var _ Compact = (&Annotation{})  // annotation_compact.go
 var _ Compact = (&Block{})       // block_compact.go
 var _ Compact = (&DataIndexes{}) // data_indexes_compact.go
 var _ Compact = (&Data{})        // data_compact.go
 var _ Compact = (&Header{})      // header_compact.go
 var _ Compact = (&Network{})     // network_compact.go
+var _ Compact = (&Rollups{})     // rollups_compact.go
 var _ Compact = (&Runs{})        // runs_compact.go
 var _ Compact = (&Run{})         // run_compact.go
 var _ Compact = (&Stats{})       // stats_compact.go
+var _ Compact = (&Targets{})     // targets_compact.go
 var _ Compact = (&TimeSpan{})    // timespan_compact.go
 
 // Compacting implementations and list ends.
@@ -65,17 +75,47 @@ type Identifier byte
 const (
 	_ Identifier = 0
 
-	TimeSpanID Identifier = 1
-	StatsID    Identifier = 2
-	BlockID    Identifier = 3
-	HeaderID   Identifier = 4
-	DataID     Identifier = 5
-	NetworkID  Identifier = 6
-	RunsID     Identifier = 7
+	TimeSpanID   Identifier = 1
+	StatsID      Identifier = 2
+	BlockID      Identifier = 3
+	HeaderID     Identifier = 4
+	DataID       Identifier = 5
+	NetworkID    Identifier = 6
+	RunsID       Identifier = 7
+	AnnotationID Identifier = 8
+	RollupsID    Identifier = 9
+	TargetsID    Identifier = 10
 
 	_ Identifier = 0xff
 )
 
+func (id Identifier) String() string {
+	switch id {
+	case TimeSpanID:
+		return "TimeSpan"
+	case StatsID:
+		return "Stats"
+	case BlockID:
+		return "Block"
+	case HeaderID:
+		return "Header"
+	case DataID:
+		return "Data"
+	case NetworkID:
+		return "Network"
+	case RunsID:
+		return "Runs"
+	case AnnotationID:
+		return "Annotation"
+	case RollupsID:
+		return "Rollups"
+	case TargetsID:
+		return "Targets"
+	default:
+		return fmt.Sprintf("Identifier(%d)", byte(id))
+	}
+}
+
 // phasedWrite is generally used by a compacting implementor to indicate that the data must be written in two
 // phases, each phase is of this type. This is useful for types which have dynamic sizes e.g.
 // [Network.FromCompact] [Network.AsCompact] which will write all the sizes of it's slices in it's first
@@ -83,6 +123,46 @@ const (
 // simple and efficient as it can read all the sizes before consuming all the bytes.
 type phasedWrite = func(ret []byte) int
 
+// Note version"5" here corresponds to the literal 5 of [version], rollups are appended after all the version 4
+// data so that the rest of the layout is unchanged. Versions 6 and 7 have the same layout, only the [Stats]
+// of each header are longer. From version 8 the finest level of the [Rollups] isn't stored and from version 9
+// only the sketch buckets in use are.
+func (d *Data) readVersion5(i int, input []byte) (int, error) {
+	i, err := d.readVersion4(i, input)
+	if err != nil {
+		return i, err
+	}
+	if d.Rollups == nil {
+		d.Rollups = &Rollups{}
+	}
+	n, err := d.Rollups.FromCompact(input[i:])
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Data")
+	}
+	d.Rollups.rebuild(d)
+	return i + n, nil
+}
+
+// Note version"4" here corresponds to the literal 4 of [version], annotations are appended after all the
+// version 2 data so that the rest of the layout is unchanged.
+func (d *Data) readVersion4(i int, input []byte) (int, error) {
+	i, err := d.readVersion2(i, input)
+	if err != nil {
+		return i, err
+	}
+	annotationsLen := 0
+	i += readLen(input[i:], &annotationsLen)
+	d.Annotations = make([]Annotation, annotationsLen)
+	for index := range d.Annotations {
+		n, err := d.Annotations[index].FromCompact(input[i:])
+		if err != nil {
+			return i, errors.Wrap(err, "while reading compact Data")
+		}
+		i += n
+	}
+	return i, nil
+}
+
 // Note version"2" here corresponds to the literal 2 of [version], every time a new version is added a
 // corresponding function should be created.
 func (d *Data) readVersion2(i int, input []byte) (int, error) {
@@ -96,23 +176,19 @@ func (d *Data) readVersion2(i int, input []byte) (int, error) {
 		return i, errors.Wrap(err, "while reading compact Data")
 	}
 	i += n
-	// drop block header len, we know it's fixed until new versions are introduced
+	// drop block header len, each header is read by itself and from [wideSketches] on they vary in length
 	i += readInt(input[i:], &n)
 	blockLen := 0
 	i += readLen(input[i:], &blockLen)
-	d.Blocks = make([]*Block, blockLen)
-	blockSizes := make([]*int, blockLen)
-	blockReads := make([]blockRead, blockLen)
+	d.BlockHeaders = make([]*Header, blockLen)
+	blockSizes := make([]int, blockLen)
 	for index := range blockLen {
-		d.Blocks[index] = &Block{}
-		blockSizes[index] = new(int)
-		header, data := d.Blocks[index].twoPhaseRead()
-		n, err := header(input[i:], blockSizes[index])
+		d.BlockHeaders[index] = &Header{}
+		n, err := readBlockHeader(input[i:], d.BlockHeaders[index], &blockSizes[index], d.PingsMeta)
 		if err != nil {
 			return i, errors.Wrap(err, "while reading compact Data")
 		}
 		i += n
-		blockReads[index] = data
 	}
 	URLLen := 0
 	i += readLen(input[i:], &URLLen)
@@ -124,26 +200,21 @@ func (d *Data) readVersion2(i int, input []byte) (int, error) {
 		return i, errors.Wrap(err, "while reading compact Data")
 	}
 	i += n
-	n, err = d.Header.FromCompact(input[i:])
+	n, err = d.Header.fromCompact(input[i:], d.PingsMeta)
 	if err != nil {
 		return i, errors.Wrap(err, "while reading compact Data")
 	}
 	i += n
 
 	// Phase 2 read the variable sized data
-	d.InsertOrder = make([]DataIndexes, insertOrderLen)
-	for index := range d.InsertOrder {
-		insert := &d.InsertOrder[index]
-		n, err := insert.FromCompact(input[i:])
-		if err != nil {
-			return i, errors.Wrap(err, "while reading compact Data")
-		}
-		i += n
-	}
+	insertOrder := input[i : i+insertOrderLen*dataIndexesLen]
+	i += len(insertOrder)
 	i += networkDataReader(input[i:], IPsLen, blockIndexesLen)
-	for index, blockData := range blockReads {
-		i += blockData(input[i:], *blockSizes[index])
+	n, err = d.readPoints(insertOrder, input[i:], blockSizes)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Data")
 	}
+	i += n
 	i += readString(input[i:], &d.URL, URLLen)
 	return i, nil
 }
@@ -165,46 +236,65 @@ func (d *Data) readVersion1(i int, input []byte) (int, error) {
 	i += readInt(input[i:], &n)
 	blockLen := 0
 	i += readLen(input[i:], &blockLen)
-	d.Blocks = make([]*Block, blockLen)
-	blockSizes := make([]*int, blockLen)
-	blockReads := make([]blockRead, blockLen)
+	d.BlockHeaders = make([]*Header, blockLen)
+	blockSizes := make([]int, blockLen)
 	for index := range blockLen {
-		d.Blocks[index] = &Block{}
-		blockSizes[index] = new(int)
-		header, data := d.Blocks[index].twoPhaseRead()
-		n, err := header(input[i:], blockSizes[index])
+		d.BlockHeaders[index] = &Header{}
+		n, err := readBlockHeader(input[i:], d.BlockHeaders[index], &blockSizes[index], d.PingsMeta)
 		if err != nil {
 			return i, errors.Wrap(err, "while reading compact Data")
 		}
 		i += n
-		blockReads[index] = data
 	}
 	URLLen := 0
 	i += readLen(input[i:], &URLLen)
-	n, err = d.Header.FromCompact(input[i:])
+	n, err = d.Header.fromCompact(input[i:], d.PingsMeta)
 	if err != nil {
 		return i, errors.Wrap(err, "while reading compact Data")
 	}
 	i += n
 
 	// Phase 2 read the variable sized data
-	d.InsertOrder = make([]DataIndexes, insertOrderLen)
-	for index := range d.InsertOrder {
-		insert := &d.InsertOrder[index]
-		n, err := insert.FromCompact(input[i:])
-		if err != nil {
-			return i, errors.Wrap(err, "while reading compact Data")
-		}
-		i += n
-	}
+	insertOrder := input[i : i+insertOrderLen*dataIndexesLen]
+	i += len(insertOrder)
 	i += networkDataReader(input[i:], IPsLen, blockIndexesLen)
-	for index, blockData := range blockReads {
-		i += blockData(input[i:], *blockSizes[index])
+	n, err = d.readPoints(insertOrder, input[i:], blockSizes)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Data")
 	}
+	i += n
 	i += readString(input[i:], &d.URL, URLLen)
 	return i, nil
 }
 
+// readPoints fills the columns of [Data] in insert order, [input] starts at the points of the first block and
+// each block is [blockSizes] points long.
+func (d *Data) readPoints(insertOrder, input []byte, blockSizes []int) (int, error) {
+	blockOffsets := make([]int, len(blockSizes))
+	i := 0
+	for index, blockSize := range blockSizes {
+		blockOffsets[index] = i
+		i += blockSize * pingDataPointLen
+	}
+	if len(input) < i {
+		return 0, errors.Errorf("Cannot read points, not enough bytes")
+	}
+	d.points.grow(len(insertOrder) / dataIndexesLen)
+	for len(insertOrder) > 0 {
+		var insert DataIndexes
+		n, _ := insert.FromCompact(insertOrder)
+		insertOrder = insertOrder[n:]
+		if insert.BlockIndex < 0 || insert.BlockIndex >= len(blockSizes) ||
+			insert.RawIndex < 0 || insert.RawIndex >= blockSizes[insert.BlockIndex] {
+			return 0, errors.Errorf("Unexpected index %+v", insert)
+		}
+		var p ping.PingDataPoint
+		_ = readPingDataPoint(input[blockOffsets[insert.BlockIndex]+insert.RawIndex*pingDataPointLen:], &p)
+		d.points.add(p, insert.BlockIndex)
+	}
+	return i, nil
+}
+
 // for internal details we want a few extra methods from all [Compact] things, which provide convenience in
 // the [write] function which is better suited for a parent of a child [Compact] compared to
 // [Compact.FromCompact].
@@ -231,14 +321,51 @@ const (
 	netIPLen        = 16 // Always store in ipv6 form
 
 	timeSpanLen      = idLen + 2*timeLen + timeDurationLen
-	statsLen         = idLen + 2*timeDurationLen + 4*float64Len + 2*uint64Len
-	headerLen        = idLen + timeSpanLen + statsLen
+	jitterLen        = 2*float64Len + 2*timeDurationLen + uint64Len
 	pingDataPointLen = timeDurationLen + timeLen + 1
 	dataIndexesLen   = intLen + intLen
 	runLen           = int64Len + uint64Len + uint64Len
 	runsLen          = idLen + runLen + runLen
+	rollupLen        = timeLen + 4*pingDataPointLen + timeDurationLen + 2*uint64Len
+
+	// noQuantilesStatsLen, noJitterStatsLen and wideStatsLen are the lengths of [Stats] before [noQuantiles],
+	// [noJitter] and [wideSketches].
+	noQuantilesStatsLen = idLen + 2*timeDurationLen + 4*float64Len + 2*uint64Len
+	noJitterStatsLen    = noQuantilesStatsLen + int64Len + sketchBuckets*uint64Len
+	wideStatsLen        = noJitterStatsLen + jitterLen
+	// statsPrefixLen is the length of [Stats] up to and including the count of the sketch buckets stored.
+	statsPrefixLen = noQuantilesStatsLen + int64Len + 2*intLen
 )
 
+// statsLenOf is the length of [Stats] written by [version]. Before [wideSketches] every sketch bucket was
+// stored, after only [buckets] are (see [storedBuckets]).
+func statsLenOf(version version, buckets int) int {
+	switch {
+	case version <= noQuantiles:
+		return noQuantilesStatsLen
+	case version <= noJitter:
+		return noJitterStatsLen
+	case version <= wideSketches:
+		return wideStatsLen
+	default:
+		return statsPrefixLen + buckets*uint64Len + jitterLen
+	}
+}
+
+// storedBuckets reads how many sketch buckets are stored by the [Stats] at the start of [b], which must be
+// at least [statsPrefixLen] long. It's clamped so that a corrupt count can't make a reader peek huge amounts,
+// [Stats.fromCompact] reports it instead.
+func storedBuckets(b []byte) int {
+	buckets := 0
+	_ = readLen(b[statsPrefixLen-intLen:], &buckets)
+	return min(max(buckets, 0), sketchBuckets)
+}
+
+// headerLenOf is the length of a [Header] written by [version], see [statsLenOf].
+func headerLenOf(version version, buckets int) int {
+	return idLen + timeSpanLen + statsLenOf(version, buckets)
+}
+
 // sliceLenCompact works out the dynamic size for all items in a slice.
 func sliceLenCompact[S ~[]T, T lenCompact](slice S) int {
 	i := int64Len // 1 int64 to encode the length
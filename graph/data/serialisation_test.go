@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -150,6 +150,27 @@ func TestCompactData(t *testing.T) {
 	testCompacter(t, testData, &data.Data{})
 }
 
+func TestCompactAnnotation(t *testing.T) {
+	t.Parallel()
+	testAnnotation := &data.Annotation{Timestamp: time.UnixMilli(1000), Text: "router rebooted"}
+	testCompacter(t, testAnnotation, &data.Annotation{})
+}
+
+func TestCompactDataWithAnnotations(t *testing.T) {
+	t.Parallel()
+	testData := data.NewData("www.google.com")
+	testData.AddPoint(ping.PingResults{
+		Data: ping.PingDataPoint{Duration: 1, Timestamp: time.UnixMilli(1000)},
+		IP:   net.IPv4bcast,
+	})
+	testData.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(3000), Text: "moved laptop to kitchen"})
+	testData.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(1500), Text: ""})
+	testData.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(2000), Text: "router rebooted ✓"})
+	assert.Equal(t, testData.Annotations[0].Timestamp, time.UnixMilli(1500))
+	assert.Equal(t, testData.Annotations[2].Text, "moved laptop to kitchen")
+	testCompacter(t, testData, &data.Data{})
+}
+
 func TestCompactLargeData(t *testing.T) {
 	t.Parallel()
 	testData := data.NewData("www.google.com")
@@ -0,0 +1,158 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"iter"
+	"math"
+	"time"
+)
+
+// SketchAccuracy is the relative error of any quantile returned by [Stats.Quantile], as long as the values
+// span less than [sketchBuckets] buckets (roughly 1µs to 28ms, or 1ms to 28s).
+const SketchAccuracy = 0.02
+
+const sketchBuckets = 256
+
+var (
+	sketchGamma    = (1 + SketchAccuracy) / (1 - SketchAccuracy)
+	sketchLogGamma = math.Log(sketchGamma)
+)
+
+// sketch is a DDSketch (https://arxiv.org/abs/1908.10693) of durations, a mergeable quantile sketch where
+// each bucket holds every value within [SketchAccuracy] of the bucket's value. It's fixed size so that the
+// [Stats] stay small: only [sketchBuckets] contiguous buckets are kept (and only those in use are written to
+// file) and when a value doesn't fit the lowest buckets are collapsed together. Latency is long-tailed and
+// it's the tail which matters, so the fastest points are the ones which lose accuracy.
+type sketch struct {
+	counts [sketchBuckets]uint64
+	// offset is the bucket index of counts[0].
+	offset int64
+	count  uint64
+}
+
+func sketchIndex(d time.Duration) int64 {
+	// Values less than a nanosecond only happen when subtracting timestamps, treat them as the smallest
+	// value.
+	value := max(float64(d), 1)
+	return int64(math.Ceil(math.Log(value) / sketchLogGamma))
+}
+
+// sketchValue is the value of the bucket [index], it's within [SketchAccuracy] of every value in the bucket.
+func sketchValue(index int64) time.Duration {
+	return time.Duration(2 * math.Pow(sketchGamma, float64(index)) / (sketchGamma + 1))
+}
+
+func (s *sketch) add(d time.Duration) {
+	index := sketchIndex(d)
+	if s.count == 0 {
+		// Leave space both sides of the first value
+		s.offset = index - sketchBuckets/2
+	}
+	switch {
+	case index >= s.offset+sketchBuckets:
+		s.shift(index - sketchBuckets + 1)
+	case index < s.offset:
+		// Move the window down as far as the largest value allows, anything still below is collapsed.
+		s.shift(max(index, s.highest()-sketchBuckets+1))
+	}
+	s.counts[max(index-s.offset, 0)]++
+	s.count++
+}
+
+// shift moves the window to start at [offset], any buckets below the new start are collapsed into the
+// lowest bucket. The highest bucket in use must still fit.
+func (s *sketch) shift(offset int64) {
+	if offset == s.offset {
+		return
+	}
+	var shifted [sketchBuckets]uint64
+	for i, count := range s.counts {
+		if count > 0 {
+			shifted[max(s.offset+int64(i)-offset, 0)] += count
+		}
+	}
+	s.counts = shifted
+	s.offset = offset
+}
+
+// lowest and highest are the indexes of the first and last bucket in use, only valid if the sketch isn't
+// empty.
+func (s *sketch) lowest() int64 {
+	for i, count := range s.counts {
+		if count > 0 {
+			return s.offset + int64(i)
+		}
+	}
+	return s.offset
+}
+
+func (s *sketch) highest() int64 {
+	for i := len(s.counts) - 1; i >= 0; i-- {
+		if s.counts[i] > 0 {
+			return s.offset + int64(i)
+		}
+	}
+	return s.offset
+}
+
+// used is the position within counts of the first bucket in use and the number of buckets up to and
+// including the last in use, both are zero if the sketch is empty.
+func (s *sketch) used() (int, int) {
+	if s.count == 0 {
+		return 0, 0
+	}
+	first := int(s.lowest() - s.offset)
+	return first, int(s.highest()-s.offset) - first + 1
+}
+
+func (s *sketch) merge(other *sketch) sketch {
+	if s.count == 0 {
+		return *other
+	}
+	if other.count == 0 {
+		return *s
+	}
+	high := max(s.highest(), other.highest())
+	ret := sketch{offset: max(min(s.lowest(), other.lowest()), high-sketchBuckets+1)}
+	for _, from := range []*sketch{s, other} {
+		for i, count := range from.counts {
+			if count > 0 {
+				ret.counts[max(from.offset+int64(i)-ret.offset, 0)] += count
+			}
+		}
+		ret.count += from.count
+	}
+	return ret
+}
+
+// buckets yields the index and count of every bucket in use, lowest first.
+func (s *sketch) buckets() iter.Seq2[int64, uint64] {
+	return func(yield func(int64, uint64) bool) {
+		for i, count := range s.counts {
+			if count > 0 && !yield(s.offset+int64(i), count) {
+				return
+			}
+		}
+	}
+}
+
+// quantile of an empty sketch is 0.
+func (s *sketch) quantile(q float64) time.Duration {
+	if s.count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(s.count-1))
+	seen := uint64(0)
+	for i, count := range s.counts {
+		seen += count
+		if seen > rank {
+			return sketchValue(s.offset + int64(i))
+		}
+	}
+	return sketchValue(s.highest())
+}
@@ -0,0 +1,144 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+var quantiles = []float64{0, 0.01, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999, 1}
+
+func TestQuantiles(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(1, 2))
+	// A long tail: mostly around 8ms with some spikes up to seconds.
+	values := make([]time.Duration, 10_000)
+	for i := range values {
+		values[i] = 8*time.Millisecond + time.Duration(r.ExpFloat64()*float64(time.Millisecond))
+		if r.IntN(100) == 0 {
+			values[i] += time.Duration(r.ExpFloat64() * float64(time.Second))
+		}
+	}
+	s := &data.Stats{}
+	s.AddPoints(values)
+	checkQuantiles(t, s, values)
+
+	t.Run("merged", func(t *testing.T) {
+		t.Parallel()
+		var merged *data.Stats
+		for chunk := range slices.Chunk(values, 1000) {
+			toMerge := &data.Stats{}
+			toMerge.AddPoints(chunk)
+			merged = merged.Merge(toMerge)
+		}
+		for _, q := range quantiles {
+			assert.Check(t, is.Equal(merged.Quantile(q), s.Quantile(q)), "q%v", q)
+		}
+	})
+}
+
+func TestQuantilesCollapse(t *testing.T) {
+	t.Parallel()
+	// Far more than the sketch can hold, the smallest values lose accuracy but the tail doesn't.
+	values := []time.Duration{}
+	for d := time.Duration(1); d < time.Minute; d *= 2 {
+		values = append(values, d, d, d)
+	}
+	s := &data.Stats{}
+	s.AddPoints(values)
+	slices.Sort(values)
+	for _, q := range []float64{0.75, 0.9, 0.99, 1} {
+		checkQuantile(t, s, values, q)
+	}
+	assert.Check(t, is.Equal(s.Quantile(0), time.Duration(1)))
+
+	// The other direction, a window which is moved down
+	s = &data.Stats{}
+	for _, v := range slices.Backward(values) {
+		s.AddPoint(v)
+	}
+	for _, q := range []float64{0.75, 0.9, 0.99, 1} {
+		checkQuantile(t, s, values, q)
+	}
+}
+
+func TestQuantilesEmpty(t *testing.T) {
+	t.Parallel()
+	s := &data.Stats{}
+	s.AddDroppedPacket()
+	assert.Check(t, !s.HasQuantiles())
+	assert.Check(t, is.Equal(s.Quantile(0.5), time.Duration(0)))
+	assert.Check(t, !s.Merge(&data.Stats{}).HasQuantiles())
+}
+
+func TestCompactQuantiles(t *testing.T) {
+	t.Parallel()
+	for _, values := range [][]time.Duration{
+		{},
+		{8 * time.Millisecond},
+		{8 * time.Millisecond, 9 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+		{time.Microsecond, time.Second},
+	} {
+		s := &data.Stats{}
+		s.AddPoints(values)
+		testCompacter(t, s, &data.Stats{})
+	}
+
+	// Only the buckets from the fastest to the slowest value are stored
+	var empty, single, spread bytes.Buffer
+	assert.NilError(t, (&data.Stats{}).AsCompact(&empty))
+	s := &data.Stats{}
+	s.AddPoint(8 * time.Millisecond)
+	assert.NilError(t, s.AsCompact(&single))
+	assert.Check(t, is.Equal(single.Len(), empty.Len()+8))
+	s.AddPoint(time.Second)
+	assert.NilError(t, s.AsCompact(&spread))
+	assert.Check(t, spread.Len() < empty.Len()+256*8)
+}
+
+func TestQuantilesMigrated(t *testing.T) {
+	t.Parallel()
+	d := readTestFile(t, filepath.Join("testdata", "input", "medium-minute-gaps.pings"))
+	values := []time.Duration{}
+	for i := range d.TotalCount {
+		if p := d.Get(i); !p.Dropped() {
+			values = append(values, p.Duration)
+		}
+	}
+	checkQuantiles(t, d.Header.Stats, values)
+	for _, header := range d.BlockHeaders {
+		assert.Check(t, header.Stats.HasQuantiles())
+	}
+}
+
+func checkQuantiles(t *testing.T, s *data.Stats, values []time.Duration) {
+	t.Helper()
+	values = slices.Sorted(slices.Values(values))
+	for _, q := range quantiles {
+		checkQuantile(t, s, values, q)
+	}
+}
+
+// checkQuantile checks the quantile is within the accuracy of the exact quantile of the sorted [values].
+func checkQuantile(t *testing.T, s *data.Stats, values []time.Duration, q float64) {
+	t.Helper()
+	expected := float64(values[int(q*float64(len(values)-1))])
+	actual := float64(s.Quantile(q))
+	msg := fmt.Sprintf("q%v: expected %s got %s", q, time.Duration(expected), time.Duration(actual))
+	assert.Check(t, math.Abs(actual-expected) <= expected*data.SketchAccuracy, msg)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 func (s *Stats) AsCompact(w io.Writer) error {
-	ret := make([]byte, statsLen)
+	ret := make([]byte, s.byteLen())
 	_ = s.write(ret)
 	_, err := w.Write(ret)
 	return err
@@ -29,10 +29,28 @@ func (s *Stats) write(ret []byte) int {
 	i += writeFloat64(ret[i:], s.StandardDeviation)
 	i += writeUint64(ret[i:], s.PacketsDropped)
 	i += writeFloat64(ret[i:], s.sumOfSquares)
+	i += writeInt64(ret[i:], s.quantiles.offset)
+	first, buckets := s.quantiles.used()
+	i += writeInt(ret[i:], first)
+	i += writeInt(ret[i:], buckets)
+	for _, count := range s.quantiles.counts[first : first+buckets] {
+		i += writeUint64(ret[i:], count)
+	}
+	i += writeFloat64(ret[i:], s.jitter.smoothed)
+	i += writeFloat64(ret[i:], s.jitter.sumOfDifferences)
+	i += writeDuration(ret[i:], s.jitter.first)
+	i += writeDuration(ret[i:], s.jitter.last)
+	i += writeUint64(ret[i:], s.jitter.count)
 	return i
 }
 
 func (s *Stats) FromCompact(input []byte) (int, error) {
+	return s.fromCompact(input, currentDataVersion)
+}
+
+// fromCompact reads the stats written by [version], files older than [noQuantiles] have no quantiles and
+// files older than [noJitter] have no jitter. Files older than [wideSketches] store every sketch bucket.
+func (s *Stats) fromCompact(input []byte, version version) (int, error) {
 	i, err := readID(input, StatsID)
 	if err != nil {
 		return i, errors.Wrap(err, "while reading compact Stats")
@@ -45,9 +63,36 @@ func (s *Stats) FromCompact(input []byte) (int, error) {
 	i += readFloat64(input[i:], &s.StandardDeviation)
 	i += readUint64(input[i:], &s.PacketsDropped)
 	i += readFloat64(input[i:], &s.sumOfSquares)
+	s.quantiles = sketch{}
+	if version <= noQuantiles {
+		return i, nil
+	}
+	i += readInt64(input[i:], &s.quantiles.offset)
+	first, buckets := 0, sketchBuckets
+	if version > wideSketches {
+		i += readInt(input[i:], &first)
+		i += readLen(input[i:], &buckets)
+		if first < 0 || buckets < 0 || first > sketchBuckets-buckets {
+			return i, errors.Errorf("while reading compact Stats, %d sketch buckets from %d don't fit", buckets, first)
+		}
+	}
+	for index := range s.quantiles.counts[first : first+buckets] {
+		i += readUint64(input[i:], &s.quantiles.counts[first+index])
+		s.quantiles.count += s.quantiles.counts[first+index]
+	}
+	s.jitter = jitter{}
+	if version <= noJitter {
+		return i, nil
+	}
+	i += readFloat64(input[i:], &s.jitter.smoothed)
+	i += readFloat64(input[i:], &s.jitter.sumOfDifferences)
+	i += readDuration(input[i:], &s.jitter.first)
+	i += readDuration(input[i:], &s.jitter.last)
+	i += readUint64(input[i:], &s.jitter.count)
 	return i, nil
 }
 
 func (s *Stats) byteLen() int {
-	return statsLen
+	_, buckets := s.quantiles.used()
+	return statsLenOf(currentDataVersion, buckets)
 }
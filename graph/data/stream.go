@@ -0,0 +1,403 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"slices"
+
+	"github.com/Lexer747/acci-ping/ping"
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// Stream is an incremental decoder of the bytes written by [Data.AsCompact], unlike [ReadData] the points are
+// not all read into memory. Instead everything but the points is decoded by [NewStream] and then each point
+// is decoded in insert order by [Stream.Next]. Use this for very large files when each point only needs to
+// be seen once.
+//
+// The fields mirror those of [Data] and should be treated as read only.
+type Stream struct {
+	Header  *Header
+	Network *Network
+	Runs    *Runs
+	URL     string
+	// BlockHeaders are the [Block.Header] of each block, see [Network.BlockIndexes] for which IP a block
+	// belongs to.
+	BlockHeaders []*Header
+	Annotations  []Annotation
+
+	ra          io.ReaderAt
+	insertOrder *bufio.Reader
+	blocks      []*bufio.Reader
+	// blockIPs is the IP of each block index
+	blockIPs []int
+	// blockRead is how many points have been read from each block
+	blockRead []int
+	// blockSizes is the number of points in each block
+	blockSizes []int
+	// blockOffsets is where the points of each block start
+	blockOffsets []int64
+	TotalCount   int64
+	read         int64
+	insertLen    int64
+	insertOffset int64
+	PingsMeta    version
+	// recomputeRuns, recomputeQuantiles and recomputeJitter are set while migrating, see [Stream.migrate].
+	recomputeRuns      bool
+	recomputeQuantiles bool
+	recomputeJitter    bool
+}
+
+// NewStream decodes everything but the points of a '.pings' file. The memory used is bounded if [r] is also
+// an [io.ReaderAt] and [io.Seeker] (e.g. an [os.File]), as the points are stored grouped by IP each group is
+// read with its own cursor. Otherwise the whole input is read into memory first. A file which holds many
+// targets returns [ErrMultipleTargets], see [NewTargetStreams].
+func NewStream(r io.Reader) (*Stream, error) {
+	ra, size, err := asReaderAt(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "while streaming Data")
+	}
+	s := &Stream{Header: &Header{}, Network: &Network{}}
+	if err := s.readMeta(ra, size); err != nil {
+		return nil, errors.Wrap(err, "while streaming Data")
+	}
+	return s, nil
+}
+
+// Next returns the next point in insert order, once every point has been read it returns [io.EOF].
+func (s *Stream) Next() (ping.PingResults, error) {
+	if s.read == s.insertLen {
+		return ping.PingResults{}, io.EOF
+	}
+	var buf [max(dataIndexesLen, pingDataPointLen)]byte
+	if _, err := io.ReadFull(s.insertOrder, buf[:dataIndexesLen]); err != nil {
+		return ping.PingResults{}, errors.Wrapf(unexpected(err), "while streaming point %d", s.read)
+	}
+	var index DataIndexes
+	_, _ = index.FromCompact(buf[:])
+	if index.BlockIndex < 0 || index.BlockIndex >= len(s.blocks) || index.RawIndex != s.blockRead[index.BlockIndex] {
+		// Points are only ever appended to a block so the raw indexes of a block are always read in order.
+		return ping.PingResults{}, errors.Errorf("while streaming point %d, unexpected index %+v", s.read, index)
+	}
+	if _, err := io.ReadFull(s.blocks[index.BlockIndex], buf[:pingDataPointLen]); err != nil {
+		return ping.PingResults{}, errors.Wrapf(unexpected(err), "while streaming point %d", s.read)
+	}
+	var p ping.PingDataPoint
+	_ = readPingDataPoint(buf[:], &p)
+	if s.recomputeRuns {
+		s.Runs.AddPoint(s.read, p)
+	}
+	if s.recomputeQuantiles && !p.Dropped() {
+		s.Header.Stats.quantiles.add(p.Duration)
+		s.BlockHeaders[index.BlockIndex].Stats.quantiles.add(p.Duration)
+	}
+	if s.recomputeJitter && !p.Dropped() {
+		s.Header.Stats.jitter.add(p.Duration)
+		s.BlockHeaders[index.BlockIndex].Stats.jitter.add(p.Duration)
+	}
+	s.blockRead[index.BlockIndex]++
+	s.read++
+	return ping.PingResults{Data: p, IP: s.Network.IPs[s.blockIPs[index.BlockIndex]]}, nil
+}
+
+// ToData reads all the remaining points into a new [Data], if no points have been read yet this is equivalent
+// to [ReadData] (the statistics are recomputed so may differ by floating point error).
+func (s *Stream) ToData() (*Data, error) {
+	d := NewData(s.URL)
+	for {
+		p, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.AddPoint(p)
+	}
+	for _, a := range s.Annotations {
+		d.AddAnnotation(a)
+	}
+	return d, nil
+}
+
+func asReaderAt(r io.Reader) (io.ReaderAt, int64, error) {
+	if ra, ok := r.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		size, err := ra.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, err
+		}
+		_, err = ra.Seek(0, io.SeekStart)
+		return ra, size, err
+	}
+	all, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(all), int64(len(all)), nil
+}
+
+// readMeta follows the same layout as [Data.readVersion2] and [Data.readVersion1] but only reads up until the
+// variable length data, the offsets of which are then computed from the lengths.
+func (s *Stream) readMeta(ra io.ReaderAt, size int64) error {
+	m := metaReader{r: bufio.NewReader(io.NewSectionReader(ra, 0, size))}
+	m.read(idLen+1, func(b []byte) (int, error) {
+		if Identifier(b[0]) == TargetsID {
+			return 0, ErrMultipleTargets
+		}
+		i, err := readID(b, DataID)
+		if err != nil {
+			return i, err
+		}
+		s.PingsMeta = version(b[i])
+		if s.PingsMeta < noRuns || s.PingsMeta > currentDataVersion {
+			return i, errors.Errorf("unknown version %d", s.PingsMeta)
+		}
+		return i + 1, nil
+	})
+	m.read(int64Len+int64Len, func(b []byte) (int, error) {
+		var insertLen int
+		i := readLen(b, &insertLen)
+		s.insertLen = int64(insertLen)
+		return i + readInt64(b[i:], &s.TotalCount), nil
+	})
+	networkHeaderReader, networkDataReader := s.Network.twoPhaseRead()
+	var IPsLen, blockIndexesLen int
+	m.read(idLen+intLen+2*int64Len, func(b []byte) (int, error) {
+		return networkHeaderReader(b, &IPsLen, &blockIndexesLen)
+	})
+	var blockLen int
+	m.read(intLen+int64Len, func(b []byte) (int, error) {
+		// drop block header len, each header is read by itself and from [wideSketches] on they vary in length
+		return intLen + readLen(b[intLen:], &blockLen), nil
+	})
+	if m.err != nil {
+		return m.err
+	}
+	s.BlockHeaders = make([]*Header, blockLen)
+	blockSizes := make([]int, blockLen)
+	for index := range blockLen {
+		b := &Block{}
+		header, _ := b.twoPhaseRead(s.PingsMeta)
+		buckets := s.storedBuckets(&m, blockHeaderStatsOffset)
+		m.read(blockHeaderLen(s.PingsMeta, buckets), func(input []byte) (int, error) { return header(input, &blockSizes[index]) })
+		s.BlockHeaders[index] = b.Header
+	}
+	var URLLen int
+	m.read(int64Len, func(b []byte) (int, error) { return readLen(b, &URLLen), nil })
+	s.Runs = &Runs{GoodPackets: &Run{}, DroppedPackets: &Run{}}
+	if s.PingsMeta != noRuns {
+		m.read(runsLen, func(b []byte) (int, error) { return s.Runs.fromCompact(b, s.PingsMeta) })
+	}
+	buckets := s.storedBuckets(&m, idLen)
+	m.read(headerLenOf(s.PingsMeta, buckets), func(b []byte) (int, error) { return s.Header.fromCompact(b, s.PingsMeta) })
+	if m.err != nil {
+		return m.err
+	}
+
+	// The variable length data, each section starts where the last ends.
+	s.ra = ra
+	offset := m.offset
+	section := func(length int) *io.SectionReader {
+		ret := io.NewSectionReader(ra, offset, int64(length))
+		offset += int64(length)
+		return ret
+	}
+	s.insertOffset = offset
+	_ = section(int(s.insertLen) * dataIndexesLen)
+	networkData := make([]byte, IPsLen*netIPLen+blockIndexesLen*intLen)
+	if _, err := section(len(networkData)).ReadAt(networkData, 0); err != nil && len(networkData) > 0 {
+		return errors.Wrap(unexpected(err), "while reading Network")
+	}
+	_ = networkDataReader(networkData, IPsLen, blockIndexesLen)
+	s.blockSizes = blockSizes
+	s.blockOffsets = make([]int64, blockLen)
+	s.blockIPs = make([]int, blockLen)
+	for index, blockSize := range blockSizes {
+		s.blockOffsets[index] = offset
+		_ = section(blockSize * pingDataPointLen)
+		s.blockIPs[index] = slices.Index(s.Network.BlockIndexes, index)
+		if s.blockIPs[index] < 0 {
+			return errors.Errorf("block %d has no IP", index)
+		}
+	}
+	url := make([]byte, URLLen)
+	if _, err := section(URLLen).ReadAt(url, 0); err != nil && URLLen > 0 {
+		return errors.Wrap(unexpected(err), "while reading URL")
+	}
+	s.URL = string(url)
+	if err := s.readAnnotations(section(int(size - offset))); err != nil {
+		return err
+	}
+	if s.PingsMeta <= noJitter {
+		return s.migrate()
+	}
+	s.reset()
+	return nil
+}
+
+// storedBuckets peeks how many sketch buckets are stored by the [Stats] which begin [at] bytes into the next
+// read, the [Stats] of files before [wideSketches] store every bucket.
+func (s *Stream) storedBuckets(m *metaReader, at int) int {
+	buckets := sketchBuckets
+	if s.PingsMeta > wideSketches {
+		m.peek(at+statsPrefixLen, func(b []byte) { buckets = storedBuckets(b[at:]) })
+	}
+	return buckets
+}
+
+// reset moves all the cursors back to the first point.
+func (s *Stream) reset() {
+	s.read = 0
+	s.insertOrder = bufio.NewReader(io.NewSectionReader(s.ra, s.insertOffset, s.insertLen*dataIndexesLen))
+	s.blocks = make([]*bufio.Reader, len(s.blockSizes))
+	s.blockRead = make([]int, len(s.blockSizes))
+	for index, blockSize := range s.blockSizes {
+		s.blocks[index] = bufio.NewReader(io.NewSectionReader(s.ra, s.blockOffsets[index], int64(blockSize*pingDataPointLen)))
+	}
+}
+
+// migrate is the streaming version of [Data.migrate] for files which don't store indexed runs, quantiles or
+// jitter, an extra pass over every point is needed to compute them.
+func (s *Stream) migrate() error {
+	s.reset()
+	if s.PingsMeta < noAnnotations {
+		s.Runs = &Runs{GoodPackets: &Run{}, DroppedPackets: &Run{}}
+		s.recomputeRuns = true
+	}
+	s.recomputeQuantiles = s.PingsMeta <= noQuantiles
+	s.recomputeJitter = true
+	defer func() { s.recomputeRuns, s.recomputeQuantiles, s.recomputeJitter = false, false, false }()
+	for {
+		_, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			s.reset()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Stream) readAnnotations(r *io.SectionReader) error {
+	s.Annotations = []Annotation{}
+	if s.PingsMeta < noRollups {
+		return nil
+	}
+	m := metaReader{r: bufio.NewReader(r)}
+	var annotationsLen int
+	m.read(int64Len, func(b []byte) (int, error) { return readLen(b, &annotationsLen), nil })
+	for range annotationsLen {
+		if m.err != nil {
+			break
+		}
+		var a Annotation
+		var textLen int
+		// The text is variable length so first peek the fixed size prefix
+		m.peek(idLen+timeLen+int64Len, func(b []byte) { _ = readLen(b[idLen+timeLen:], &textLen) })
+		m.read(idLen+timeLen+int64Len+textLen, a.FromCompact)
+		s.Annotations = append(s.Annotations, a)
+	}
+	if m.err != nil {
+		return errors.Wrap(m.err, "while reading Annotations")
+	}
+	if s.PingsMeta > noRollups {
+		return s.skipRollups(&m)
+	}
+	return nil
+}
+
+// skipRollups doesn't read the [Rollups] as they're not needed while streaming, but it does check that they're
+// all present so that a truncated file is still an error.
+func (s *Stream) skipRollups(m *metaReader) error {
+	var levelsLen int
+	m.read(idLen+int64Len, func(b []byte) (int, error) {
+		i, err := readID(b, RollupsID)
+		if err != nil {
+			return i, err
+		}
+		return i + readLen(b[i:], &levelsLen), nil
+	})
+	for range levelsLen {
+		var bucketsLen int
+		m.read(timeDurationLen+int64Len, func(b []byte) (int, error) {
+			return timeDurationLen + readLen(b[timeDurationLen:], &bucketsLen), nil
+		})
+		m.skip(bucketsLen * rollupLen)
+	}
+	return errors.Wrap(m.err, "while reading Rollups")
+}
+
+// metaReader wraps the repeated error handling of reading the meta data of a [Stream], the first error
+// stops all future reads.
+type metaReader struct {
+	r      *bufio.Reader
+	err    error
+	offset int64
+}
+
+// read peeks at least [n] bytes which are passed to [f], [f] returns how many bytes it consumed.
+func (m *metaReader) read(n int, f func([]byte) (int, error)) {
+	if m.err != nil {
+		return
+	}
+	b, err := m.peekN(n)
+	if err != nil {
+		m.err = err
+		return
+	}
+	consumed, err := f(b)
+	if err != nil {
+		m.err = err
+		return
+	}
+	_, m.err = m.r.Discard(consumed)
+	m.offset += int64(consumed)
+}
+
+func (m *metaReader) skip(n int) {
+	if m.err != nil {
+		return
+	}
+	discarded, err := m.r.Discard(n)
+	m.err = unexpected(err)
+	m.offset += int64(discarded)
+}
+
+func (m *metaReader) peek(n int, f func([]byte)) {
+	if m.err != nil {
+		return
+	}
+	b, err := m.peekN(n)
+	if err != nil {
+		m.err = err
+		return
+	}
+	f(b)
+}
+
+func (m *metaReader) peekN(n int) ([]byte, error) {
+	if n > m.r.Size() {
+		// Grow the buffer so that it can be peeked, only long annotations hit this.
+		rest := bufio.NewReaderSize(m.r, n)
+		m.r = rest
+	}
+	b, err := m.r.Peek(n)
+	return b, unexpected(err)
+}
+
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
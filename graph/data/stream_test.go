@@ -0,0 +1,136 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/utils/th"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStreamMatchesReadData(t *testing.T) {
+	t.Parallel()
+	inputs, err := filepath.Glob("testdata/input/*.pings")
+	assert.NilError(t, err)
+	assert.Assert(t, len(inputs) > 0)
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			t.Parallel()
+			expected := readTestFile(t, input)
+			f, err := os.Open(input)
+			assert.NilError(t, err)
+			defer f.Close()
+			s, err := data.NewStream(f)
+			assert.NilError(t, err)
+			checkStream(t, expected, s)
+		})
+	}
+}
+
+func TestStreamWithAnnotations(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		d.AddPoint(p)
+	}
+	d.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(2000), Text: "router rebooted"})
+	long := string(bytes.Repeat([]byte("a"), 10_000))
+	d.AddAnnotation(data.Annotation{Timestamp: time.UnixMilli(3000), Text: long})
+	var b bytes.Buffer
+	assert.NilError(t, d.AsCompact(&b))
+
+	s, err := data.NewStream(bytes.NewReader(b.Bytes()))
+	assert.NilError(t, err)
+	checkStream(t, d, s)
+
+	// A reader which can't seek must also work
+	s, err = data.NewStream(struct{ io.Reader }{bytes.NewReader(b.Bytes())})
+	assert.NilError(t, err)
+	checkStream(t, d, s)
+}
+
+func TestStreamToData(t *testing.T) {
+	t.Parallel()
+	expected := readTestFile(t, "testdata/input/medium-309-with-induced-drops-02-08-2024.pings")
+	f, err := os.Open("testdata/input/medium-309-with-induced-drops-02-08-2024.pings")
+	assert.NilError(t, err)
+	defer f.Close()
+	s, err := data.NewStream(f)
+	assert.NilError(t, err)
+	actual, err := s.ToData()
+	assert.NilError(t, err)
+	assert.Equal(t, actual.URL, expected.URL)
+	assert.Equal(t, actual.TotalCount, expected.TotalCount)
+	for i := range expected.TotalCount {
+		assert.Check(t, is.DeepEqual(actual.GetFull(i), expected.GetFull(i)))
+	}
+	assert.Check(t, is.DeepEqual(actual.Runs, expected.Runs))
+}
+
+func TestStreamTruncated(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	for _, p := range makeLargePings() {
+		d.AddPoint(p)
+	}
+	var b bytes.Buffer
+	assert.NilError(t, d.AsCompact(&b))
+	_, err := data.NewStream(bytes.NewReader(b.Bytes()[:b.Len()/2]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = data.NewStream(bytes.NewReader(b.Bytes()[:10]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestStreamEmpty(t *testing.T) {
+	t.Parallel()
+	d := data.NewData("www.google.com")
+	var b bytes.Buffer
+	assert.NilError(t, d.AsCompact(&b))
+	s, err := data.NewStream(bytes.NewReader(b.Bytes()))
+	assert.NilError(t, err)
+	checkStream(t, d, s)
+	_, err = s.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func checkStream(t *testing.T, expected *data.Data, s *data.Stream) {
+	t.Helper()
+	assert.Equal(t, s.URL, expected.URL)
+	assert.Equal(t, s.TotalCount, expected.TotalCount)
+	assert.Check(t, is.DeepEqual(s.Header, expected.Header, th.AllowAllUnexported))
+	assert.Check(t, is.DeepEqual(s.Network.IPs, expected.Network.IPs))
+	assert.Check(t, is.DeepEqual(s.Annotations, expected.Annotations))
+	assert.Check(t, is.DeepEqual(s.Runs, expected.Runs))
+	for i, header := range expected.BlockHeaders {
+		assert.Check(t, is.DeepEqual(s.BlockHeaders[i], header, th.AllowAllUnexported))
+	}
+	for i := range expected.TotalCount {
+		p, err := s.Next()
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(p, expected.GetFull(i)), i)
+	}
+	_, err := s.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func readTestFile(t *testing.T, path string) *data.Data {
+	t.Helper()
+	f, err := os.Open(path)
+	assert.NilError(t, err)
+	defer f.Close()
+	d, err := data.ReadData(f)
+	assert.NilError(t, err)
+	return d
+}
@@ -0,0 +1,93 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"io"
+	"slices"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// ErrMultipleTargets is returned when reading a '.pings' file which holds many targets into a single [Data],
+// use [ReadTargets] or [NewTargetStreams] instead.
+var ErrMultipleTargets = errors.New("the file holds the pings of more than one url")
+
+// Targets are the [Data] of many URLs stored in a single '.pings' file, each target is a complete [Data] with
+// it's own Network, Blocks and Runs. A file only holds [Targets] once a second URL is captured to it, until
+// then it's a plain [Data].
+type Targets struct {
+	Targets []*Data
+}
+
+// Find returns the index of the target with the given url, or -1 if there isn't one.
+func (t *Targets) Find(url string) int {
+	return slices.IndexFunc(t.Targets, func(d *Data) bool { return d.URL == url })
+}
+
+// URLs returns the url of every target in file order.
+func (t *Targets) URLs() []string {
+	ret := make([]string, len(t.Targets))
+	for i, d := range t.Targets {
+		ret[i] = d.URL
+	}
+	return ret
+}
+
+// ReadTargets is like [ReadData] but also reads files which hold many targets, a file holding a single [Data]
+// is read as a single target.
+func ReadTargets(r io.Reader) (*Targets, error) {
+	toReadFrom, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "While reading into Targets{}")
+	}
+	t := &Targets{}
+	if len(toReadFrom) > 0 && Identifier(toReadFrom[0]) == DataID {
+		d := &Data{}
+		if _, err := d.FromCompact(toReadFrom); err != nil {
+			return nil, errors.Wrap(err, "While reading into Targets{}")
+		}
+		t.Targets = []*Data{d}
+		return t, nil
+	}
+	if _, err := t.FromCompact(toReadFrom); err != nil {
+		return nil, errors.Wrap(err, "While reading into Targets{}")
+	}
+	return t, nil
+}
+
+// NewTargetStreams is like [NewStream] but also reads files which hold many targets, returning a [Stream] per
+// target in file order. Only the meta data of each target is read.
+func NewTargetStreams(r io.Reader) ([]*Stream, error) {
+	ra, size, err := asReaderAt(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "while streaming Targets")
+	}
+	var id [idLen]byte
+	if _, err := ra.ReadAt(id[:], 0); err != nil {
+		return nil, errors.Wrap(unexpected(err), "while streaming Targets")
+	}
+	if Identifier(id[0]) == DataID {
+		s, err := NewStream(io.NewSectionReader(ra, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		return []*Stream{s}, nil
+	}
+	sections, err := targetSections(ra, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "while streaming Targets")
+	}
+	ret := make([]*Stream, len(sections))
+	for i, section := range sections {
+		ret[i], err = NewStream(section)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while streaming target %d", i)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,103 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/Lexer747/acci-ping/utils/errors"
+)
+
+// Each target is prefixed by it's length in bytes so that a reader can find any target without reading the
+// ones before it, see [targetSections].
+
+func (t *Targets) AsCompact(w io.Writer) error {
+	ret := make([]byte, t.byteLen())
+	_ = t.write(ret)
+	_, err := w.Write(ret)
+	return err
+}
+
+func (t *Targets) FromCompact(input []byte) (int, error) {
+	i, err := readID(input, TargetsID)
+	if err != nil {
+		return i, errors.Wrap(err, "while reading compact Targets")
+	}
+	if len(input) < i+int64Len {
+		return i, errors.Wrap(io.ErrUnexpectedEOF, "while reading compact Targets")
+	}
+	targetsLen := 0
+	i += readLen(input[i:], &targetsLen)
+	t.Targets = make([]*Data, 0, min(targetsLen, len(input)))
+	for index := range targetsLen {
+		dataLen := 0
+		if len(input) < i+int64Len {
+			return i, errors.Wrap(io.ErrUnexpectedEOF, "while reading compact Targets")
+		}
+		i += readLen(input[i:], &dataLen)
+		if dataLen < 0 || dataLen > len(input)-i {
+			return i, errors.Wrapf(io.ErrUnexpectedEOF, "while reading compact Targets, target %d", index)
+		}
+		d := &Data{}
+		if _, err := d.FromCompact(input[i : i+dataLen]); err != nil {
+			return i, errors.Wrapf(err, "while reading compact Targets, target %d", index)
+		}
+		t.Targets = append(t.Targets, d)
+		i += dataLen
+	}
+	return i, nil
+}
+
+func (t *Targets) write(ret []byte) int {
+	i := writeByte(ret, TargetsID)
+	i += writeLen(ret[i:], t.Targets)
+	for _, d := range t.Targets {
+		i += writeInt(ret[i:], d.byteLen())
+		i += d.write(ret[i:])
+	}
+	return i
+}
+
+func (t *Targets) byteLen() int {
+	i := idLen + int64Len
+	for _, d := range t.Targets {
+		i += int64Len + d.byteLen()
+	}
+	return i
+}
+
+// targetSections finds the bytes of each target without reading them.
+func targetSections(ra io.ReaderAt, size int64) ([]*io.SectionReader, error) {
+	var buf [idLen + int64Len]byte
+	if _, err := ra.ReadAt(buf[:], 0); err != nil {
+		return nil, unexpected(err)
+	}
+	if _, err := readID(buf[:], TargetsID); err != nil {
+		return nil, err
+	}
+	//nolint:gosec
+	// G115 a length larger than an int64 is a corrupt file which is caught by the bounds checks below.
+	targetsLen := int64(binary.LittleEndian.Uint64(buf[idLen:]))
+	offset := int64(len(buf))
+	ret := []*io.SectionReader{}
+	for index := range targetsLen {
+		if _, err := ra.ReadAt(buf[:int64Len], offset); err != nil {
+			return nil, errors.Wrapf(unexpected(err), "target %d", index)
+		}
+		//nolint:gosec
+		// G115 as above.
+		dataLen := int64(binary.LittleEndian.Uint64(buf[:int64Len]))
+		offset += int64Len
+		if dataLen < 0 || dataLen > size-offset {
+			return nil, errors.Wrapf(io.ErrUnexpectedEOF, "target %d", index)
+		}
+		ret = append(ret, io.NewSectionReader(ra, offset, dataLen))
+		offset += dataLen
+	}
+	return ret, nil
+}
@@ -0,0 +1,79 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func makeTestTargets() *data.Targets {
+	google := data.NewData("www.google.com")
+	addTestPoint(google, 1, 5*time.Millisecond, net.IPv4bcast)
+	addTestPoint(google, 2, 0, net.IPv4bcast)
+	example := data.NewData("www.example.com")
+	addTestPoint(example, 1, 9*time.Millisecond, net.IPv4allsys)
+	return &data.Targets{Targets: []*data.Data{google, example, data.NewData("empty")}}
+}
+
+func TestCompactTargets(t *testing.T) {
+	t.Parallel()
+	testCompacter(t, makeTestTargets(), &data.Targets{})
+}
+
+func TestReadTargets(t *testing.T) {
+	t.Parallel()
+	targets := makeTestTargets()
+	var b bytes.Buffer
+	assert.NilError(t, targets.AsCompact(&b))
+
+	read, err := data.ReadTargets(bytes.NewReader(b.Bytes()))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(read.URLs(), []string{"www.google.com", "www.example.com", "empty"}))
+	assert.Check(t, is.Equal(read.Find("www.example.com"), 1))
+	assert.Check(t, is.Equal(read.Find("missing"), -1))
+
+	_, err = data.ReadData(bytes.NewReader(b.Bytes()))
+	assert.Check(t, is.ErrorIs(err, data.ErrMultipleTargets))
+	_, err = data.NewStream(bytes.NewReader(b.Bytes()))
+	assert.Check(t, is.ErrorIs(err, data.ErrMultipleTargets))
+
+	// A plain file is a single target
+	read, err = data.ReadTargets(bytes.NewReader(targetBytes(t, targets.Targets[0])))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(read.URLs(), []string{"www.google.com"}))
+}
+
+func TestTargetStreams(t *testing.T) {
+	t.Parallel()
+	targets := makeTestTargets()
+	var b bytes.Buffer
+	assert.NilError(t, targets.AsCompact(&b))
+
+	streams, err := data.NewTargetStreams(bytes.NewReader(b.Bytes()))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(streams, len(targets.Targets)))
+	for i, s := range streams {
+		checkStream(t, targets.Targets[i], s)
+	}
+
+	_, err = data.NewTargetStreams(bytes.NewReader(b.Bytes()[:b.Len()-4]))
+	assert.Check(t, err != nil)
+}
+
+func targetBytes(t *testing.T, d *data.Data) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	assert.NilError(t, d.AsCompact(&b))
+	return b.Bytes()
+}
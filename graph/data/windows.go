@@ -0,0 +1,156 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+// DefaultWindows are the windows of time which [RollingStats] are usually kept for.
+var DefaultWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// WindowBucket is the accuracy of the windows of [RollingStats], each window starts on a multiple of this.
+const WindowBucket = 5 * time.Second
+
+// WindowStats are the stats of the points in a window of time before the latest point.
+type WindowStats struct {
+	// Window is how long before the latest point these stats cover, zero is all time.
+	Window time.Duration
+	Stats  *Stats
+	// LongestDrop is the longest run of consecutive dropped packets in the window, from the first dropped
+	// packet until the next good packet.
+	LongestDrop time.Duration
+}
+
+// RollingStats keeps the [Stats] of the most recent points for some windows of time (e.g. the last minute)
+// as the points are added. The points are grouped into buckets of [WindowBucket] and a window is all the
+// buckets which overlap it, so only the buckets for the longest window are kept.
+type RollingStats struct {
+	windows []time.Duration
+	longest time.Duration
+	buckets []windowBucket
+	drops   []dropRun
+	latest  time.Time
+
+	all         Stats
+	longestDrop time.Duration
+}
+
+type windowBucket struct {
+	start time.Time
+	stats Stats
+}
+
+// dropRun is a run of consecutive dropped packets, it's ongoing until the next good packet.
+type dropRun struct {
+	begin, end time.Time
+	ongoing    bool
+}
+
+func NewRollingStats(windows ...time.Duration) *RollingStats {
+	return &RollingStats{
+		windows: windows,
+		longest: slices.Max(windows),
+		buckets: []windowBucket{},
+		drops:   []dropRun{},
+	}
+}
+
+// AddPoint adds the point to its bucket and the all time stats, the points must be added in order.
+func (r *RollingStats) AddPoint(p ping.PingDataPoint) {
+	r.latest = p.Timestamp
+	start := p.Timestamp.Truncate(WindowBucket)
+	if len(r.buckets) == 0 || start.After(r.buckets[len(r.buckets)-1].start) {
+		r.buckets = append(r.buckets, windowBucket{start: start})
+	}
+	bucket := &r.buckets[len(r.buckets)-1]
+	if p.Dropped() {
+		bucket.stats.AddDroppedPacket()
+		r.all.AddDroppedPacket()
+		if len(r.drops) > 0 && r.drops[len(r.drops)-1].ongoing {
+			r.drops[len(r.drops)-1].end = p.Timestamp
+		} else {
+			r.drops = append(r.drops, dropRun{begin: p.Timestamp, end: p.Timestamp, ongoing: true})
+		}
+	} else {
+		bucket.stats.AddPoint(p.Duration)
+		r.all.AddPoint(p.Duration)
+		if len(r.drops) > 0 && r.drops[len(r.drops)-1].ongoing {
+			r.drops[len(r.drops)-1].end = p.Timestamp
+			r.drops[len(r.drops)-1].ongoing = false
+		}
+	}
+	if len(r.drops) > 0 {
+		last := r.drops[len(r.drops)-1]
+		r.longestDrop = max(r.longestDrop, last.end.Sub(last.begin))
+	}
+
+	cutoff := r.latest.Add(-r.longest)
+	expired := slices.IndexFunc(r.buckets, func(b windowBucket) bool { return b.start.Add(WindowBucket).After(cutoff) })
+	r.buckets = slices.Delete(r.buckets, 0, max(expired, 0))
+	expired = slices.IndexFunc(r.drops, func(d dropRun) bool { return d.ongoing || !d.end.Before(cutoff) })
+	if expired == -1 {
+		expired = len(r.drops)
+	}
+	r.drops = slices.Delete(r.drops, 0, expired)
+}
+
+// Windows are the stats of each window in the order given to [NewRollingStats] followed by the all time
+// stats. The stats of a window without any points are nil.
+func (r *RollingStats) Windows() []WindowStats {
+	ret := make([]WindowStats, 0, len(r.windows)+1)
+	for _, window := range r.windows {
+		cutoff := r.latest.Add(-window)
+		w := WindowStats{Window: window}
+		// Merging needs good points on both sides, so buckets of only dropped packets are counted separately.
+		var dropped uint64
+		for _, b := range r.buckets {
+			if !b.start.Add(WindowBucket).After(cutoff) {
+				continue
+			}
+			if b.stats.GoodCount == 0 {
+				dropped += b.stats.PacketsDropped
+				continue
+			}
+			if w.Stats == nil {
+				// Copy so that the bucket isn't changed by a merge.
+				first := b.stats
+				w.Stats = &first
+				continue
+			}
+			w.Stats = w.Stats.Merge(&b.stats)
+		}
+		if dropped != 0 {
+			if w.Stats == nil {
+				w.Stats = &Stats{}
+			}
+			w.Stats.PacketsDropped += dropped
+		}
+		for _, d := range r.drops {
+			if !d.end.Before(cutoff) {
+				w.LongestDrop = max(w.LongestDrop, d.end.Sub(later(d.begin, cutoff)))
+			}
+		}
+		ret = append(ret, w)
+	}
+	all := WindowStats{LongestDrop: r.longestDrop}
+	if r.all.GoodCount+r.all.PacketsDropped != 0 {
+		stats := r.all
+		all.Stats = &stats
+	}
+	return append(ret, all)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
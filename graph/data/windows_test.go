@@ -0,0 +1,75 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package data_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRollingStats(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.August, 2, 21, 0, 0, 0, time.UTC)
+	r := data.NewRollingStats(time.Minute, 5*time.Minute)
+	// 10 minutes of a ping a second, 10ms for the first 8 minutes then 30ms. There's a 20 second drop in the
+	// 2nd minute and a 3 second drop in the 10th.
+	for i := range 10 * 60 {
+		p := ping.PingDataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), Duration: 10 * time.Millisecond}
+		if i >= 8*60 {
+			p.Duration = 30 * time.Millisecond
+		}
+		if (i >= 70 && i < 90) || (i >= 550 && i < 553) {
+			p = ping.PingDataPoint{Timestamp: p.Timestamp, DropReason: ping.Timeout}
+		}
+		r.AddPoint(p)
+	}
+
+	windows := r.Windows()
+	assert.Assert(t, is.Len(windows, 3))
+	minute, five, all := windows[0], windows[1], windows[2]
+
+	assert.Check(t, is.Equal(minute.Window, time.Minute))
+	assert.Check(t, is.Equal(minute.Stats.Mean, float64(30*time.Millisecond)))
+	assert.Check(t, is.Equal(minute.Stats.PacketsDropped, uint64(3)))
+	assert.Check(t, is.Equal(minute.LongestDrop, 3*time.Second))
+
+	assert.Check(t, is.Equal(five.Window, 5*time.Minute))
+	// The bucket which overlaps the start of the window is included
+	assert.Check(t, is.Equal(five.Stats.GoodCount+five.Stats.PacketsDropped, uint64(5*60+5)))
+	assert.Check(t, five.Stats.Quantile(0.95) > 29*time.Millisecond, five.Stats.Quantile(0.95))
+	assert.Check(t, is.Equal(five.LongestDrop, 3*time.Second))
+
+	assert.Check(t, is.Equal(all.Window, time.Duration(0)))
+	assert.Check(t, is.Equal(all.Stats.GoodCount, uint64(600-23)))
+	assert.Check(t, is.Equal(all.Stats.PacketsDropped, uint64(23)))
+	assert.Check(t, is.Equal(all.LongestDrop, 20*time.Second))
+}
+
+func TestRollingStatsDropped(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.August, 2, 21, 0, 0, 0, time.UTC)
+	r := data.NewRollingStats(data.DefaultWindows...)
+	for _, w := range r.Windows() {
+		assert.Check(t, is.Nil(w.Stats))
+	}
+	// An ongoing drop
+	for i := range 30 {
+		r.AddPoint(ping.PingDataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), DropReason: ping.Timeout})
+	}
+	for _, w := range r.Windows() {
+		assert.Assert(t, w.Stats != nil)
+		assert.Check(t, is.Equal(w.Stats.GoodCount, uint64(0)))
+		assert.Check(t, is.Equal(w.Stats.PacketsDropped, uint64(30)))
+		assert.Check(t, is.Equal(w.Stats.PacketLoss(), 1.0))
+		assert.Check(t, is.Equal(w.LongestDrop, 29*time.Second))
+	}
+}
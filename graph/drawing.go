@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -31,11 +31,13 @@ type computeFrameConfig struct {
 	followLatestSpan bool
 	drawSpinner      bool
 	yAxisScale       YAxisScale
+	zoom             Zoom
 }
 
 func (c computeFrameConfig) Match(cfg computeFrameConfig) bool {
 	return c.followLatestSpan == cfg.followLatestSpan &&
-		c.yAxisScale == cfg.yAxisScale
+		c.yAxisScale == cfg.yAxisScale &&
+		c.zoom.Equal(cfg.zoom)
 }
 
 const drawingDebug = false
@@ -49,13 +51,14 @@ func (g *Graph) computeFrame(cfg computeFrameConfig) func(io.Writer) error {
 	s := g.Term.GetSize()
 	g.data.Lock()
 	count := g.data.LockFreeTotalCount()
+	annotations := g.data.LockFreeAnnotations()
 	spinnerValue := ""
 	if cfg.drawSpinner {
 		spinnerValue = g.lastFrame.spinnerData.spinner(s)
 		g.drawingBuffer.Get(draw.SpinnerIndex).Reset()
 		g.drawingBuffer.Get(draw.SpinnerIndex).WriteString(spinnerValue)
 	}
-	if count == g.lastFrame.PacketCount && g.lastFrame.Match(s, cfg) {
+	if count == g.lastFrame.PacketCount && len(annotations) == g.lastFrame.AnnotationCount && g.lastFrame.Match(s, cfg) {
 		g.data.Unlock() // fast path the frame didn't change
 		if updateGui := g.checkGUI(); updateGui != nil {
 			return updateGui
@@ -74,38 +77,70 @@ func (g *Graph) computeFrame(cfg computeFrameConfig) func(io.Writer) error {
 	g.drawingBuffer.Reset(draw.GraphIndexes...)
 
 	header := g.data.LockFreeHeader()
-	iter := g.data.LockFreeIter(cfg.followLatestSpan)
+	extent := g.extent(cfg.followLatestSpan)
+	window := cfg.zoom.within(extent)
+	spans := g.data.LockFreeView(cfg.followLatestSpan, window)
+	if len(spans) == 0 {
+		// The window was panned to before the latest span began and then the latest span was followed.
+		window = nil
+		spans = g.data.LockFreeView(cfg.followLatestSpan, nil)
+	}
+	overall := header.TimeSpan
+	if window != nil {
+		overall = window
+	}
+	iter := g.data.LockFreeIter(spans)
 	x := computeXAxis(
 		g.drawingBuffer.Get(draw.XAxisIndex),
 		g.drawingBuffer.Get(draw.BarIndex),
 		s,
-		header.TimeSpan,
-		g.data.LockFreeSpanInfos(),
+		overall,
+		spans,
 		cfg.followLatestSpan,
 		int(iter.Total),
 	)
 	yStats := header.Stats
-	if cfg.followLatestSpan {
+	switch {
+	case cfg.followLatestSpan:
 		yStats = x.spans[0].pingStats
+	case window != nil:
+		yStats = viewStats(x.spans, header.Stats)
 	}
-	y := computeYAxis(g.drawingBuffer.Get(draw.YAxisIndex), s, yStats, g.data.LockFreeURL(), cfg.yAxisScale)
+	y := computeYAxis(
+		g.drawingBuffer.Get(draw.YAxisIndex),
+		s,
+		yStats,
+		overlayBounds(yStats, g.overlay),
+		g.data.LockFreeURL(),
+		cfg.yAxisScale,
+	)
+	drawGuides(g.drawingBuffer.Get(draw.GuideIndex), g.periods, x, y, s)
+	drawOverlay(g.drawingBuffer.Get(draw.OverlayIndex), g.overlay, header.TimeSpan.Begin, x, y, s)
+	drawAnomalies(g.drawingBuffer.Get(draw.AnomalyIndex), g.data.LockFreeAnomalies(), x, y, s)
+	addAnnotationMarkers(g.drawingBuffer.Get(draw.BarIndex), g.drawingBuffer.Get(draw.AnnotationIndex), s, annotations, x, y)
 	computeFrame(
 		g,
 		g.drawingBuffer.Get(draw.GradientIndex),
 		g.drawingBuffer.Get(draw.DataIndex),
 		g.drawingBuffer.Get(draw.DroppedIndex),
 		g.drawingBuffer.Get(draw.KeyIndex),
-		iter,
+		// The x-axis needs the real count of points but drawing them only needs enough to fill each column.
+		g.data.LockFreeRollupIter(spans, s.Width),
 		g.data.LockFreeRuns(),
 		x, y, s,
 	)
+	drawZoomIndicator(g.drawingBuffer.Get(draw.KeyIndex), window, extent, s)
 	g.drawingBuffer.Get(draw.SpinnerIndex).WriteString(spinnerValue)
 	// Everything we need is now cached we can unlock a bit early while we tidy up for the next frame
 	paintFrame := withGUI(g.drawingBuffer)
 	noGUI := withoutGUI(g.drawingBuffer)
 	g.data.Unlock()
+	// The last frame is also read by [Graph.Inspect] from outside the drawing thread.
+	g.frameMutex.Lock()
+	defer g.frameMutex.Unlock()
 	g.lastFrame = frame{
 		PacketCount:       count,
+		AnnotationCount:   len(annotations),
 		yAxis:             y,
 		xAxis:             x,
 		spinnerData:       g.lastFrame.spinnerData,
@@ -25,12 +25,39 @@ func (g *Graph) ComputeFrame() string {
 		followLatestSpan: false,
 		drawSpinner:      false,
 		yAxisScale:       g.presentation.Get().YAxisScale,
+		zoom:             g.presentation.Get().Zoom,
 	})
 	err := painter(&b)
 	check.NoErr(err, "While painting frame to string buffer")
 	return b.String()
 }
 
+// Zoom applies the action in the same way as the control plane.
+func (g *Graph) Zoom(action ZoomAction) Zoom {
+	p := g.presentation.Get()
+	g.data.Lock()
+	p.Zoom = g.zoom(p.Zoom, action, p.Following)
+	g.data.Unlock()
+	g.presentation.Set(p)
+	return p.Zoom
+}
+
+// Pan applies the columns in the same way as the control plane.
+func (g *Graph) Pan(columns int) Zoom {
+	p := g.presentation.Get()
+	width := g.plotWidth()
+	g.data.Lock()
+	p.Zoom = g.pan(p.Zoom, columns, width, p.Following)
+	g.data.Unlock()
+	g.presentation.Set(p)
+	return p.Zoom
+}
+
+// PlotWidth is how many columns the points were drawn across in the last frame.
+func (g *Graph) PlotWidth() int {
+	return g.plotWidth()
+}
+
 func (g *Graph) Size() int64 {
 	return g.data.TotalCount()
 }
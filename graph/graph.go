@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -35,6 +35,8 @@ type Graph struct {
 
 	presentation   atomic.Of[Presentation]
 	controlChannel <-chan Control
+	overlay        *data.Data
+	periods        []data.Period
 	lastFrame      frame
 	initial        ping.PingsPerMinute
 	debugStrict    bool
@@ -45,6 +47,10 @@ type Graph struct {
 type Control struct {
 	FollowLatestSpan Change[bool]
 	YAxisScale       Change[YAxisScale]
+	// Zoom is an action applied to the current [Zoom].
+	Zoom Change[ZoomAction]
+	// Pan moves the current [Zoom] by this many columns of the graph, forward in time if positive.
+	Pan Change[int]
 }
 
 type Change[T any] struct {
@@ -57,9 +63,11 @@ type Change[T any] struct {
 //
 //   - Following if true will make it so that the latest span is the only one drawn as if it is the main graph.
 //   - Y Axis scale sets the y axis scale according to that enum.
+//   - Zoom is the window of time drawn, see [Zoom].
 type Presentation struct {
 	Following  bool
 	YAxisScale YAxisScale
+	Zoom       Zoom
 }
 
 type GraphConfiguration struct {
@@ -74,7 +82,13 @@ type GraphConfiguration struct {
 	DrawingBuffer *draw.Buffer
 	ControlPlane  <-chan Control
 	// Optional (can be nil)
-	Data           *data.Data
+	Data *data.Data
+	// Overlay is optional (can be nil) and is another capture which is drawn behind [Data] as if both started
+	// at the same time, for comparing two captures. It must not change while the graph is drawn.
+	Overlay *data.Data
+	// Periods are optional (can be nil), each is drawn as faint vertical guides at its peaks, see
+	// [data.Data.Periodicity].
+	Periods        []data.Period
 	URL            string
 	PingsPerMinute ping.PingsPerMinute
 	Presentation   Presentation
@@ -85,6 +99,10 @@ func StartUp() {
 	xAxisStartup()
 	yAxisStartup()
 	drawWindowStartUp()
+	annotationStartup()
+	overlayStartUp()
+	anomalyStartUp()
+	guideStartUp()
 }
 
 func NewGraph(ctx context.Context, cfg GraphConfiguration) *Graph {
@@ -106,6 +124,8 @@ func NewGraph(ctx context.Context, cfg GraphConfiguration) *Graph {
 		ui:             cfg.Gui,
 		debugStrict:    cfg.DebugStrict,
 		controlChannel: cfg.ControlPlane,
+		overlay:        cfg.Overlay,
+		periods:        cfg.Periods,
 		presentation:   atomic.Init(cfg.Presentation),
 	}
 	if ctx != nil {
@@ -131,9 +151,10 @@ func (g *Graph) Run(
 	ctx context.Context,
 	stop context.CancelCauseFunc,
 	listeners []terminal.ConditionalListener,
+	keyListeners []terminal.KeyListener,
 	fallbacks []terminal.Listener,
 ) (func() error, func(), <-chan terminal.Size, error) {
-	cleanup, err := g.Term.StartRaw(ctx, stop, listeners, fallbacks)
+	cleanup, err := g.Term.StartRaw(ctx, stop, listeners, keyListeners, fallbacks)
 	if err != nil {
 		return nil, cleanup, nil, err
 	}
@@ -166,6 +187,7 @@ func (g *Graph) Run(
 					followLatestSpan: p.Following,
 					drawSpinner:      true,
 					yAxisScale:       p.YAxisScale,
+					zoom:             p.Zoom,
 				})
 				// Now that we have a frame to draw (may just be a spinner update), execute this function
 				// writing the painted frame to the terminal.
@@ -197,6 +219,7 @@ func (g *Graph) OneFrame() error {
 		followLatestSpan: p.Following,
 		drawSpinner:      false,
 		yAxisScale:       p.YAxisScale,
+		zoom:             p.Zoom,
 	})
 	return toWrite(g.Term)
 }
@@ -218,6 +241,37 @@ func (g *Graph) Summarise() string {
 	return strings.ReplaceAll(g.data.Summary(), "| ", "\n\t")
 }
 
+// Outages finds every outage of the graph's backed data, see [data.Data.Outages].
+func (g *Graph) Outages(options data.OutageOptions) *data.OutageReport {
+	return g.data.Outages(options)
+}
+
+// Anomalies found in the graph's backed data so far, see [graphdata.GraphData.Anomalies].
+func (g *Graph) Anomalies() []data.Anomaly {
+	return g.data.Anomalies()
+}
+
+// Heatmap groups the graph's backed data by day and time of day, see [data.Data.Heatmap].
+func (g *Graph) Heatmap(options data.HeatmapOptions) *data.Heatmap {
+	return g.data.Heatmap(options)
+}
+
+// Windows are the stats of the most recent points of the graph's backed data, see
+// [graphdata.GraphData.Windows].
+func (g *Graph) Windows() []data.WindowStats {
+	return g.data.Windows()
+}
+
+// Stats of the graph's backed data, see [graphdata.GraphData.Stats].
+func (g *Graph) Stats() *data.Stats {
+	return g.data.Stats()
+}
+
+// AddAnnotation stores the annotation alongside the graph's data, it is drawn from the next frame onwards.
+func (g *Graph) AddAnnotation(a data.Annotation) {
+	g.data.AddAnnotation(a)
+}
+
 func (g *Graph) ClearForPerfTest() {
 	g.lastFrame = frame{spinnerData: spinner{timestampLastDrawn: time.Now()}}
 	g.drawingBuffer = draw.NewPaintBuffer()
@@ -250,8 +304,8 @@ func (g *Graph) handleControl(ctx context.Context) {
 			if !ok {
 				return
 			}
-			guiChanged := p.FollowLatestSpan.DidChange || p.YAxisScale.DidChange
-			pr := Presentation{}
+			guiChanged := p.FollowLatestSpan.DidChange || p.YAxisScale.DidChange || p.Zoom.DidChange || p.Pan.DidChange
+			pr := g.presentation.Get()
 			if p.FollowLatestSpan.DidChange {
 				pr.Following = p.FollowLatestSpan.Value
 				slog.Info("switching to:", "FollowLatestSpan", p.FollowLatestSpan.Value)
@@ -260,6 +314,20 @@ func (g *Graph) handleControl(ctx context.Context) {
 				pr.YAxisScale = p.YAxisScale.Value
 				slog.Info("switching to:", "YAxisScale", p.YAxisScale.Value)
 			}
+			if p.Zoom.DidChange {
+				g.data.Lock()
+				pr.Zoom = g.zoom(pr.Zoom, p.Zoom.Value, pr.Following)
+				g.data.Unlock()
+				slog.Info("switching to:", "Zoom", p.Zoom.Value, "Window", pr.Zoom.Window, "End", pr.Zoom.End)
+			}
+			if p.Pan.DidChange {
+				// The width is read first as the frame lock can't be taken while holding the data lock.
+				width := g.plotWidth()
+				g.data.Lock()
+				pr.Zoom = g.pan(pr.Zoom, p.Pan.Value, width, pr.Following)
+				g.data.Unlock()
+				slog.Debug("switching to:", "Pan", p.Pan.Value, "Window", pr.Zoom.Window, "End", pr.Zoom.End)
+			}
 			if guiChanged {
 				g.presentation.Set(pr)
 			}
@@ -283,6 +351,7 @@ type frame struct {
 	yAxis             drawingYAxis
 	cfg               computeFrameConfig
 	PacketCount       int64
+	AnnotationCount   int
 }
 
 func (f frame) Match(s terminal.Size, cfg computeFrameConfig) bool {
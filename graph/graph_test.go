@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -9,6 +9,7 @@ package graph_test
 import (
 	"context"
 	"math/rand/v2"
+	"net"
 	"os"
 	"strings"
 	"testing"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/Lexer747/acci-ping/draw"
 	"github.com/Lexer747/acci-ping/graph"
+	"github.com/Lexer747/acci-ping/graph/data"
 	"github.com/Lexer747/acci-ping/ping"
 	"github.com/Lexer747/acci-ping/terminal"
 	"github.com/Lexer747/acci-ping/utils/env"
@@ -188,16 +190,272 @@ func TestThousandsDrawing(t *testing.T) {
 	drawingTest(t, test)
 }
 
+func TestAnnotationDrawing(t *testing.T) {
+	t.Parallel()
+	test := DrawingTest{
+		Size: terminal.Size{Height: 15, Width: 80},
+		Values: []ping.PingDataPoint{
+			{Duration: 1 * time.Second, Timestamp: time.Time{}.Add(1 * time.Second)},
+			{Duration: 2 * time.Second, Timestamp: time.Time{}.Add(2 * time.Second)},
+			{Duration: 3 * time.Second, Timestamp: time.Time{}.Add(3 * time.Second)},
+			{Duration: 2 * time.Second, Timestamp: time.Time{}.Add(4 * time.Second)},
+			{Duration: 1 * time.Second, Timestamp: time.Time{}.Add(5 * time.Second)},
+		},
+		Annotations: []data.Annotation{
+			{Timestamp: time.Time{}.Add(2500 * time.Millisecond), Text: "router rebooted"},
+			{Timestamp: time.Time{}.Add(2700 * time.Millisecond), Text: "moved laptop to kitchen"},
+			{Timestamp: time.Time{}.Add(10 * time.Second), Text: "not drawn"},
+		},
+		ExpectedFile: "testdata/annotation.frame",
+	}
+	drawingTest(t, test)
+}
+
+func TestOverlayDrawing(t *testing.T) {
+	t.Parallel()
+	// The overlay is a day later but is drawn as if it started at the same time, it's also slower so the y-axis
+	// grows to fit it. The last overlay point is after the graph's data so isn't drawn.
+	later := time.Time{}.Add(24 * time.Hour)
+	test := DrawingTest{
+		Size: terminal.Size{Height: 15, Width: 80},
+		Values: []ping.PingDataPoint{
+			{Duration: 1 * time.Second, Timestamp: time.Time{}.Add(1 * time.Second)},
+			{Duration: 2 * time.Second, Timestamp: time.Time{}.Add(2 * time.Second)},
+			{Duration: 3 * time.Second, Timestamp: time.Time{}.Add(3 * time.Second)},
+			{Duration: 2 * time.Second, Timestamp: time.Time{}.Add(4 * time.Second)},
+			{Duration: 1 * time.Second, Timestamp: time.Time{}.Add(5 * time.Second)},
+		},
+		Overlay: []ping.PingDataPoint{
+			{Duration: 4 * time.Second, Timestamp: later.Add(1 * time.Second)},
+			{Duration: 5 * time.Second, Timestamp: later.Add(2 * time.Second)},
+			{DropReason: ping.TestDrop, Timestamp: later.Add(3 * time.Second)},
+			{Duration: 3 * time.Second, Timestamp: later.Add(4 * time.Second)},
+			{Duration: 4 * time.Second, Timestamp: later.Add(5 * time.Second)},
+			{Duration: 6 * time.Second, Timestamp: later.Add(6 * time.Second)},
+		},
+		ExpectedFile: "testdata/overlay.frame",
+	}
+	drawingTest(t, test)
+}
+
+func TestGuideDrawing(t *testing.T) {
+	t.Parallel()
+	// A spike every 4 seconds from the 2nd second, the guides are at each spike. The shorter period is too
+	// dense to draw.
+	values := []ping.PingDataPoint{}
+	for i := range 20 {
+		p := ping.PingDataPoint{Duration: 1 * time.Second, Timestamp: time.Time{}.Add(time.Duration(i) * time.Second)}
+		if i%4 == 2 {
+			p.Duration = 3 * time.Second
+		}
+		values = append(values, p)
+	}
+	test := DrawingTest{
+		Size:   terminal.Size{Height: 15, Width: 80},
+		Values: values,
+		Periods: []data.Period{
+			{Period: 4 * time.Second, Peak: time.Time{}.Add(2 * time.Second)},
+			{Period: 100 * time.Millisecond, Peak: time.Time{}},
+		},
+		ExpectedFile: "testdata/guides.frame",
+	}
+	drawingTest(t, test)
+}
+
+func TestZoomDrawing(t *testing.T) {
+	t.Parallel()
+	// An hour of a ping every 10 seconds which gets slower, the view is zoomed into the last quarter and then
+	// panned back by a quarter of that.
+	values := []ping.PingDataPoint{}
+	begin := time.Date(2026, time.March, 4, 14, 0, 0, 0, time.UTC)
+	for i := range 360 {
+		values = append(values, ping.PingDataPoint{
+			Duration:  time.Duration(10+i/10) * time.Millisecond,
+			Timestamp: begin.Add(time.Duration(i) * 10 * time.Second),
+		})
+	}
+	test := DrawingTest{
+		Size:         terminal.Size{Height: 15, Width: 80},
+		Values:       values,
+		Zoom:         []graph.ZoomAction{graph.ZoomIn, graph.ZoomIn, graph.PanLeft},
+		ExpectedFile: "testdata/zoom.frame",
+	}
+	drawingTest(t, test)
+}
+
+func TestZoom(t *testing.T) {
+	t.Parallel()
+	g, closer, err := initTestGraph(t, terminal.Size{Height: 15, Width: 80}, nil, nil)
+	assert.NilError(t, err)
+	defer closer()
+	begin := time.Date(2026, time.March, 4, 14, 0, 0, 0, time.UTC)
+	for i := range 60 {
+		// Two half hours with an hour gap between them.
+		timestamp := begin.Add(time.Duration(i) * time.Minute)
+		if i >= 30 {
+			timestamp = timestamp.Add(time.Hour)
+		}
+		g.AddPoint(ping.PingResults{Data: ping.PingDataPoint{Duration: time.Millisecond, Timestamp: timestamp}, IP: []byte{}})
+	}
+	end := begin.Add(119 * time.Minute)
+	window := 29*time.Minute + 45*time.Second
+
+	assert.Equal(t, g.Zoom(graph.ZoomIn), graph.Zoom{Window: 2 * window})
+	assert.Equal(t, g.Zoom(graph.ZoomIn), graph.Zoom{Window: window})
+	assert.Equal(t, g.Zoom(graph.PanLeft), graph.Zoom{Window: window, End: end.Add(-window / 4)})
+	g.Zoom(graph.PanLeft)
+	g.Zoom(graph.PanLeft)
+	// The next pan would be entirely in the gap, so it jumps back to the last point before the gap.
+	assert.Equal(t, g.Zoom(graph.PanLeft), graph.Zoom{Window: window, End: begin.Add(29 * time.Minute)})
+	// The window was clamped to the beginning of the data, so it is three pans until the gap.
+	for range 3 {
+		assert.Assert(t, !g.Zoom(graph.PanRight).End.IsZero())
+	}
+	// Again the pan would be in the gap, this time it jumps forward to the first point after the gap which is
+	// live again.
+	assert.Equal(t, g.Zoom(graph.PanRight), graph.Zoom{Window: window})
+	assert.Equal(t, g.Zoom(graph.ZoomOut), graph.Zoom{Window: 2 * window})
+	assert.Equal(t, g.Zoom(graph.ZoomOut), graph.Zoom{})
+
+	g.Zoom(graph.ZoomIn)
+	g.Zoom(graph.PanLeft)
+	assert.Equal(t, g.Zoom(graph.Live), graph.Zoom{})
+}
+
+func TestPan(t *testing.T) {
+	t.Parallel()
+	g, closer, err := initTestGraph(t, terminal.Size{Height: 15, Width: 80}, nil, nil)
+	assert.NilError(t, err)
+	defer closer()
+	begin := time.Date(2026, time.March, 4, 14, 0, 0, 0, time.UTC)
+	for i := range 60 {
+		g.AddPoint(ping.PingResults{Data: ping.PingDataPoint{Duration: time.Millisecond, Timestamp: begin.Add(time.Duration(i) * time.Minute)}, IP: []byte{}})
+	}
+	end := begin.Add(59 * time.Minute)
+	window := 14*time.Minute + 45*time.Second
+
+	g.Zoom(graph.ZoomIn)
+	assert.Equal(t, g.Zoom(graph.ZoomIn), graph.Zoom{Window: window})
+	_ = g.ComputeFrame()
+	width := g.PlotWidth()
+	assert.Assert(t, width > 0)
+	// Already live, so there's nothing to pan forward to.
+	assert.Equal(t, g.Pan(1), graph.Zoom{Window: window})
+	assert.Equal(t, g.Pan(-width), graph.Zoom{Window: window, End: end.Add(-window)})
+	assert.Equal(t, g.Pan(1), graph.Zoom{Window: window, End: end.Add(-window).Add(window / time.Duration(width))})
+	assert.Equal(t, g.Pan(width), graph.Zoom{Window: window})
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+	size := terminal.Size{Height: 15, Width: 80}
+	g, closer, err := initTestGraph(t, size, nil, nil)
+	assert.NilError(t, err)
+	defer closer()
+	begin := time.Date(2026, time.March, 4, 14, 0, 0, 0, time.UTC)
+	ip := net.IPv4(192, 168, 0, 1)
+	for i := range 60 {
+		g.AddPoint(ping.PingResults{
+			Data: ping.PingDataPoint{Duration: time.Duration(i+1) * time.Millisecond, Timestamp: begin.Add(time.Duration(i) * time.Minute)},
+			IP:   ip,
+		})
+	}
+	_, ok := g.Inspect(1)
+	assert.Assert(t, !ok, "the y-axis labels aren't part of the graph")
+	_, ok = g.Inspect(40)
+	assert.Assert(t, !ok, "nothing is drawn before the first frame")
+	_ = g.ComputeFrame()
+
+	var inspected []ping.PingResults
+	for column := 1; column <= size.Width; column++ {
+		if p, ok := g.Inspect(column); ok {
+			inspected = append(inspected, p)
+		}
+	}
+	assert.Assert(t, len(inspected) > 0)
+	assert.Equal(t, inspected[0].Data.Timestamp, begin)
+	assert.Equal(t, inspected[len(inspected)-1].Data.Timestamp, begin.Add(59*time.Minute))
+	assert.Assert(t, inspected[0].IP.Equal(ip))
+	for i := 1; i < len(inspected); i++ {
+		assert.Assert(t, !inspected[i].Data.Timestamp.Before(inspected[i-1].Data.Timestamp))
+	}
+}
+
+func TestInspectColumn(t *testing.T) {
+	t.Parallel()
+	size := terminal.Size{Height: 15, Width: 80}
+	g, closer, err := initTestGraph(t, size, nil, nil)
+	assert.NilError(t, err)
+	defer closer()
+	begin := time.Date(2026, time.March, 4, 14, 0, 0, 0, time.UTC)
+	ips := []net.IP{net.IPv4(192, 168, 0, 1), net.IPv4(192, 168, 0, 2)}
+	for i := range 200 {
+		// Two spans with an hour gap between them, the second pinging a different IP.
+		timestamp := begin.Add(time.Duration(i) * 10 * time.Second)
+		ip := ips[0]
+		if i >= 100 {
+			timestamp = timestamp.Add(time.Hour)
+			ip = ips[1]
+		}
+		p := ping.PingDataPoint{Duration: time.Duration(i%7+1) * time.Millisecond, Timestamp: timestamp}
+		if i%10 == 0 {
+			p = ping.PingDataPoint{DropReason: ping.TestDrop, Timestamp: timestamp}
+		}
+		g.AddPoint(ping.PingResults{Data: p, IP: ip})
+	}
+	_, _, ok := g.PlotColumns()
+	assert.Assert(t, !ok, "nothing is drawn before the first frame")
+	_ = g.ComputeFrame()
+	first, last, ok := g.PlotColumns()
+	assert.Assert(t, ok)
+	assert.Assert(t, first > 1 && last < size.Width, "%d-%d", first, last)
+
+	var good, dropped uint64
+	shared := 0
+	var previous graph.Column
+	for column := first; column <= last; column++ {
+		c, ok := g.InspectColumn(column)
+		if !ok {
+			// The columns between the spans.
+			continue
+		}
+		assert.Assert(t, !c.Begin.Before(previous.End), "column %d overlaps the one before", column)
+		good += c.Stats.GoodCount
+		dropped += c.Stats.PacketsDropped
+		if len(c.IPs) == 2 {
+			// The spans share the column between them, so both IPs are in it.
+			shared++
+		}
+		previous = c
+	}
+	// Every point is in exactly one column.
+	assert.Equal(t, good, uint64(180))
+	assert.Equal(t, dropped, uint64(20))
+	assert.Equal(t, shared, 1)
+
+	c, ok := g.InspectColumn(last)
+	assert.Assert(t, ok)
+	assert.Assert(t, c.Stats.GoodCount > 0)
+	assert.Assert(t, c.IPs[0].Equal(ips[1]))
+	_, ok = g.InspectColumn(1)
+	assert.Assert(t, !ok)
+}
+
 type DrawingTest struct {
 	ExpectedFile string
 	Values       []ping.PingDataPoint
-	Size         terminal.Size
+	Annotations  []data.Annotation
+	Overlay      []ping.PingDataPoint
+	Periods      []data.Period
+	// Zoom is applied in order once every value is added.
+	Zoom []graph.ZoomAction
+	Size terminal.Size
 }
 
 //nolint:unused
 func updateDrawingTest(t *testing.T, test DrawingTest) {
 	t.Helper()
-	actual := drawGraph(t, test.Size, test.Values)
+	actual := drawGraph(t, test)
 	err := os.WriteFile(test.ExpectedFile, []byte(strings.Join(actual, "\n")), 0o777)
 	assert.NilError(t, err)
 	t.Fatal("Only call update drawing once")
@@ -206,7 +464,7 @@ func updateDrawingTest(t *testing.T, test DrawingTest) {
 func drawingTest(t *testing.T, test DrawingTest) {
 	// updateDrawingTest(t, test)
 	t.Helper()
-	actualStrings := drawGraph(t, test.Size, test.Values)
+	actualStrings := drawGraph(t, test)
 	expectedBytes, err := os.ReadFile(test.ExpectedFile)
 	assert.NilError(t, err)
 	actualJoined := strings.Join(actualStrings, "\n")
@@ -226,21 +484,31 @@ func drawingTest(t *testing.T, test DrawingTest) {
 	}
 }
 
-func drawGraph(t *testing.T, size terminal.Size, input []ping.PingDataPoint) []string {
+func drawGraph(t *testing.T, test DrawingTest) []string {
 	t.Helper()
-	if len(input) == 1 {
+	if len(test.Values) == 1 {
 		panic("drawGraph test doesn't work on inputs size 1")
 	}
-	g, closer, err := initTestGraph(t, size)
+	var overlay *data.Data
+	if test.Overlay != nil {
+		overlay = data.NewData("overlay")
+		for _, p := range test.Overlay {
+			overlay.AddPoint(ping.PingResults{Data: p, IP: []byte{}})
+		}
+	}
+	g, closer, err := initTestGraph(t, test.Size, overlay, test.Periods)
 	assert.NilError(t, err)
 	defer closer()
 
-	actual := eval(t, g, input)
-	output := th.MakeBuffer(size)
-	return th.EmulateTerminal(actual, output, size, th.Panic)
+	for _, a := range test.Annotations {
+		g.AddAnnotation(a)
+	}
+	actual := eval(t, g, test.Values, test.Zoom...)
+	output := th.MakeBuffer(test.Size)
+	return th.EmulateTerminal(actual, output, test.Size, th.Panic)
 }
 
-func initTestGraph(t *testing.T, size terminal.Size) (*graph.Graph, func(), error) {
+func initTestGraph(t *testing.T, size terminal.Size, overlay *data.Data, periods []data.Period) (*graph.Graph, func(), error) {
 	t.Helper()
 	stdin, _, term, setTerm, err := th.NewTestTerminal()
 	setTerm(size)
@@ -255,15 +523,20 @@ func initTestGraph(t *testing.T, size terminal.Size) (*graph.Graph, func(), erro
 		Terminal:      term,
 		DrawingBuffer: draw.NewPaintBuffer(),
 		DebugStrict:   true,
+		Overlay:       overlay,
+		Periods:       periods,
 	})
 	return g, func() { stdin.WriteCtrlC(t) }, err
 }
 
-func eval(t *testing.T, g *graph.Graph, input []ping.PingDataPoint) string {
+func eval(t *testing.T, g *graph.Graph, input []ping.PingDataPoint, zoom ...graph.ZoomAction) string {
 	t.Helper()
 	for _, p := range input {
 		g.AddPoint(ping.PingResults{Data: p, IP: []byte{}})
 	}
+	for _, action := range zoom {
+		g.Zoom(action)
+	}
 	assert.Equal(t, int64(len(input)), g.Size())
 	actual := g.ComputeFrame()
 	assert.Equal(t, int64(len(input)), g.Size())
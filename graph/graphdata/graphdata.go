@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -8,6 +8,8 @@ package graphdata
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,16 +29,22 @@ type GraphData struct {
 	m         *sync.Mutex
 	spans     []*SpanInfo
 	spanIndex int
+	anomalies *data.AnomalyDetector
+	rolling   *data.RollingStats
 }
 
 func NewGraphData(d *data.Data) *GraphData {
 	g := &GraphData{
-		data:  d,
-		spans: []*SpanInfo{NewSpanInfo()},
-		m:     &sync.Mutex{},
+		data:      d,
+		spans:     []*SpanInfo{NewSpanInfo()},
+		m:         &sync.Mutex{},
+		anomalies: data.NewAnomalyDetector(data.DefaultAnomalyOptions),
+		rolling:   data.NewRollingStats(data.DefaultWindows...),
 	}
 	for i := range d.TotalCount {
 		g.addPointToSpans(d.Get(i), i)
+		g.anomalies.AddPoint(i, d.Get(i))
+		g.rolling.AddPoint(d.Get(i))
 	}
 	return g
 }
@@ -46,6 +54,15 @@ func (gd *GraphData) AddPoint(p ping.PingResults) {
 	defer gd.Unlock()
 	gd.data.AddPoint(p)
 	gd.addPointToSpans(p.Data, gd.data.TotalCount-1)
+	gd.anomalies.AddPoint(gd.data.TotalCount-1, p.Data)
+	gd.rolling.AddPoint(p.Data)
+}
+
+// AddAnnotation stores a user annotation in the underlying data, see [data.Data.AddAnnotation].
+func (gd *GraphData) AddAnnotation(a data.Annotation) {
+	gd.Lock()
+	defer gd.Unlock()
+	gd.data.AddAnnotation(a)
 }
 
 func (gd *GraphData) TotalCount() int64 {
@@ -66,6 +83,40 @@ func (gd *GraphData) Summary() string {
 	return gd.data.Summary()
 }
 
+func (gd *GraphData) Outages(options data.OutageOptions) *data.OutageReport {
+	gd.Lock()
+	defer gd.Unlock()
+	return gd.data.Outages(options)
+}
+
+func (gd *GraphData) Heatmap(options data.HeatmapOptions) *data.Heatmap {
+	gd.Lock()
+	defer gd.Unlock()
+	return gd.data.Heatmap(options)
+}
+
+// Anomalies is a copy of every anomaly found so far, see [data.AnomalyDetector].
+func (gd *GraphData) Anomalies() []data.Anomaly {
+	gd.Lock()
+	defer gd.Unlock()
+	return slices.Clone(gd.anomalies.Anomalies)
+}
+
+// Windows are the stats of the most recent points, see [data.RollingStats.Windows].
+func (gd *GraphData) Windows() []data.WindowStats {
+	gd.Lock()
+	defer gd.Unlock()
+	return gd.rolling.Windows()
+}
+
+// Stats is a copy of the stats of every point so far.
+func (gd *GraphData) Stats() *data.Stats {
+	gd.Lock()
+	defer gd.Unlock()
+	ret := *gd.data.Header.Stats
+	return &ret
+}
+
 func (gd *GraphData) Lock() {
 	gd.m.Lock()
 }
@@ -78,38 +129,161 @@ func (gd *GraphData) LockFreeHeader() *data.Header { return gd.data.Header }
 func (gd *GraphData) LockFreeURL() string          { return gd.data.URL }
 func (gd *GraphData) LockFreeRuns() *data.Runs     { return gd.data.Runs }
 func (gd *GraphData) LockFreeSpanInfos() Spans     { return gd.spans }
+func (gd *GraphData) LockFreeGet(index int64) ping.PingDataPoint {
+	return gd.data.Get(index)
+}
+func (gd *GraphData) LockFreeGetFull(index int64) ping.PingResults {
+	return gd.data.GetFull(index)
+}
+func (gd *GraphData) LockFreeAnomalies() []data.Anomaly {
+	return gd.anomalies.Anomalies
+}
+func (gd *GraphData) LockFreeAnnotations() []data.Annotation {
+	return gd.data.Annotations
+}
 
-func (gd *GraphData) LockFreeIter(followLatestSpan bool) *Iter {
-	offset := int64(0)
-	total := gd.LockFreeTotalCount()
+// LockFreeView are the spans to draw, only the latest span if [followLatestSpan] and if [window] isn't nil
+// only the points within it. A span which is partly in the window is replaced by a span of just the points
+// in the window, so it's empty if there's no points in the window.
+func (gd *GraphData) LockFreeView(followLatestSpan bool, window *data.TimeSpan) Spans {
+	spans := gd.LockFreeSpanInfos()
 	if followLatestSpan {
-		spans := gd.LockFreeSpanInfos()
-		lastIndex := len(spans) - 1
-		spansExceptLast := spans[:lastIndex]
-		offset = int64(spansExceptLast.Count())
-		lastSpan := spans[lastIndex]
-		total = int64(lastSpan.Count)
+		spans = spans[len(spans)-1:]
+	}
+	if window == nil {
+		return spans
 	}
+	first := gd.LockFreeIndexAt(window.Begin)
+	last := gd.LockFreeIndexAt(window.End.Add(time.Nanosecond)) - 1
+	ret := Spans{}
+	for _, span := range spans {
+		start, end := max(span.start, first), min(span.end, last)
+		switch {
+		case span.Count == 0 || start > end:
+			continue
+		case start == span.start && end == span.end:
+			ret = append(ret, span)
+		default:
+			ret = append(ret, gd.clipSpan(start, end))
+		}
+	}
+	return ret
+}
+
+// LockFreeIndexAt is the index of the first point which isn't before [t], or the total count if every point
+// is before it.
+func (gd *GraphData) LockFreeIndexAt(t time.Time) int64 {
+	total := gd.LockFreeTotalCount()
+	return int64(sort.Search(int(total), func(i int) bool { return !gd.data.Get(int64(i)).Timestamp.Before(t) }))
+}
+
+// clipSpan is a new span of the points from [start] to [end] inclusive.
+func (gd *GraphData) clipSpan(start, end int64) *SpanInfo {
+	ret := NewSpanInfo()
+	ret.addFirstPoint(gd.data.Get(start), start)
+	for i := start + 1; i <= end; i++ {
+		ret.add(gd.data.Get(i), i)
+	}
+	return ret
+}
+
+// LockFreeIter iterates every point of the [spans], which must be consecutive e.g. from
+// [GraphData.LockFreeView].
+func (gd *GraphData) LockFreeIter(spans Spans) *Iter {
 	return &Iter{
-		Total:  total,
+		Total:  int64(spans.Count()),
 		d:      gd.data,
-		spans:  gd.LockFreeSpanInfos(),
-		offset: offset,
+		spans:  spans,
+		offset: spans[0].start,
 	}
 }
 
+// LockFreeRollupIter is [GraphData.LockFreeIter] but when there are far more points than [columns] to draw
+// them in, each span is iterated using the extremes of the [data.Rollups] instead of every point. The
+// resolution of the rollups is picked per span so that there's still a few buckets for each column the span
+// is likely to be drawn in.
+func (gd *GraphData) LockFreeRollupIter(spans Spans, columns int) *Iter {
+	iter := gd.LockFreeIter(spans)
+	columns = max(columns, 1)
+	if iter.Total <= int64(columns*rollupMinPointsPerColumn) {
+		return iter
+	}
+	points := make([]ping.PingDataPoint, 0, columns*rollupBucketsPerColumn*rollupPointsPerBucket)
+	for _, span := range spans {
+		// Same as the x-axis, each span is given columns in proportion to how many points it has.
+		spanColumns := max(int64(columns)*int64(span.Count)/iter.Total, 1)
+		points = gd.rollupSpan(points, span, spanColumns*rollupBucketsPerColumn)
+	}
+	return &Iter{
+		Total:  int64(len(points)),
+		d:      gd.data,
+		spans:  spans,
+		points: points,
+	}
+}
+
+const (
+	// rollupMinPointsPerColumn is how many points per terminal column there must be before the rollups are
+	// used at all.
+	rollupMinPointsPerColumn = 1_000
+	// rollupBucketsPerColumn is the minimum number of buckets per terminal column before a coarser resolution
+	// is used.
+	rollupBucketsPerColumn = 8
+	// rollupPointsPerBucket is the most points which [data.Rollup.Extremes] returns.
+	rollupPointsPerBucket = 4
+)
+
+// rollupSpan appends the points which represent [span] to [points], which is every point of the span if
+// there's no resolution which would reduce them.
+func (gd *GraphData) rollupSpan(points []ping.PingDataPoint, span *SpanInfo, minBuckets int64) []ping.PingDataPoint {
+	if int64(span.Count) > minBuckets {
+		for level := len(data.RollupResolutions) - 1; level >= 0; level-- {
+			buckets := gd.data.Rollups.Within(level, span.TimeSpan)
+			if int64(len(buckets)) < minBuckets && level > 0 {
+				continue
+			}
+			if len(buckets)*rollupPointsPerBucket >= span.Count {
+				break
+			}
+			for _, bucket := range buckets {
+				for _, p := range bucket.Extremes() {
+					// Buckets on the edge of a span may contain points from the neighbouring spans.
+					if span.TimeSpan.Contains(p.Timestamp) {
+						points = append(points, p)
+					}
+				}
+			}
+			return points
+		}
+	}
+	for i := span.start; i <= span.end; i++ {
+		points = append(points, gd.data.Get(i))
+	}
+	return points
+}
+
+// Iter is a view of the points to draw, either every point or a summary of them from the rollups see
+// [GraphData.LockFreeRollupIter].
 type Iter struct {
-	d      *data.Data
-	spans  Spans
+	d     *data.Data
+	spans Spans
+	// points are the points to iterate when they've come from the rollups.
+	points []ping.PingDataPoint
 	Total  int64
 	offset int64
 }
 
 func (i *Iter) Get(index int64) ping.PingDataPoint {
+	if i.points != nil {
+		return i.points[index]
+	}
 	return i.d.Get(index + i.offset)
 }
 
 func (i *Iter) IsLast(index int64) bool {
+	if i.points != nil {
+		return index-1 == i.Total
+	}
 	return i.d.IsLast(index)
 }
 
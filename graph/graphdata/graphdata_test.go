@@ -7,6 +7,7 @@
 package graphdata_test
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -173,7 +174,7 @@ func (test BasicTimeSpanTest) Run(t *testing.T) {
 
 func assertEveryPointHasSpan(t *testing.T, gd *graphdata.GraphData, actual []*graphdata.SpanInfo) {
 	t.Helper()
-	iter := gd.LockFreeIter(false)
+	iter := gd.LockFreeIter(gd.LockFreeSpanInfos())
 	for i := range iter.Total {
 		p := iter.Get(i)
 		timestamp := p.Timestamp
@@ -240,3 +241,82 @@ func (test TimeSpanFileTest) Run(t *testing.T) {
 	}
 	assertEveryPointHasSpan(t, gd, gd.LockFreeSpanInfos())
 }
+
+func TestLockFreeRollupIter(t *testing.T) {
+	t.Parallel()
+	gd := graphdata.NewGraphData(data.NewData("foo.bar"))
+	origin := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	// A day of 10 pings a second, with a slow ping and an outage in the middle
+	const count = 24 * 60 * 60 * 10
+	for i := range count {
+		p := ping.PingDataPoint{Duration: time.Duration(10+i%7) * time.Millisecond, Timestamp: origin.Add(time.Duration(i) * 100 * time.Millisecond)}
+		switch {
+		case i == count/2:
+			p.Duration = time.Second
+		case i > count/2 && i < count/2+50:
+			p.DropReason = ping.TestDrop
+		}
+		gd.AddPoint(ping.PingResults{Data: p})
+	}
+	assert.Equal(t, gd.LockFreeRollupIter(gd.LockFreeSpanInfos(), 1000).Total, int64(count), "too many columns to use the rollups")
+
+	iter := gd.LockFreeRollupIter(gd.LockFreeSpanInfos(), 80)
+	assert.Check(t, iter.Total < count/100, "expected far fewer than %d points, got %d", count, iter.Total)
+	slowest, dropped := false, 0
+	for i := range iter.Total {
+		p := iter.Get(i)
+		if i > 0 {
+			assert.Assert(t, !p.Timestamp.Before(iter.Get(i-1).Timestamp), "index %d out of order", i)
+		}
+		slowest = slowest || p.Duration == time.Second
+		if p.Dropped() {
+			dropped++
+		}
+	}
+	assert.Check(t, slowest, "the slowest point must be kept")
+	assert.Check(t, dropped > 0, "the outage must be kept")
+	assertEveryPointHasSpan(t, gd, gd.LockFreeSpanInfos())
+}
+
+func TestLockFreeView(t *testing.T) {
+	t.Parallel()
+	gd := graphdata.NewGraphData(data.NewData("foo.bar"))
+	origin := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Two spans of 100 pings a second apart, with an hour between them
+	for i := range 200 {
+		timestamp := origin.Add(time.Duration(i) * time.Second)
+		if i >= 100 {
+			timestamp = timestamp.Add(time.Hour)
+		}
+		p := ping.PingDataPoint{Duration: time.Duration(10+i) * time.Millisecond, Timestamp: timestamp}
+		gd.AddPoint(ping.PingResults{Data: p})
+	}
+	spans := gd.LockFreeSpanInfos()
+	assert.Assert(t, is.Len(spans, 2))
+	window := func(begin, end time.Duration) *data.TimeSpan {
+		return &data.TimeSpan{Begin: origin.Add(begin), End: origin.Add(end), Duration: end - begin}
+	}
+
+	assert.Check(t, slices.Equal(gd.LockFreeView(false, nil), spans))
+	assert.Check(t, slices.Equal(gd.LockFreeView(true, nil), spans[1:]))
+	assert.Check(t, slices.Equal(gd.LockFreeView(false, window(-time.Minute, 2*time.Hour)), spans), "spans in the window are kept")
+	assert.Assert(t, is.Len(gd.LockFreeView(false, window(30*time.Minute, 40*time.Minute)), 0), "the gap has no points")
+
+	// The end of the first span and the start of the second
+	view := gd.LockFreeView(false, window(90*time.Second, time.Hour+105*time.Second))
+	assert.Assert(t, is.Len(view, 2))
+	assert.Equal(t, view[0].Count, 10)
+	assert.Equal(t, view[0].TimeSpan.Begin, origin.Add(90*time.Second))
+	assert.Equal(t, view[0].PingStats.Min, 100*time.Millisecond)
+	assert.Equal(t, view[1].Count, 6)
+	assert.Equal(t, view[1].PingStats.Max, 115*time.Millisecond)
+	iter := gd.LockFreeIter(view)
+	assert.Equal(t, iter.Total, int64(16))
+	assert.Equal(t, iter.Get(0).Duration, 100*time.Millisecond)
+	assert.Equal(t, iter.Get(15).Duration, 115*time.Millisecond)
+
+	// Following only ever has points of the latest span
+	view = gd.LockFreeView(true, window(90*time.Second, time.Hour+105*time.Second))
+	assert.Assert(t, is.Len(view, 1))
+	assert.Equal(t, gd.LockFreeIter(view).Get(0).Duration, 110*time.Millisecond)
+}
@@ -0,0 +1,63 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+func guideStartUp() {
+	guideBar = themes.Secondary(typography.DashedVertical)
+}
+
+var guideBar string
+
+// minGuideGap is the fewest columns between two guides of a period, any closer and the guides would hide the
+// graph rather than show the pattern so the period isn't drawn in that span.
+const minGuideGap = 3
+
+// drawGuides draws a faint vertical guide at each peak of the [periods] which lies within a drawn span, in
+// the same way as the annotation markers (see [addAnnotationMarkers]).
+func drawGuides(
+	toWriteTo *bytes.SafeBuffer,
+	periods []data.Period,
+	xAxis drawingXAxis,
+	yAxis drawingYAxis,
+	s terminal.Size,
+) {
+	if len(periods) == 0 {
+		return
+	}
+	bar := makeBar(guideBar, s, true)
+	drawn := map[int]struct{}{}
+	for _, p := range periods {
+		for _, span := range xAxis.spans {
+			guides := p.Guides(span.timeSpan)
+			if len(guides) == 0 || span.timeSpan.Duration == 0 {
+				continue
+			}
+			if columns := float64(span.width) * float64(p.Period) / float64(span.timeSpan.Duration); columns < minGuideGap {
+				continue
+			}
+			for _, t := range guides {
+				x := getX(t, span, yAxis, s)
+				if _, found := drawn[x]; found {
+					continue
+				}
+				drawn[x] = struct{}{}
+				toWriteTo.WriteString(ansi.CursorPosition(2, x) + bar)
+			}
+		}
+	}
+	// Reset the cursor back to the start of the axis
+	toWriteTo.WriteString(ansi.CursorPosition(s.Height, 1))
+}
@@ -0,0 +1,200 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+)
+
+// HeatmapMetric is the value each cell of a heatmap is coloured by.
+type HeatmapMetric int
+
+const (
+	// HeatmapLatency colours by the p95 round trip time.
+	HeatmapLatency HeatmapMetric = iota
+	// HeatmapLoss colours by the packet loss.
+	HeatmapLoss
+)
+
+func (m HeatmapMetric) String() string {
+	switch m {
+	case HeatmapLatency:
+		return "p95 latency"
+	case HeatmapLoss:
+		return "packet loss"
+	default:
+		panic(fmt.Sprintf("unknown HeatmapMetric: %d", int(m)))
+	}
+}
+
+// HeatmapView controls how [HeatmapLines] draws a [data.Heatmap].
+type HeatmapView struct {
+	Metric HeatmapMetric
+	// MaxDays is the most days to draw, the most recent are kept. Zero draws every day.
+	MaxDays int
+}
+
+// heatmapLevels is the gradient of the cells from best to worst, each level has a distinct symbol so that
+// the heatmap can still be read without colour.
+var heatmapLevels = []struct {
+	symbol string
+	colour func(string) string
+}{
+	{typography.LightBlock, themes.Positive},
+	{typography.MediumBlock, themes.Highlight},
+	{typography.DarkBlock, themes.DarkNegative},
+	{typography.Block, themes.Negative},
+}
+
+// heatmapLossEdges are the upper bounds of each level but the last when colouring by [HeatmapLoss].
+var heatmapLossEdges = []float64{0, 0.01, 0.05}
+
+// heatmapMinHourWidth is the fewest columns between the hour labels.
+const heatmapMinHourWidth = 4
+
+// HeatmapLines draws [h] as a calendar, one line per day and one column per time of day bucket, with each
+// cell's symbol and colour chosen by the [HeatmapView.Metric]. The latency levels are spread logarithmically
+// between the fastest and slowest cell. The lines are suitable for a [gui.Box]. Returns nil if there are no
+// days.
+func HeatmapLines(h *data.Heatmap, view HeatmapView) []gui.Typography {
+	if len(h.Days) == 0 {
+		return nil
+	}
+	days, cells := h.Days, h.Cells
+	if view.MaxDays > 0 && len(days) > view.MaxDays {
+		days, cells = days[len(days)-view.MaxDays:], cells[len(cells)-view.MaxDays:]
+	}
+	buckets := h.BucketsPerDay()
+	// Small buckets are a single column, large buckets are stretched to keep the heatmap readable.
+	cellWidth := max(1, 48/buckets)
+
+	edges, legend, legendLen := heatmapEdges(cells, view.Metric)
+	level := func(cell *data.Stats) string {
+		if cell == nil {
+			return strings.Repeat(" ", cellWidth)
+		}
+		if view.Metric == HeatmapLatency && cell.GoodCount == 0 {
+			return themes.Negative(strings.Repeat(typography.Multiply, cellWidth))
+		}
+		value := heatmapValue(cell, view.Metric)
+		l := 0
+		for l < len(edges) && value > edges[l] {
+			l++
+		}
+		return heatmapLevels[l].colour(strings.Repeat(heatmapLevels[l].symbol, cellWidth))
+	}
+
+	title := fmt.Sprintf("%s by time of day (%s)", view.Metric.String(), h.Options.Location.String())
+	ret := []gui.Typography{
+		{ToPrint: themes.TitleHighlight(title), TextLen: utf8.RuneCountInString(title), Alignment: gui.Centre},
+		{ToPrint: "", TextLen: 0, Alignment: gui.Centre},
+	}
+	const dayLayout = "Mon 02 Jan "
+	labelWidth := len(dayLayout)
+	hours := heatmapHours(h, cellWidth)
+	ret = append(ret, gui.Typography{
+		ToPrint:   strings.Repeat(" ", labelWidth) + themes.Secondary(hours),
+		TextLen:   labelWidth + len(hours),
+		Alignment: gui.Left,
+	})
+	for i, day := range days {
+		var b strings.Builder
+		b.WriteString(themes.Primary(day.Format(dayLayout)))
+		for _, cell := range cells[i] {
+			b.WriteString(level(cell))
+		}
+		ret = append(ret, gui.Typography{ToPrint: b.String(), TextLen: labelWidth + buckets*cellWidth, Alignment: gui.Left})
+	}
+	ret = append(ret,
+		gui.Typography{ToPrint: "", TextLen: 0, Alignment: gui.Centre},
+		gui.Typography{ToPrint: legend, TextLen: legendLen, Alignment: gui.Centre},
+	)
+	return ret
+}
+
+func heatmapValue(cell *data.Stats, metric HeatmapMetric) float64 {
+	if metric == HeatmapLoss {
+		return cell.PacketLoss()
+	}
+	return float64(cell.Quantile(0.95))
+}
+
+// heatmapEdges are the upper bounds of each level but the last, and the legend which describes them.
+func heatmapEdges(cells [][]*data.Stats, metric HeatmapMetric) ([]float64, string, int) {
+	var edges []float64
+	var describe func(float64) string
+	switch metric {
+	case HeatmapLoss:
+		edges = heatmapLossEdges
+		describe = func(f float64) string { return fmt.Sprintf("%.3g%%", f*100) }
+	case HeatmapLatency:
+		fastest, slowest := math.Inf(1), math.Inf(-1)
+		for _, day := range cells {
+			for _, cell := range day {
+				if cell != nil && cell.GoodCount > 0 {
+					fastest = min(fastest, heatmapValue(cell, metric))
+					slowest = max(slowest, heatmapValue(cell, metric))
+				}
+			}
+		}
+		edges = make([]float64, len(heatmapLevels)-1)
+		for i := range edges {
+			if math.IsInf(fastest, 1) {
+				continue
+			}
+			ratio := float64(i+1) / float64(len(heatmapLevels))
+			edges[i] = math.Exp(math.Log(max(fastest, 1)) + ratio*(math.Log(max(slowest, 1))-math.Log(max(fastest, 1))))
+		}
+		describe = func(f float64) string { return histogramDuration(time.Duration(f)) }
+	}
+	var legend strings.Builder
+	legendLen := 0
+	add := func(symbol string, colour func(string) string, text string) {
+		if legendLen > 0 {
+			legend.WriteString("  ")
+			legendLen += 2
+		}
+		legend.WriteString(colour(symbol) + themes.Primary(" "+text))
+		legendLen += utf8.RuneCountInString(symbol) + 1 + utf8.RuneCountInString(text)
+	}
+	for i, l := range heatmapLevels {
+		if i < len(edges) {
+			add(l.symbol, l.colour, "≤ "+describe(edges[i]))
+		} else {
+			add(l.symbol, l.colour, "> "+describe(edges[len(edges)-1]))
+		}
+	}
+	if metric == HeatmapLatency {
+		add(typography.Multiply, themes.Negative, "all dropped")
+	}
+	return edges, legend.String(), legendLen
+}
+
+// heatmapHours labels the hours above the columns, as many as fit.
+func heatmapHours(h *data.Heatmap, cellWidth int) string {
+	width := h.BucketsPerDay() * cellWidth
+	ret := []byte(strings.Repeat(" ", width))
+	next := 0
+	for hour := range 24 {
+		at := hour * width / 24
+		if at < next || time.Duration(hour)*time.Hour%h.Options.Bucket != 0 {
+			continue
+		}
+		copy(ret[at:], fmt.Sprintf("%02d", hour))
+		next = at + heatmapMinHourWidth
+	}
+	return strings.TrimRight(string(ret), " ")
+}
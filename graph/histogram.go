@@ -0,0 +1,121 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+)
+
+// HistogramOptions control how [HistogramLines] draws the distribution.
+type HistogramOptions struct {
+	// Bins is the most bars to draw, one per line.
+	Bins int
+	// Log sizes the bins logarithmically, which spreads out the fast points and squashes the long tail.
+	Log bool
+	// BarWidth is the width of the fullest bar.
+	BarWidth int
+}
+
+var DefaultHistogramOptions = HistogramOptions{Bins: 16, BarWidth: 40}
+
+// histogramMarkers are the percentiles which are marked against the bin they fall in.
+var histogramMarkers = []struct {
+	q     float64
+	label string
+}{
+	{0.5, "p50"},
+	{0.95, "p95"},
+	{0.99, "p99"},
+}
+
+// HistogramLines draws the round trip time distribution of [stats] as a horizontal bar histogram, one line
+// per bin, with the bins that the p50, p95 and p99 fall in marked. The lines are suitable for a [gui.Box].
+// Returns nil if there are no good points.
+func HistogramLines(stats *data.Stats, options HistogramOptions) []gui.Typography {
+	h := stats.Histogram(options.Bins, options.Log)
+	if h == nil {
+		return nil
+	}
+	bins := "linear"
+	if h.Log {
+		bins = "log"
+	}
+	title := fmt.Sprintf("RTT distribution (%d points, %s bins)", h.Total, bins)
+	ret := []gui.Typography{
+		{ToPrint: themes.TitleHighlight(title), TextLen: utf8.RuneCountInString(title), Alignment: gui.Centre},
+		{ToPrint: "", TextLen: 0, Alignment: gui.Centre},
+	}
+
+	markers := make([][]string, len(h.Bins))
+	for _, m := range histogramMarkers {
+		value := stats.Quantile(m.q)
+		for i, b := range h.Bins {
+			if value < b.High || i == len(h.Bins)-1 {
+				markers[i] = append(markers[i], m.label)
+				break
+			}
+		}
+	}
+
+	labels := make([]string, len(h.Bins))
+	labelWidth := 0
+	for i, b := range h.Bins {
+		labels[i] = histogramDuration(b.Low) + " - " + histogramDuration(b.High)
+		labelWidth = max(labelWidth, utf8.RuneCountInString(labels[i]))
+	}
+	largest := h.Largest()
+	countWidth := len(fmt.Sprint(largest))
+	for i, b := range h.Bins {
+		width := int(uint64(options.BarWidth) * b.Count / largest)
+		bar := strings.Repeat(typography.Block, width)
+		if width == 0 && b.Count > 0 {
+			// Otherwise a handful of points in the long tail would be invisible
+			bar, width = typography.LightBlock, 1
+		}
+		padding := strings.Repeat(" ", options.BarWidth-width)
+		count := fmt.Sprintf(" %*d", countWidth, b.Count)
+		// Durations may be in µs so can't be padded by fmt
+		label := strings.Repeat(" ", labelWidth-utf8.RuneCountInString(labels[i])) + labels[i] + " "
+		line := themes.Primary(label) + themes.Secondary(typography.Vertical) + themes.Positive(bar) + padding + themes.Primary(count)
+		textLen := labelWidth + 1 + 1 + options.BarWidth + len(count)
+		if len(markers[i]) > 0 {
+			marker := " " + typography.FilledLeftTriangle + " " + strings.Join(markers[i], " ")
+			line += themes.Highlight(marker)
+			textLen += utf8.RuneCountInString(marker)
+		}
+		ret = append(ret, gui.Typography{ToPrint: line, TextLen: textLen, Alignment: gui.Left})
+	}
+
+	summary := make([]string, len(histogramMarkers))
+	for i, m := range histogramMarkers {
+		summary[i] = m.label + " " + histogramDuration(stats.Quantile(m.q))
+	}
+	footer := strings.Join(summary, " | ")
+	ret = append(ret,
+		gui.Typography{ToPrint: "", TextLen: 0, Alignment: gui.Centre},
+		gui.Typography{ToPrint: themes.Emphasis(footer), TextLen: utf8.RuneCountInString(footer), Alignment: gui.Centre},
+	)
+	return ret
+}
+
+// histogramDuration rounds to 3 significant figures, in integers since float rounding would print e.g.
+// 29.999999ms.
+func histogramDuration(d time.Duration) string {
+	unit := time.Duration(1)
+	for d/unit >= 1000 {
+		unit *= 10
+	}
+	return d.Round(unit).String()
+}
@@ -0,0 +1,161 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"net"
+	"slices"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+)
+
+// Inspect is the point drawn nearest to the [column] of the last frame, false if the column isn't part of the
+// graph or there's no point near it.
+func (g *Graph) Inspect(column int) (ping.PingResults, bool) {
+	g.frameMutex.Lock()
+	x, y := g.lastFrame.xAxis, g.lastFrame.yAxis
+	g.frameMutex.Unlock()
+	g.data.Lock()
+	defer g.data.Unlock()
+	best, bestDistance := int64(-1), time.Duration(0)
+	for _, c := range x.columnTimes(column, y) {
+		middle := c.begin.Add(c.end.Sub(c.begin) / 2)
+		index, ok := g.nearest(middle, c.span.timeSpan)
+		if !ok {
+			continue
+		}
+		distance := g.data.LockFreeGet(index).Timestamp.Sub(middle).Abs()
+		if best == -1 || distance < bestDistance {
+			best, bestDistance = index, distance
+		}
+	}
+	if best == -1 {
+		return ping.PingResults{}, false
+	}
+	return g.data.LockFreeGetFull(best), true
+}
+
+// Column is every point drawn in a column of the graph, see [Graph.InspectColumn].
+type Column struct {
+	// Begin and End are the times drawn in the column, up to but not including the end.
+	Begin, End time.Time
+	Stats      *data.Stats
+	// IPs are the distinct IPs pinged in the column, in the order they were first pinged.
+	IPs []net.IP
+}
+
+// InspectColumn is every point drawn in the [column] of the last frame, false if the column isn't part of
+// the graph. The points are found from the x-axis of the last frame rather than it's [drawWindow], which only
+// keeps how many points were drawn in each cell and not when they were or which IP was pinged.
+func (g *Graph) InspectColumn(column int) (Column, bool) {
+	g.frameMutex.Lock()
+	x, y := g.lastFrame.xAxis, g.lastFrame.yAxis
+	g.frameMutex.Unlock()
+	columns := x.columnTimes(column, y)
+	if len(columns) == 0 {
+		return Column{}, false
+	}
+	ret := Column{Begin: columns[0].begin, End: columns[len(columns)-1].end, Stats: &data.Stats{}, IPs: []net.IP{}}
+	g.data.Lock()
+	defer g.data.Unlock()
+	for _, c := range columns {
+		last := g.data.LockFreeIndexAt(c.end)
+		for i := g.data.LockFreeIndexAt(c.begin); i < last; i++ {
+			p := g.data.LockFreeGetFull(i)
+			if p.Data.Dropped() {
+				ret.Stats.AddDroppedPacket()
+			} else {
+				ret.Stats.AddPoint(p.Data.Duration)
+			}
+			if !slices.ContainsFunc(ret.IPs, p.IP.Equal) {
+				ret.IPs = append(ret.IPs, p.IP)
+			}
+		}
+	}
+	return ret, true
+}
+
+// PlotColumns are the first and last columns of the last frame which points are drawn in, false if nothing
+// has been drawn yet.
+func (g *Graph) PlotColumns() (first, last int, ok bool) {
+	g.frameMutex.Lock()
+	defer g.frameMutex.Unlock()
+	x, y := g.lastFrame.xAxis, g.lastFrame.yAxis
+	if len(x.spans) == 0 {
+		return 0, 0, false
+	}
+	first, _ = x.spanColumns(x.spans[0], y)
+	_, last = x.spanColumns(x.spans[len(x.spans)-1], y)
+	return first, last, first <= last
+}
+
+// nearest is the index of the point closest to [t] which is [within] the time span, must be called with the
+// data lock held.
+func (g *Graph) nearest(t time.Time, within *data.TimeSpan) (int64, bool) {
+	after := g.data.LockFreeIndexAt(t)
+	best, bestDistance := int64(-1), time.Duration(0)
+	for _, index := range []int64{after - 1, after} {
+		if index < 0 || index >= g.data.LockFreeTotalCount() {
+			continue
+		}
+		timestamp := g.data.LockFreeGet(index).Timestamp
+		if timestamp.Before(within.Begin) || timestamp.After(within.End) {
+			continue
+		}
+		distance := timestamp.Sub(t).Abs()
+		if best == -1 || distance < bestDistance {
+			best, bestDistance = index, distance
+		}
+	}
+	return best, best != -1
+}
+
+// columnTime is the part of a span drawn in a column, from [begin] up to but not including [end].
+type columnTime struct {
+	span       *XAxisSpanInfo
+	begin, end time.Time
+}
+
+// columnTimes are the times which [getX] draws in the [column] for each span drawn in it. It's usually only
+// one span but neighbouring spans share the column between them, and none if the column isn't part of any.
+func (x drawingXAxis) columnTimes(column int, y drawingYAxis) []columnTime {
+	ret := []columnTime{}
+	for _, span := range x.spans {
+		left, right := x.spanColumns(span, y)
+		if column < left || column > right {
+			continue
+		}
+		ts := span.timeSpan
+		if right == left || ts.Duration == 0 {
+			ret = append(ret, columnTime{span: span, begin: ts.Begin, end: ts.End.Add(time.Nanosecond)})
+			continue
+		}
+		perColumn := float64(ts.Duration) / float64(right-left)
+		c := columnTime{
+			span:  span,
+			begin: ts.End.Add(-time.Duration(float64(right-column) * perColumn)),
+			end:   ts.End.Add(-time.Duration(float64(right-column-1) * perColumn)),
+		}
+		if c.begin.Before(ts.Begin) {
+			c.begin = ts.Begin
+		}
+		if column == right {
+			// The right most column is only the latest time.
+			c.end = ts.End.Add(time.Nanosecond)
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// spanColumns are the left and right most columns the [span] is drawn in, the same bounds as [getX] where the
+// right most column is the latest time.
+func (x drawingXAxis) spanColumns(span *XAxisSpanInfo, y drawingYAxis) (left, right int) {
+	return max(y.labelSize, span.startX), min(max(1, x.size-1), span.endX)
+}
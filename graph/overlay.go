@@ -0,0 +1,83 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/terminal/typography"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+)
+
+func overlayStartUp() {
+	overlayPoint = themes.Secondary(typography.HollowBullet)
+}
+
+var overlayPoint string
+
+// OverlayKey is the symbol each point of [GraphConfiguration.Overlay] is drawn with, for use in a legend.
+func OverlayKey() string {
+	return overlayPoint
+}
+
+// overlayBounds are the y-axis bounds which fit both the graph's data and the overlay.
+func overlayBounds(stats *data.Stats, overlay *data.Data) *data.Stats {
+	if overlay == nil || overlay.Header.Stats.GoodCount == 0 || stats.GoodCount == 0 {
+		return stats
+	}
+	return stats.Merge(overlay.Header.Stats)
+}
+
+// drawOverlay draws every good point of [overlay] as if it started at [begin], the beginning of the graph's
+// data, so that two captures can be compared. Points which don't land in any of the x-axis spans aren't
+// drawn, the overlay doesn't change the x-axis.
+func drawOverlay(
+	toWriteTo *bytes.SafeBuffer,
+	overlay *data.Data,
+	begin time.Time,
+	xAxis drawingXAxis,
+	yAxis drawingYAxis,
+	s terminal.Size,
+) {
+	if overlay == nil {
+		return
+	}
+	offset := begin.Sub(overlay.Header.TimeSpan.Begin)
+	drawn := map[coords]struct{}{}
+	for i := range overlay.TotalCount {
+		p := overlay.Get(i)
+		if p.Dropped() {
+			continue
+		}
+		p.Timestamp = p.Timestamp.Add(offset)
+		span := xAxis.spanOf(p.Timestamp)
+		if span == nil {
+			continue
+		}
+		x, y := translate(p, span, yAxis, s)
+		c := coords{x: x, y: y}
+		if _, found := drawn[c]; found || x < 1 || x > s.Width || y < 1 || y > s.Height {
+			continue
+		}
+		drawn[c] = struct{}{}
+		toWriteTo.WriteString(ansi.CursorPosition(y, x) + overlayPoint)
+	}
+}
+
+// spanOf is the span which contains [t] or nil if it's in a gap between spans or outside the axis.
+func (x drawingXAxis) spanOf(t time.Time) *XAxisSpanInfo {
+	for _, span := range x.spans {
+		if span.timeSpan.Contains(t) {
+			return span
+		}
+	}
+	return nil
+}
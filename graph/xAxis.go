@@ -8,7 +8,9 @@ package graph
 
 import (
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Lexer747/acci-ping/graph/data"
 	"github.com/Lexer747/acci-ping/graph/graphdata"
@@ -119,19 +121,28 @@ func computeXAxis(
 	for _, span := range xAxisSpans {
 		span.startX = min(s.Width, s.Width-remaining)
 
-		start, times := span.timeSpan.FormatDraw(span.width, 2)
-		if len(times) < 1 {
-			toCrop := max(min(span.width-2, len(start)-1), 0)
-			cropped := start[:toCrop]
-			remaining -= len(cropped) + 2
-			fmt.Fprintf(toWriteTo, "%s", themes.Emphasis(cropped))
-			toWriteTo.WriteString(padding + padding)
-		} else {
-			remaining -= len(start) + 4 + 2
-			fmt.Fprint(toWriteTo, themes.Primary("[ ")+themes.Emphasis(start)+themes.Primary(" ]"))
-			toWriteTo.WriteString(padding + padding)
-			remaining = xAxisDrawTimes(toWriteTo, times, remaining, padding)
+		jitter := jitterLabel(span.pingStats)
+		// The brackets and padding
+		jitterWidth := utf8.RuneCountInString(jitter) + 6
+		if jitter != "" && span.width < 3*jitterWidth {
+			jitter = ""
+		}
+		times := bytes.NewSafeBuffer()
+		afterTimes := xAxisDrawSpanTimes(times, span, span.width, remaining)
+		if jitter != "" {
+			withJitter := bytes.NewSafeBuffer()
+			afterJitter := xAxisDrawSpanTimes(withJitter, span, span.width-jitterWidth, remaining) - jitterWidth
+			// The jitter is only drawn if it fits in the columns the times alone would take, so that the end of
+			// the span and therefore every point is in the same place either way.
+			if afterJitter >= afterTimes {
+				fmt.Fprint(withJitter, themes.Primary("( ")+themes.Emphasis(jitter)+themes.Primary(" )"))
+				withJitter.WriteString(padding + padding)
+				withJitter.WriteString(strings.Repeat(padding, afterJitter-afterTimes))
+				times = withJitter
+			}
 		}
+		toWriteTo.WriteString(times.String())
+		remaining = afterTimes
 
 		span.endX = min(s.Width, s.Width-remaining)
 	}
@@ -215,6 +226,33 @@ func combineSpansPixelWise(spans []*graphdata.SpanInfo, startingWidth, total int
 	return retSpans
 }
 
+// jitterLabel summarises the jitter of a span for the x-axis, empty if the span doesn't have any.
+func jitterLabel(stats *data.Stats) string {
+	if stats.GoodCount < 2 {
+		return ""
+	}
+	return fmt.Sprintf("jitter %s, mean %s",
+		time.Duration(numeric.RoundToNearestSigFig(float64(stats.Jitter()), 3)),
+		time.Duration(numeric.RoundToNearestSigFig(float64(stats.MeanJitter()), 3)))
+}
+
+// xAxisDrawSpanTimes draws the start of the [span] and as many of the times after it which fit in [width],
+// returning the [remaining] width of the axis after them.
+func xAxisDrawSpanTimes(b *bytes.SafeBuffer, span *XAxisSpanInfo, width int, remaining int) int {
+	start, times := span.timeSpan.FormatDraw(width, 2)
+	if len(times) < 1 {
+		toCrop := max(min(span.width-2, len(start)-1), 0)
+		cropped := start[:toCrop]
+		fmt.Fprintf(b, "%s", themes.Emphasis(cropped))
+		b.WriteString(padding + padding)
+		return remaining - (len(cropped) + 2)
+	}
+	remaining -= len(start) + 4 + 2
+	fmt.Fprint(b, themes.Primary("[ ")+themes.Emphasis(start)+themes.Primary(" ]"))
+	b.WriteString(padding + padding)
+	return xAxisDrawTimes(b, times, remaining, padding)
+}
+
 func xAxisDrawTimes(b *bytes.SafeBuffer, times []string, remaining int, padding string) int {
 	for _, point := range times {
 		if remaining <= len(point) {
@@ -69,7 +69,7 @@ func addYAxisVerticalSpanIndicator(bars *bytes.SafeBuffer, s terminal.Size, span
 func computeYAxis(
 	toWriteTo *bytes.SafeBuffer,
 	size terminal.Size,
-	stats *data.Stats,
+	stats, bounds *data.Stats,
 	url string,
 	scale YAxisScale,
 ) drawingYAxis {
@@ -88,7 +88,7 @@ func computeYAxis(
 		h := i + 2
 		fmt.Fprint(toWriteTo, ansi.CursorPosition(h, 1))
 		if i%gapSize == 1 {
-			minY, maxY := effectiveYBounds(stats)
+			minY, maxY := effectiveYBounds(bounds)
 			var scaledDuration float64
 			switch scale {
 			case Linear:
@@ -109,7 +109,7 @@ func computeYAxis(
 	fmt.Fprint(toWriteTo, ansi.CursorPosition(max(1, size.Height-1), 1)+themes.Primary(typography.Vertical))
 	return drawingYAxis{
 		size:      size.Height,
-		stats:     stats,
+		stats:     bounds,
 		labelSize: min(durationSize+4, size.Width),
 		scale:     scale,
 	}
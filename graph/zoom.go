@@ -0,0 +1,241 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/gui/themes"
+	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
+	"github.com/Lexer747/acci-ping/utils/bytes"
+	"github.com/Lexer747/acci-ping/utils/numeric"
+)
+
+// Zoom is the window of time drawn on the x-axis, the zero value draws all of it.
+type Zoom struct {
+	// Window is how much time is drawn, zero is everything.
+	Window time.Duration
+	// End is the latest time drawn, zero follows the latest point.
+	End time.Time
+}
+
+func (z Zoom) Equal(other Zoom) bool {
+	return z.Window == other.Window && z.End.Equal(other.End)
+}
+
+// ZoomAction is a change to the [Zoom] of the graph.
+type ZoomAction byte
+
+const (
+	// ZoomIn halves the window, keeping the same centre.
+	ZoomIn ZoomAction = 0
+	// ZoomOut doubles the window, keeping the same centre, until everything is drawn.
+	ZoomOut ZoomAction = 1
+	// PanLeft moves the window back in time by a quarter of its width, it is no longer live.
+	PanLeft ZoomAction = 2
+	// PanRight moves the window forward in time by a quarter of its width, it's live again if it reaches the
+	// latest point.
+	PanRight ZoomAction = 3
+	// Live draws everything and follows the latest point again.
+	Live ZoomAction = 4
+)
+
+func (z ZoomAction) String() string {
+	switch z {
+	case ZoomIn:
+		return "ZoomIn"
+	case ZoomOut:
+		return "ZoomOut"
+	case PanLeft:
+		return "PanLeft"
+	case PanRight:
+		return "PanRight"
+	case Live:
+		return "Live"
+	default:
+		panic("exhaustive:enforce")
+	}
+}
+
+// minZoomWindow is the smallest window which can be zoomed into.
+const minZoomWindow = 10 * time.Second
+
+// within is the part of [extent] to draw, nil if it's all of it. A window which would be past either end of
+// the [extent] is moved back inside it.
+func (z Zoom) within(extent *data.TimeSpan) *data.TimeSpan {
+	if z.Window == 0 || z.Window >= extent.Duration {
+		return nil
+	}
+	end := extent.End
+	if !z.End.IsZero() && z.End.Before(end) {
+		end = z.End
+	}
+	begin := end.Add(-z.Window)
+	if begin.Before(extent.Begin) {
+		begin = extent.Begin
+		end = begin.Add(z.Window)
+	}
+	return &data.TimeSpan{Begin: begin, End: end, Duration: z.Window}
+}
+
+// zoom applies the [action] to [z], must be called with the data lock held.
+func (g *Graph) zoom(z Zoom, action ZoomAction, followLatestSpan bool) Zoom {
+	if g.data.LockFreeTotalCount() == 0 {
+		return z
+	}
+	extent := g.extent(followLatestSpan)
+	window := z.within(extent)
+	if window == nil && action != ZoomIn {
+		return Zoom{}
+	}
+	switch action {
+	case ZoomIn:
+		if window == nil {
+			window = extent
+		}
+		if window.Duration/2 < minZoomWindow {
+			return z
+		}
+		return z.recentre(window, window.Duration/2, extent)
+	case ZoomOut:
+		return z.recentre(window, window.Duration*2, extent)
+	case PanLeft:
+		return g.panBy(z, window, extent, -z.Window/4)
+	case PanRight:
+		return g.panBy(z, window, extent, z.Window/4)
+	case Live:
+		return Zoom{}
+	default:
+		panic("exhaustive:enforce")
+	}
+}
+
+// pan moves [z] by [columns] of a graph [width] columns wide, forward in time if positive, must be called with
+// the data lock held. It's [PanLeft] and [PanRight] but by as little as a column, e.g. for dragging the graph.
+func (g *Graph) pan(z Zoom, columns, width int, followLatestSpan bool) Zoom {
+	if g.data.LockFreeTotalCount() == 0 || columns == 0 || width <= 0 {
+		return z
+	}
+	extent := g.extent(followLatestSpan)
+	window := z.within(extent)
+	if window == nil {
+		// Everything is drawn so there's nowhere to pan to.
+		return z
+	}
+	return g.panBy(z, window, extent, z.Window*time.Duration(columns)/time.Duration(width))
+}
+
+// plotWidth is how many columns the points were drawn across in the last frame.
+func (g *Graph) plotWidth() int {
+	g.frameMutex.Lock()
+	defer g.frameMutex.Unlock()
+	return g.lastFrame.xAxis.size - g.lastFrame.yAxis.labelSize
+}
+
+// panBy moves the [window] of [z] by [d], skipping over any gap in the data so that there's always something
+// drawn. It's live again if it reaches the end of the [extent].
+func (g *Graph) panBy(z Zoom, window, extent *data.TimeSpan, d time.Duration) Zoom {
+	end := window.End.Add(d)
+	if d < 0 {
+		if before := g.data.LockFreeIndexAt(end.Add(-z.Window)); g.data.LockFreeIndexAt(end.Add(time.Nanosecond)) == before && before > 0 {
+			// Nothing in the window, so the right edge jumps back to the previous point instead.
+			end = g.data.LockFreeGet(before - 1).Timestamp
+		}
+		return Zoom{Window: z.Window, End: end}
+	}
+	if after := g.data.LockFreeIndexAt(end); after == g.data.LockFreeIndexAt(end.Add(-z.Window)) && after < g.data.LockFreeTotalCount() {
+		// Nothing in the window, so the left edge jumps forward to the next point instead.
+		end = g.data.LockFreeGet(after).Timestamp.Add(z.Window)
+	}
+	if !end.Before(extent.End) {
+		end = time.Time{}
+	}
+	return Zoom{Window: z.Window, End: end}
+}
+
+// recentre is a zoom of [width] with the same centre as [window], which is everything if it's wider than the
+// [extent] and live if it reaches the latest point.
+func (z Zoom) recentre(window *data.TimeSpan, width time.Duration, extent *data.TimeSpan) Zoom {
+	if width >= extent.Duration {
+		return Zoom{}
+	}
+	if z.End.IsZero() {
+		return Zoom{Window: width}
+	}
+	end := window.Begin.Add(window.Duration / 2).Add(width / 2)
+	if !end.Before(extent.End) {
+		end = time.Time{}
+	}
+	return Zoom{Window: width, End: end}
+}
+
+// extent is all the time which can be drawn, must be called with the data lock held.
+func (g *Graph) extent(followLatestSpan bool) *data.TimeSpan {
+	if followLatestSpan {
+		spans := g.data.LockFreeSpanInfos()
+		return spans[len(spans)-1].TimeSpan
+	}
+	return g.data.LockFreeHeader().TimeSpan
+}
+
+// viewStats are the ping stats of every span drawn, or [fallback] if none of them have a good point.
+func viewStats(spans []*XAxisSpanInfo, fallback *data.Stats) *data.Stats {
+	var ret *data.Stats
+	for _, span := range spans {
+		// Merging needs good points on both sides.
+		switch {
+		case span.pingStats.GoodCount == 0:
+		case ret == nil:
+			ret = span.pingStats
+		default:
+			ret = ret.Merge(span.pingStats)
+		}
+	}
+	if ret == nil {
+		return fallback
+	}
+	return ret
+}
+
+// drawZoomIndicator writes which part of the [extent] is drawn in the bottom right corner of the graph, e.g.
+// "viewing 14:02–14:17 of 3h".
+func drawZoomIndicator(toWriteTo *bytes.SafeBuffer, window, extent *data.TimeSpan, s terminal.Size) {
+	if window == nil {
+		return
+	}
+	layout := "15:04"
+	switch {
+	case window.Begin.YearDay() != window.End.YearDay() || extent.Begin.YearDay() != extent.End.YearDay():
+		layout = "Jan 2 15:04"
+	case window.Duration < 10*time.Minute:
+		layout = "15:04:05"
+	}
+	text := fmt.Sprintf("viewing %s–%s of %s",
+		window.Begin.Format(layout), window.End.Format(layout), shortDuration(extent.Duration))
+	x := s.Width - utf8.RuneCountInString(text)
+	if x < 1 || s.Height < 3 {
+		return
+	}
+	toWriteTo.WriteString(ansi.CursorPosition(s.Height-1, x) + themes.Emphasis(text))
+}
+
+// shortDuration is [d] to two significant figures without any trailing zero units, e.g. "3h" not "3h0m0s".
+func shortDuration(d time.Duration) string {
+	ret := time.Duration(numeric.RoundToNearestSigFig(float64(d), 2)).String()
+	if strings.HasSuffix(ret, "m0s") {
+		ret = strings.TrimSuffix(ret, "0s")
+	}
+	if strings.HasSuffix(ret, "h0m") {
+		ret = strings.TrimSuffix(ret, "0m")
+	}
+	return ret
+}
@@ -67,6 +67,20 @@ func (b Box) Draw(size terminal.Size, buf *bytes.SafeBuffer) {
 	buf.WriteString(ansi.CursorPosition(p.startY+end+2, p.startX) + corners.BottomLeft + bar + corners.BottomRight)
 }
 
+// At is the box moved so that its top left corner is drawn at [row] and [column] of a terminal of [size], or
+// as near as it can be while the whole box is still on screen.
+func (b Box) At(row, column int, size terminal.Size) Box {
+	row = max(min(row, size.Height-b.height(size)+1), 1)
+	column = max(min(column, size.Width-b.width(size)+1), 1)
+	b.Position = Position{
+		Vertical:   Top,
+		Horizontal: Left,
+		// The padding moves the box from where it would be in the top left, see [Box.position].
+		Padding: Padding{Bottom: row - b.height(size), Right: column - b.widthFromStyle()},
+	}
+	return b
+}
+
 type boxPosition struct {
 	startY, startX int
 }
@@ -18,6 +18,7 @@ import (
 
 	"github.com/Lexer747/acci-ping/gui"
 	"github.com/Lexer747/acci-ping/terminal"
+	"github.com/Lexer747/acci-ping/terminal/ansi"
 	"github.com/Lexer747/acci-ping/utils/bytes"
 	"github.com/Lexer747/acci-ping/utils/env"
 	"github.com/Lexer747/acci-ping/utils/sliceutils"
@@ -213,6 +214,32 @@ func (tc *testCase) getOutputFileName() string {
 	return strings.ReplaceAll(ret, " ", "_")
 }
 
+func TestBoxAt(t *testing.T) {
+	t.Parallel()
+	size := terminal.Size{Height: 20, Width: 40}
+	box := gui.Box{
+		BoxText: []gui.Typography{{ToPrint: "12345", LenFromToPrint: true, Alignment: gui.Left}, {ToPrint: "1", LenFromToPrint: true, Alignment: gui.Left}},
+		Style:   gui.RoundedCorners,
+	}
+	// The box is 4 rows and 7 columns with the border.
+	tests := []struct {
+		row, column                 int
+		expectedRow, expectedColumn int
+	}{
+		{row: 5, column: 10, expectedRow: 5, expectedColumn: 10},
+		{row: 1, column: 1, expectedRow: 1, expectedColumn: 1},
+		{row: 4, column: 2, expectedRow: 4, expectedColumn: 2},
+		{row: 20, column: 40, expectedRow: 17, expectedColumn: 34},
+		{row: -3, column: 0, expectedRow: 1, expectedColumn: 1},
+	}
+	for _, test := range tests {
+		b := bytes.NewSafeBuffer()
+		box.At(test.row, test.column, size).Draw(size, b)
+		expected := ansi.CursorPosition(test.expectedRow, test.expectedColumn)
+		assert.Assert(t, strings.HasPrefix(b.String(), expected), "%d,%d: %q", test.row, test.column, b.String())
+	}
+}
+
 type testCase struct {
 	text     []string
 	position gui.Position
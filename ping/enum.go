@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -24,6 +24,16 @@ func (d Dropped) String() string {
 	}
 }
 
+// ParseDropped is the inverse of [Dropped.String], the empty string is [NotDropped].
+func ParseDropped(s string) (Dropped, bool) {
+	for _, d := range []Dropped{NotDropped, Timeout, DNSFailure, BadResponse, TestDrop} {
+		if d.String() == s {
+			return d, true
+		}
+	}
+	return NotDropped, false
+}
+
 func (at addressType) String() string {
 	switch at {
 	case _IP4:
@@ -73,6 +73,13 @@ const (
 	FormattingReset = CSI + "0m"
 	HideCursor      = CSI + "?25l"
 	ShowCursor      = CSI + "?25h"
+	// BracketedPasteOn makes the terminal surround pasted text with "CSI 200~" and "CSI 201~".
+	BracketedPasteOn  = CSI + "?2004h"
+	BracketedPasteOff = CSI + "?2004l"
+	// MouseReportingOn makes the terminal report every mouse press, release and motion as an SGR encoded
+	// "CSI < b ; x ; y M" sequence.
+	MouseReportingOn  = CSI + "?1003h" + CSI + "?1006h"
+	MouseReportingOff = CSI + "?1006l" + CSI + "?1003l"
 )
 
 // CursorPosition compacted when defaults are passed, some chars may be elided:
@@ -0,0 +1,391 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package terminal
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Key is a single key press decoded from the terminal input, either a typed rune or one of the special keys
+// which the terminal sends as an escape sequence. Control characters such as enter ('\r'), tab, backspace
+// ('\x7f') and escape are typed runes.
+type Key struct {
+	// Rune is the rune typed when [Code] is [KeyRune].
+	Rune rune
+	Code KeyCode
+	// Modifiers held while the key was pressed, not every terminal reports them for every key.
+	Modifiers Modifier
+	// Paste is the text pasted when [Code] is [KeyPaste].
+	Paste string
+	// Mouse is the mouse event when [Code] is [KeyMouse].
+	Mouse Mouse
+}
+
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyInsert
+	KeyDelete
+	KeyPageUp
+	KeyPageDown
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	// KeyPaste is text pasted in the terminal while bracketed paste is on, see [Key.Paste].
+	KeyPaste
+	// KeyMouse is a mouse event reported while mouse reporting is on, see [Key.Mouse].
+	KeyMouse
+	// KeyUnknown is an escape sequence which was well formed but isn't one of the keys above.
+	KeyUnknown
+)
+
+var keyCodeNames = map[KeyCode]string{
+	KeyUp: "up", KeyDown: "down", KeyRight: "right", KeyLeft: "left",
+	KeyHome: "home", KeyEnd: "end", KeyInsert: "insert", KeyDelete: "delete",
+	KeyPageUp: "pageup", KeyPageDown: "pagedown",
+	KeyF1: "f1", KeyF2: "f2", KeyF3: "f3", KeyF4: "f4", KeyF5: "f5", KeyF6: "f6",
+	KeyF7: "f7", KeyF8: "f8", KeyF9: "f9", KeyF10: "f10", KeyF11: "f11", KeyF12: "f12",
+	KeyPaste: "paste", KeyMouse: "mouse", KeyUnknown: "unknown",
+}
+
+// Mouse is a single mouse event, the button pressed or released or the mouse moving.
+type Mouse struct {
+	// X and Y are the column and row of the event, starting from 1 in the top left like
+	// [ansi.CursorPosition].
+	X, Y   int
+	Button MouseButton
+	Action MouseAction
+}
+
+type MouseButton byte
+
+const (
+	// MouseNone is the button of a motion event while no button is held.
+	MouseNone MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+)
+
+type MouseAction byte
+
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	// MouseMotion is the mouse moving, [Mouse.Button] is the button held while it moved.
+	MouseMotion
+)
+
+// Modifier is a bit set of the modifier keys held during a key press.
+type Modifier byte
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+// String is the name of the key as it would be written in documentation e.g. "ctrl+left" or "alt+x".
+func (k Key) String() string {
+	var b strings.Builder
+	if k.Modifiers&ModCtrl != 0 {
+		b.WriteString("ctrl+")
+	}
+	if k.Modifiers&ModAlt != 0 {
+		b.WriteString("alt+")
+	}
+	if k.Modifiers&ModShift != 0 {
+		b.WriteString("shift+")
+	}
+	if k.Code != KeyRune {
+		b.WriteString(keyCodeNames[k.Code])
+		return b.String()
+	}
+	switch k.Rune {
+	case '\r', '\n':
+		b.WriteString("enter")
+	case '\t':
+		b.WriteString("tab")
+	case '\x1b':
+		b.WriteString("escape")
+	case '\x7f', '\b':
+		b.WriteString("backspace")
+	case ' ':
+		b.WriteString("space")
+	default:
+		if k.Rune < ' ' {
+			b.WriteString("ctrl+" + string('a'+k.Rune-1))
+		} else {
+			b.WriteRune(k.Rune)
+		}
+	}
+	return b.String()
+}
+
+// KeyListener is like a [ConditionalListener] but for every decoded [Key], including the special keys which
+// don't have a rune.
+type KeyListener struct {
+	// Applicable is the applicability of this listener, i.e. for which keys do you want this action to be
+	// fired.
+	Applicable func(Key) bool
+	// Action the callback which is invoked with the applicable key, see [Listener.Action].
+	Action func(Key) error
+	// Name is used for if a listener errors for easier identification, it may be omitted.
+	Name string
+}
+
+const esc = '\x1b'
+
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+)
+
+// keyDecoder decodes the bytes read from the terminal into keys, the escape sequences and pastes can be split
+// across reads so the decoder keeps what's incomplete until the next read.
+type keyDecoder struct {
+	pending []byte
+	pasting bool
+	paste   []byte
+}
+
+// decode the keys of [input] following on from anything pending from the last read. If [more] then the read
+// filled the buffer and so an incomplete sequence at the end is kept for the next read, otherwise it's decoded
+// as the runes it's made of (e.g. a lone escape key press).
+func (d *keyDecoder) decode(input []byte, more bool) []Key {
+	buf := append(d.pending, input...)
+	d.pending = nil
+	keys := []Key{}
+	for len(buf) > 0 {
+		if d.pasting {
+			end := bytes.Index(buf, pasteEnd)
+			if end == -1 {
+				// The end of the buffer could be the start of the end of the paste.
+				keep := partialPrefix(buf, pasteEnd)
+				d.paste = append(d.paste, buf[:len(buf)-keep]...)
+				d.pending = bytes.Clone(buf[len(buf)-keep:])
+				return keys
+			}
+			d.paste = append(d.paste, buf[:end]...)
+			keys = append(keys, Key{Code: KeyPaste, Paste: string(d.paste)})
+			d.pasting = false
+			d.paste = nil
+			buf = buf[end+len(pasteEnd):]
+			continue
+		}
+		if bytes.HasPrefix(buf, pasteStart) {
+			d.pasting = true
+			buf = buf[len(pasteStart):]
+			continue
+		}
+		key, n := decodeKey(buf)
+		if n == 0 {
+			if more {
+				d.pending = bytes.Clone(buf)
+				return keys
+			}
+			key, n = decodeRune(buf)
+		}
+		keys = append(keys, key)
+		buf = buf[n:]
+	}
+	return keys
+}
+
+// partialPrefix is the length of the longest suffix of [buf] which is a prefix of [sequence].
+func partialPrefix(buf, sequence []byte) int {
+	for n := min(len(buf), len(sequence)-1); n > 0; n-- {
+		if bytes.HasPrefix(sequence, buf[len(buf)-n:]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// decodeKey decodes the first key of [buf] returning the key and how many bytes it was, zero if [buf] ends
+// before the key does.
+func decodeKey(buf []byte) (Key, int) {
+	if buf[0] != esc {
+		if !utf8.FullRune(buf) {
+			return Key{}, 0
+		}
+		return decodeRune(buf)
+	}
+	if len(buf) < 2 {
+		return Key{}, 0
+	}
+	switch buf[1] {
+	case '[':
+		return decodeCSI(buf)
+	case 'O':
+		if len(buf) < 3 {
+			return Key{}, 0
+		}
+		code, ok := ss3Keys[buf[2]]
+		if !ok {
+			code = KeyUnknown
+		}
+		return Key{Code: code}, 3
+	case esc:
+		return Key{Rune: esc}, 1
+	default:
+		// Escape followed by a rune is how the terminal sends alt+rune.
+		if !utf8.FullRune(buf[1:]) {
+			return Key{}, 0
+		}
+		key, n := decodeRune(buf[1:])
+		key.Modifiers |= ModAlt
+		return key, n + 1
+	}
+}
+
+func decodeRune(buf []byte) (Key, int) {
+	r, n := utf8.DecodeRune(buf)
+	return Key{Rune: r}, n
+}
+
+var ss3Keys = map[byte]KeyCode{
+	'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft, 'H': KeyHome, 'F': KeyEnd,
+	'P': KeyF1, 'Q': KeyF2, 'R': KeyF3, 'S': KeyF4,
+}
+
+var tildeKeys = map[int]KeyCode{
+	1: KeyHome, 2: KeyInsert, 3: KeyDelete, 4: KeyEnd, 5: KeyPageUp, 6: KeyPageDown, 7: KeyHome, 8: KeyEnd,
+	11: KeyF1, 12: KeyF2, 13: KeyF3, 14: KeyF4, 15: KeyF5, 17: KeyF6, 18: KeyF7, 19: KeyF8, 20: KeyF9,
+	21: KeyF10, 23: KeyF11, 24: KeyF12,
+}
+
+// decodeCSI decodes a control sequence, "ESC [" then any parameter bytes, any intermediate bytes and a final
+// byte. The parameters are numbers separated by ';' where the second is the modifiers (plus one).
+func decodeCSI(buf []byte) (Key, int) {
+	i := 2
+	for i < len(buf) && buf[i] >= 0x30 && buf[i] <= 0x3F {
+		i++
+	}
+	paramsEnd := i
+	for i < len(buf) && buf[i] >= 0x20 && buf[i] <= 0x2F {
+		i++
+	}
+	if i >= len(buf) {
+		return Key{}, 0
+	}
+	final := buf[i]
+	n := i + 1
+	if final < 0x40 || final > 0x7E {
+		// Not a control sequence after all, so it's alt+[ and the rest is typed after.
+		return Key{Rune: '[', Modifiers: ModAlt}, 2
+	}
+	if paramsEnd > 2 && buf[2] == '<' && (final == 'M' || final == 'm') {
+		return decodeSGRMouse(string(buf[3:paramsEnd]), final), n
+	}
+	if paramsEnd > 2 && bytes.IndexByte([]byte("<=>?"), buf[2]) != -1 {
+		// A private sequence e.g. "ESC [ ? ...", which isn't a key.
+		return Key{Code: KeyUnknown}, n
+	}
+	params := strings.Split(string(buf[2:paramsEnd]), ";")
+	param := func(index, fallback int) int {
+		if index >= len(params) {
+			return fallback
+		}
+		p, err := strconv.Atoi(params[index])
+		if err != nil {
+			return fallback
+		}
+		return p
+	}
+	modifiers := Modifier(max(param(1, 1)-1, 0)) & (ModShift | ModAlt | ModCtrl)
+	switch final {
+	case '~':
+		code, ok := tildeKeys[param(0, 0)]
+		if !ok {
+			return Key{Code: KeyUnknown}, n
+		}
+		return Key{Code: code, Modifiers: modifiers}, n
+	case 'u':
+		// The "CSI u" encoding of a rune with modifiers.
+		return Key{Rune: rune(param(0, 0)), Modifiers: modifiers}, n
+	case 'Z':
+		return Key{Rune: '\t', Modifiers: ModShift}, n
+	default:
+		code, ok := ss3Keys[final]
+		if !ok {
+			return Key{Code: KeyUnknown}, n
+		}
+		return Key{Code: code, Modifiers: modifiers}, n
+	}
+}
+
+// decodeSGRMouse decodes the parameters of an SGR mouse event "ESC [ < b ; x ; y M", where the final byte is
+// 'm' for a release. The low bits of b are the button and the higher bits are the modifiers, motion and wheel.
+func decodeSGRMouse(params string, final byte) Key {
+	fields := strings.Split(params, ";")
+	if len(fields) != 3 {
+		return Key{Code: KeyUnknown}
+	}
+	var values [3]int
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return Key{Code: KeyUnknown}
+		}
+		values[i] = v
+	}
+	b := values[0]
+	var modifiers Modifier
+	if b&4 != 0 {
+		modifiers |= ModShift
+	}
+	if b&8 != 0 {
+		modifiers |= ModAlt
+	}
+	if b&16 != 0 {
+		modifiers |= ModCtrl
+	}
+	m := Mouse{X: values[1], Y: values[2], Action: MousePress}
+	switch {
+	case b&64 != 0:
+		// The wheel, only up and down are buttons and there's no release.
+		switch b & 3 {
+		case 0:
+			m.Button = MouseWheelUp
+		case 1:
+			m.Button = MouseWheelDown
+		default:
+			return Key{Code: KeyUnknown}
+		}
+	default:
+		m.Button = [...]MouseButton{MouseLeft, MouseMiddle, MouseRight, MouseNone}[b&3]
+		switch {
+		case b&32 != 0:
+			m.Action = MouseMotion
+		case final == 'm':
+			m.Action = MouseRelease
+		}
+	}
+	return Key{Code: KeyMouse, Modifiers: modifiers, Mouse: m}
+}
@@ -63,6 +63,8 @@ type Terminal struct {
 	cleanup              func()
 	fallbacks            []Listener
 	listeners            []ConditionalListener
+	keyListeners         []KeyListener
+	decoder              keyDecoder
 	size                 atomic.Of[Size]
 	stdinFd              int
 	backgroundColour     themes.Luminance
@@ -226,7 +228,9 @@ func (userControlCErr) Error() string {
 
 // StartRaw takes ownership of the stdin/stdout and control of the incoming context. It will asynchronously
 // block on the users input and forward characters to the relevant listener. By default a `ctrl+C` listener is
-// added which will call the [stop] function when detected.
+// added which will call the [stop] function when detected. The [keyListeners] are given every key decoded
+// from the input, see [KeyListener], and bracketed paste is turned on so that pasted text is a single
+// [KeyPaste].
 //
 // The first return value is a clean up function which recover from a panic, putting the terminal back into
 // normal mode and unhooking the listeners so that the program terminates gracefully upon a panic in another
@@ -245,6 +249,7 @@ func (t *Terminal) StartRaw(
 	ctx context.Context,
 	stop context.CancelCauseFunc,
 	listeners []ConditionalListener,
+	keyListeners []KeyListener,
 	fallbacks []Listener,
 ) (func(), error) {
 	restore := func() {}
@@ -259,7 +264,7 @@ func (t *Terminal) StartRaw(
 		restore = func() { _ = term.Restore(t.stdinFd, oldState) }
 	}
 	ctrlCAction := func(rune) error {
-		t.Print(ansi.ShowCursor)
+		t.Print(ansi.MouseReportingOff + ansi.BracketedPasteOff + ansi.ShowCursor)
 		restore()
 		stop(UserCancelled)
 		return nil
@@ -276,10 +281,11 @@ func (t *Terminal) StartRaw(
 		},
 	}
 	t.listeners = slices.Concat(t.listeners, []ConditionalListener{controlCListener}, listeners)
+	t.keyListeners = slices.Concat(t.keyListeners, keyListeners)
 	if fallbacks != nil {
 		t.fallbacks = fallbacks
 	}
-	t.Print(ansi.HideCursor)
+	t.Print(ansi.HideCursor + ansi.BracketedPasteOn + ansi.MouseReportingOn)
 	go t.beingListening(ctx)
 	return t.cleanup, nil
 }
@@ -385,10 +391,10 @@ func (t *Terminal) beingListening(ctx context.Context) {
 			if received.n <= 0 {
 				return // cancelled
 			}
-			heard := string(buffer[:received.n])
-			slog.Debug("got keyboard input", "received", heard)
-			for _, r := range heard {
-				t.processListenedRune(r)
+			slog.Debug("got keyboard input", "received", string(buffer[:received.n]))
+			// A full buffer probably means there's more to read, e.g. the rest of a paste.
+			for _, key := range t.decoder.decode(buffer[:received.n], received.n == len(buffer)) {
+				t.processKey(key)
 			}
 			// if we don't have the processing signal this clear would be racey against stdin.
 			bytes.Clear(buffer, received.n)
@@ -397,9 +403,39 @@ func (t *Terminal) beingListening(ctx context.Context) {
 	}
 }
 
-// processListenedRune should only be called by the listener thread
-func (t *Terminal) processListenedRune(r rune) {
+// processKey should only be called by the listener thread. Every key goes to the key listeners, a typed rune
+// without modifiers also goes to the rune listeners and if none of the listeners applied to it, the fallbacks.
+// Pasted text only goes to the fallbacks so that it can't trigger the other listeners.
+func (t *Terminal) processKey(k Key) {
 	runFallback := true
+	for _, l := range t.keyListeners {
+		if !l.Applicable(k) {
+			continue
+		}
+		err := l.Action(k)
+		if err != nil {
+			panic(errors.Wrapf(err, "unexpected failure Action %q in terminal", l.Name))
+		}
+		runFallback = false
+	}
+	switch {
+	case k.Code == KeyRune && k.Modifiers == 0:
+		if t.processListenedRune(k.Rune) {
+			runFallback = false
+		}
+		if runFallback {
+			t.processFallbacks(k.Rune)
+		}
+	case k.Code == KeyPaste && runFallback:
+		for _, r := range k.Paste {
+			t.processFallbacks(r)
+		}
+	}
+}
+
+// processListenedRune should only be called by the listener thread, it returns true if any listener applied.
+func (t *Terminal) processListenedRune(r rune) bool {
+	applied := false
 	for _, l := range t.listeners {
 		if !l.Applicable(r) {
 			continue
@@ -408,14 +444,17 @@ func (t *Terminal) processListenedRune(r rune) {
 		if err != nil {
 			panic(errors.Wrapf(err, "unexpected failure Action %q in terminal", l.Name))
 		}
-		runFallback = false
+		applied = true
 	}
-	if runFallback {
-		for _, l := range t.fallbacks {
-			err := l.Action(r)
-			if err != nil {
-				panic(errors.Wrapf(err, "unexpected failure Action %q in terminal", l.Name))
-			}
+	return applied
+}
+
+// processFallbacks should only be called by the listener thread
+func (t *Terminal) processFallbacks(r rune) {
+	for _, l := range t.fallbacks {
+		err := l.Action(r)
+		if err != nil {
+			panic(errors.Wrapf(err, "unexpected failure Action %q in terminal", l.Name))
 		}
 	}
 }
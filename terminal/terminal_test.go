@@ -9,6 +9,8 @@ package terminal_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -24,11 +26,11 @@ func TestTerminalWrite(t *testing.T) {
 	assert.NilError(t, err)
 	ctx, cancelFunc := context.WithCancelCause(t.Context())
 	defer cancelFunc(nil)
-	_, err = term.StartRaw(ctx, cancelFunc, nil, nil)
+	_, err = term.StartRaw(ctx, cancelFunc, nil, nil, nil)
 	assert.NilError(t, err)
 	const hello = "Hello world"
 	term.Print(hello)
-	assert.Equal(t, ansi.HideCursor+hello, stdout.ReadString(t))
+	assert.Equal(t, ansi.HideCursor+ansi.BracketedPasteOn+ansi.MouseReportingOn+hello, stdout.ReadString(t))
 }
 
 func TestTerminalReading(t *testing.T) {
@@ -39,7 +41,7 @@ func TestTerminalReading(t *testing.T) {
 	ctx, cancelFunc := context.WithTimeoutCause(t.Context(), time.Second, timeout)
 	cancelWithCause := func(err error) { cancelFunc() }
 	defer cancelWithCause(nil)
-	_, err = term.StartRaw(ctx, cancelWithCause, nil, nil)
+	_, err = term.StartRaw(ctx, cancelWithCause, nil, nil, nil)
 	assert.NilError(t, err)
 	_, _ = stdin.Write([]byte("\x03")) // ctrl-c will cause the terminal to cancel
 
@@ -70,7 +72,7 @@ func TestTerminalListener(t *testing.T) {
 			},
 		},
 	}
-	_, err = term.StartRaw(ctx, cancelFunc, []terminal.ConditionalListener{testListener}, nil)
+	_, err = term.StartRaw(ctx, cancelFunc, []terminal.ConditionalListener{testListener}, nil, nil)
 	assert.NilError(t, err)
 	_ = stdout.ReadString(t)
 	_, _ = stdin.Write([]byte("a"))
@@ -121,7 +123,7 @@ func TestTerminalFallbackListener(t *testing.T) {
 			return nil
 		},
 	}
-	_, err = term.StartRaw(ctx, cancelFunc, []terminal.ConditionalListener{testListener}, []terminal.Listener{fallback})
+	_, err = term.StartRaw(ctx, cancelFunc, []terminal.ConditionalListener{testListener}, nil, []terminal.Listener{fallback})
 	assert.NilError(t, err)
 	_, _ = stdin.Write([]byte("a"))
 	m1 <- struct{}{}
@@ -136,3 +138,92 @@ func TestTerminalFallbackListener(t *testing.T) {
 	<-m2
 	assert.Equal(t, 'c', lastRune)
 }
+
+func TestTerminalKeyListener(t *testing.T) {
+	t.Parallel()
+	stdin, stdout, term, _, err := th.NewTestTerminal()
+	assert.NilError(t, err)
+	ctx, cancelFunc := context.WithCancelCause(t.Context())
+	defer cancelFunc(nil)
+	keyListener := terminal.KeyListener{
+		Applicable: func(k terminal.Key) bool { return true },
+		Action: func(k terminal.Key) error {
+			if k.Code == terminal.KeyPaste {
+				return term.Print("paste " + strconv.Quote(k.Paste) + ",")
+			}
+			if k.Code == terminal.KeyMouse {
+				m := k.Mouse
+				return term.Print(fmt.Sprintf("%s %d %d %d %d,", k, m.Button, m.Action, m.X, m.Y))
+			}
+			return term.Print(k.String() + ",")
+		},
+	}
+	_, err = term.StartRaw(ctx, cancelFunc, nil, []terminal.KeyListener{keyListener}, nil)
+	assert.NilError(t, err)
+	_ = stdout.ReadString(t)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "a", expected: "a,"},
+		{input: "\x1b[D", expected: "left,"},
+		{input: "\x1bOA", expected: "up,"},
+		{input: "\x1b[C\x1b[Bx", expected: "right,down,x,"},
+		{input: "\x1b[1;5D\x1b[1;2A\x1b[1;3C", expected: "ctrl+left,shift+up,alt+right,"},
+		{input: "\x1b[5~\x1b[6;5~\x1b[3~\x1b[H\x1b[4~", expected: "pageup,ctrl+pagedown,delete,home,end,"},
+		{input: "\x1bOP\x1b[15~\x1b[24;2~", expected: "f1,f5,shift+f12,"},
+		{input: "\x1b[Z\x1b[97;5u", expected: "shift+tab,ctrl+a,"},
+		{input: "\x1bx\x1b\x1b[A", expected: "alt+x,escape,up,"},
+		{input: "\x1b", expected: "escape,"},
+		{input: "\r\x7f\x04", expected: "enter,backspace,ctrl+d,"},
+		{input: "é😀", expected: "é,😀,"},
+		{input: "\x1b[?1;2c\x1b[99X", expected: "unknown,unknown,"},
+		// Longer than the terminal reads at once, so both are split across reads.
+		{input: "\x1b[200~a pasted line with \x1b[A in it\x1b[201~", expected: `paste "a pasted line with \x1b[A in it",`},
+		{input: "abcdefghijklmnopqrs\x1b[D", expected: "a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,left,"},
+		{input: "\x1b[<0;12;5M\x1b[<0;12;5m", expected: "mouse 1 0 12 5,mouse 1 1 12 5,"},
+		{input: "\x1b[<35;3;4M\x1b[<32;4;4M", expected: "mouse 0 2 3 4,mouse 1 2 4 4,"},
+		{input: "\x1b[<64;1;1M\x1b[<81;2;2M", expected: "mouse 4 0 1 1,ctrl+mouse 5 0 2 2,"},
+		{input: "\x1b[<66;1;1M\x1b[<1;2M", expected: "unknown,unknown,"},
+	}
+	for _, test := range tests {
+		_, _ = stdin.Write([]byte(test.input))
+		actual := ""
+		for len(actual) < len(test.expected) {
+			actual += stdout.ReadString(t)
+		}
+		assert.Equal(t, test.expected, actual, "%q", test.input)
+	}
+}
+
+func TestTerminalPasteFallback(t *testing.T) {
+	t.Parallel()
+	stdin, _, term, _, err := th.NewTestTerminal()
+	assert.NilError(t, err)
+	ctx, cancelFunc := context.WithCancelCause(t.Context())
+	defer cancelFunc(nil)
+	heard := make(chan rune)
+	defer close(heard)
+	listener := terminal.ConditionalListener{
+		Applicable: func(r rune) bool { return r == 'x' },
+		Listener: terminal.Listener{Action: func(r rune) error {
+			heard <- '!'
+			return nil
+		}},
+	}
+	fallback := terminal.Listener{Action: func(r rune) error {
+		heard <- r
+		return nil
+	}}
+	_, err = term.StartRaw(ctx, cancelFunc, []terminal.ConditionalListener{listener}, nil, []terminal.Listener{fallback})
+	assert.NilError(t, err)
+	// Pasted text is only given to the fallbacks, even the runes with a listener.
+	_, _ = stdin.Write([]byte("\x1b[200~xy\x1b[201~x"))
+	assert.Equal(t, 'x', <-heard)
+	assert.Equal(t, 'y', <-heard)
+	assert.Equal(t, '!', <-heard)
+	// And special keys aren't given to the rune listeners at all.
+	_, _ = stdin.Write([]byte("\x1b[Dz"))
+	assert.Equal(t, 'z', <-heard)
+}
@@ -10,6 +10,7 @@ const (
 	Bullet       = "\u2022"
 	HollowBullet = "\u25E6"
 	Diamond      = "\u25C6"
+	Star         = "\u2605"
 	Multiply     = "\u00D7"
 
 	HollowDownTriangle  = "\u25BD"
@@ -25,6 +26,7 @@ const (
 	Vertical         = "\u2502"
 	Horizontal       = "\u2500"
 	DoubleVertical   = "\u2551"
+	DashedVertical   = "\u250A"
 	DoubleHorizontal = "\u2550"
 
 	VerySteepUpSlope = "\u002F"
@@ -67,5 +69,6 @@ const (
 	DownArrow  = "\u2193"
 	LeftArrow  = "\u2194"
 
-	DoubleUpArrow = "\u21D1"
+	DoubleUpArrow     = "\u21D1"
+	UpDownDoubleArrow = "\u21D5"
 )
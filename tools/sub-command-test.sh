@@ -2,7 +2,7 @@
 
 # Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 #
-# Copyright 2025 Lexer747
+# Copyright 2025-2026 Lexer747
 #
 # SPDX-License-Identifier: GPL-2.0-only
 
@@ -41,6 +41,14 @@ test-cli 0 drawframe -debug-log "$ROOT"/tools/drawframe-verify.log "$ROOT/graph/
 test-cli 0 rawdata -all "$ROOT/graph/data/testdata/input/huge-over-days-2.pings" & pids+=($!)
 test-cli 0 rawdata -csv "$ROOT/graph/data/testdata/input/huge-over-days-2.pings" & pids+=($!)
 test-cli 0 rawdata "$ROOT/graph/data/testdata/input/huge-over-days-2.pings" & pids+=($!)
+test-cli 0 rawdata -format json "$ROOT/graph/data/testdata/input/huge-over-days-2.pings" & pids+=($!)
+test-cli 0 rawdata -format ndjson "$ROOT/graph/data/testdata/input/huge-over-days-2.pings" & pids+=($!)
+MERGE_OUT=$(mktemp -u --suffix=.pings)
+test-cli 0 merge -out "$MERGE_OUT" "$ROOT/graph/data/testdata/input/medium-hour-gaps.pings" "$ROOT/graph/data/testdata/input/medium-minute-gaps.pings" & pids+=($!)
+IMPORT_OUT=$(mktemp -u --suffix=.pings)
+test-cli 0 import -out "$IMPORT_OUT" "$ROOT/cmd/subcommands/importer/testdata/iputils.txt" & pids+=($!)
+SLICE_OUT=$(mktemp -u --suffix=.pings)
+test-cli 0 slice -from +1m -to -1m -out "$SLICE_OUT" "$ROOT/graph/data/testdata/input/medium-hour-gaps.pings" & pids+=($!)
 if [[ $SHOULD_TEST_NETWORK == "1" ]]; then
 	test-cli 0 ping -n 1 & pids+=($!)
 fi
@@ -55,6 +63,8 @@ for pid in "${pids[@]}"; do
     fi
 done
 
+rm -f "$MERGE_OUT" "$SLICE_OUT" "$IMPORT_OUT"
+
 if [[ $exitCode != 0 ]]; then
     find "$ROOT"/tools/ -name '*.log' -exec cat {} ';'
 fi
@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -72,3 +72,32 @@ func FanInFanOut[T any](ctx context.Context, c <-chan T, channelSize, fanOutCoun
 	}
 	return result
 }
+
+// Concat returns a channel which first receives every value from [first] until it is closed, then every value
+// from [second]. The returned channel is closed when [second] is closed or the [ctx] is done.
+func Concat[T any](ctx context.Context, first, second <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, c := range []<-chan T{first, second} {
+			for {
+				var v T
+				var ok bool
+				select {
+				case <-ctx.Done():
+					return
+				case v, ok = <-c:
+				}
+				if !ok {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,65 @@
+// Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
+//
+// Copyright 2026 Lexer747
+//
+// SPDX-License-Identifier: GPL-2.0-only
+
+package th
+
+import (
+	"bytes"
+	"net"
+	"time"
+
+	"github.com/Lexer747/acci-ping/graph/data"
+	"github.com/Lexer747/acci-ping/ping"
+	"gotest.tools/v3/assert"
+)
+
+// PingsURL is the url of the data made by [MakeData].
+const PingsURL = "www.google.com"
+
+// PingsStart is the usual start of the points made by [Pings].
+var PingsStart = time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)
+
+// PingsIP is the IP of every point made by [Pings].
+var PingsIP = net.IPv4(142, 250, 179, 228)
+
+// Pings makes [n] good 8ms points [every] apart from [start]. If [change] isn't nil it's called with each point
+// so that a test can shape the points (e.g. dropping some of them).
+func Pings(start time.Time, n int, every time.Duration, change func(i int, p *ping.PingResults)) []ping.PingResults {
+	ret := make([]ping.PingResults, n)
+	for i := range ret {
+		ret[i] = ping.PingResults{
+			Data: ping.PingDataPoint{Timestamp: start.Add(time.Duration(i) * every), Duration: 8 * time.Millisecond},
+			IP:   PingsIP,
+		}
+		if change != nil {
+			change(i, &ret[i])
+		}
+	}
+	return ret
+}
+
+// MakeData returns the data of the [PingsURL] with every one of the [points] added.
+func MakeData(points []ping.PingResults) *data.Data {
+	d := data.NewData(PingsURL)
+	for _, p := range points {
+		d.AddPoint(p)
+	}
+	return d
+}
+
+// WritePingsFile returns the '.pings' file of the [MakeData] of the [points].
+func WritePingsFile(t T, points []ping.PingResults) []byte {
+	t.Helper()
+	return WriteData(t, MakeData(points))
+}
+
+// WriteData returns the '.pings' file of [d].
+func WriteData(t T, d *data.Data) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	assert.NilError(t, d.AsCompact(&b))
+	return b.Bytes()
+}
@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -8,8 +8,10 @@ package timeutils
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Lexer747/acci-ping/utils/errors"
 	"github.com/Lexer747/acci-ping/utils/numeric"
 )
 
@@ -30,3 +32,44 @@ func TimeDateFormat(t time.Time) string {
 		t.Year(), t.Month().String(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location().String(),
 	)
 }
+
+// absoluteFormats are the layouts accepted by [ParseTimeOrOffset] for absolute times, in the order they are
+// tried.
+var absoluteFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTimeOrOffset parses either an absolute time or a time relative to a span of time [start, end].
+//
+//   - Absolute times are either [time.RFC3339Nano] or a date and optional time e.g. "2024-08-02 20:40", these
+//     are parsed in the [loc] timezone unless the input includes its own offset.
+//   - Relative times are a [time.ParseDuration] string starting with a sign, a "+" means the offset is from
+//     [start] and a "-" means the offset is backwards from [end]. e.g. "-20m" is twenty minutes before [end].
+func ParseTimeOrOffset(s string, start, end time.Time, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		offset, err := time.ParseDuration(s[1:])
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "cannot parse %q as a relative time", s)
+		}
+		if s[0] == '+' {
+			return start.Add(offset), nil
+		}
+		return end.Add(-offset), nil
+	}
+	for _, layout := range absoluteFormats {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf(
+		"cannot parse %q as a time, expected a relative time like \"-20m\" or \"+1h\", or an absolute time like %q",
+		s, "2006-01-02 15:04:05",
+	)
+}
@@ -1,6 +1,6 @@
 // Use of this source code is governed by a GPL-2 license that can be found in the LICENSE file.
 //
-// Copyright 2024-2025 Lexer747
+// Copyright 2024-2026 Lexer747
 //
 // SPDX-License-Identifier: GPL-2.0-only
 
@@ -8,6 +8,7 @@ package timeutils_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Lexer747/acci-ping/utils/timeutils"
 	"gotest.tools/v3/assert"
@@ -31,3 +32,30 @@ func TestBug(t *testing.T) {
 	// Previous versions of this code would result in "129.399999ms"
 	assert.Check(t, is.Equal("129.3ms", timeutils.HumanString(129379939, 4)))
 }
+
+func TestParseTimeOrOffset(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.August, 2, 20, 0, 0, 0, time.UTC)
+	end := time.Date(2024, time.August, 2, 22, 0, 0, 0, time.UTC)
+	cases := []struct {
+		expected time.Time
+		input    string
+	}{
+		{input: "+20m", expected: start.Add(20 * time.Minute)},
+		{input: "-20m", expected: end.Add(-20 * time.Minute)},
+		{input: "-1h30m", expected: end.Add(-90 * time.Minute)},
+		{input: "2024-08-02T21:04:27.56Z", expected: time.Date(2024, time.August, 2, 21, 4, 27, 560_000_000, time.UTC)},
+		{input: "2024-08-02 21:04:27", expected: time.Date(2024, time.August, 2, 21, 4, 27, 0, time.UTC)},
+		{input: " 2024-08-02 21:04 ", expected: time.Date(2024, time.August, 2, 21, 4, 0, 0, time.UTC)},
+		{input: "2024-08-03", expected: time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		actual, err := timeutils.ParseTimeOrOffset(c.input, start, end, time.UTC)
+		assert.NilError(t, err, c.input)
+		assert.Check(t, actual.Equal(c.expected), "%q: %s != %s", c.input, actual, c.expected)
+	}
+	for _, bad := range []string{"", "yesterday", "+soon", "21:04"} {
+		_, err := timeutils.ParseTimeOrOffset(bad, start, end, time.UTC)
+		assert.Check(t, err != nil, bad)
+	}
+}